@@ -11,9 +11,17 @@ import (
 	"time"
 
 	"github.com/sistemica/traefik-manager/internal/api/server"
+	"github.com/sistemica/traefik-manager/internal/apikeys"
+	"github.com/sistemica/traefik-manager/internal/audit"
 	"github.com/sistemica/traefik-manager/internal/config"
 	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/oidc"
+	"github.com/sistemica/traefik-manager/internal/prober"
+	"github.com/sistemica/traefik-manager/internal/reconcile"
 	"github.com/sistemica/traefik-manager/internal/store"
+	"github.com/sistemica/traefik-manager/internal/templates"
+	"github.com/sistemica/traefik-manager/internal/tlsconfig"
+	"github.com/sistemica/traefik-manager/internal/webhooks"
 )
 
 func main() {
@@ -42,8 +50,114 @@ func main() {
 		logger.Fatal().Err(err).Str("path", cfg.Storage.FilePath).Msg("Failed to initialize store")
 	}
 
+	// Initialize environment stores
+	environments := store.NewEnvironments(dataStore)
+	for _, env := range cfg.Environments {
+		envStore, err := store.NewFileStore(env.StorageFilePath)
+		if err != nil {
+			logger.Fatal().Err(err).Str("environment", env.Name).Str("path", env.StorageFilePath).Msg("Failed to initialize environment store")
+		}
+		if err := environments.Add(env.Name, envStore); err != nil {
+			logger.Fatal().Err(err).Str("environment", env.Name).Msg("Failed to register environment")
+		}
+	}
+
 	// Initialize and setup server
 	server := server.New(cfg, dataStore)
+	if len(cfg.Environments) > 0 {
+		server.SetEnvironments(environments)
+	}
+
+	// Initialize managed API keys
+	if cfg.Auth.Enabled {
+		apiKeys, err := apikeys.NewStore(cfg.Auth.KeysFilePath)
+		if err != nil {
+			logger.Fatal().Err(err).Str("path", cfg.Auth.KeysFilePath).Msg("Failed to initialize API keys")
+		}
+		server.SetAPIKeys(apiKeys)
+	}
+
+	// Initialize audit log
+	var auditLog *audit.Log
+	if cfg.Audit.Enabled {
+		auditLog, err = audit.NewLog(cfg.Audit.FilePath, audit.Options{
+			MaxSize:    int64(cfg.Audit.MaxSizeMB) << 20,
+			MaxBackups: cfg.Audit.MaxBackups,
+			MaxAge:     cfg.Audit.MaxAge,
+		})
+		if err != nil {
+			logger.Fatal().Err(err).Str("path", cfg.Audit.FilePath).Msg("Failed to initialize audit log")
+		}
+		server.SetAuditLog(auditLog)
+	}
+
+	// Initialize OIDC bearer token authentication
+	if cfg.Auth.OIDC != nil {
+		authenticator, err := oidc.NewAuthenticator(*cfg.Auth.OIDC)
+		if err != nil {
+			logger.Fatal().Err(err).Str("jwks", cfg.Auth.OIDC.JWKS).Msg("Failed to initialize OIDC authentication")
+		}
+		server.SetBearerAuthenticator(authenticator)
+	}
+
+	// Initialize TLS certificates
+	var tlsReloader *tlsconfig.Reloader
+	if tlsConfig := cfg.Server.TLS; tlsConfig != nil {
+		tlsReloader, err = tlsconfig.NewReloader(tlsConfig.CertFile, tlsConfig.KeyFile, tlsConfig.ClientCAFile)
+		if err != nil {
+			logger.Fatal().Err(err).Str("cert", tlsConfig.CertFile).Msg("Failed to load TLS certificates")
+		}
+		server.SetTLSReloader(tlsReloader)
+	}
+
+	// Initialize reconciliation against Traefik's API
+	var reconciler *reconcile.Reconciler
+	if cfg.Reconcile != nil {
+		reconciler = reconcile.NewReconciler(cfg.Reconcile.Instances, cfg.Reconcile.Timeout)
+		server.SetReconciler(reconciler)
+	}
+
+	// Initialize health probing of backend servers
+	var healthProber *prober.Prober
+	if cfg.HealthProbe.Enabled {
+		healthProber = prober.NewProber(dataStore, prober.Options{
+			HistorySize: cfg.HealthProbe.HistorySize,
+		})
+		server.SetProber(healthProber)
+	}
+
+	// Initialize outgoing webhooks notified of the changes of every environment
+	var webhookDispatcher *webhooks.Dispatcher
+	if cfg.Webhooks.Enabled {
+		webhookStore, err := webhooks.NewStore(cfg.Webhooks.FilePath)
+		if err != nil {
+			logger.Fatal().Err(err).Str("path", cfg.Webhooks.FilePath).Msg("Failed to initialize webhooks")
+		}
+		webhookDispatcher = webhooks.NewDispatcher(webhookStore, webhooks.Options{
+			MaxAttempts:    cfg.Webhooks.MaxAttempts,
+			InitialBackoff: cfg.Webhooks.InitialBackoff,
+			MaxBackoff:     cfg.Webhooks.MaxBackoff,
+			Timeout:        cfg.Webhooks.Timeout,
+			HistorySize:    cfg.Webhooks.HistorySize,
+			DeadLetterSize: cfg.Webhooks.DeadLetterSize,
+		})
+		for _, name := range environments.Names() {
+			envStore, _ := environments.Get(name)
+			if notifier, ok := envStore.(store.ChangeNotifier); ok {
+				notifier.OnChange(webhookDispatcher.Notify(name))
+			}
+		}
+		server.SetWebhooks(webhookDispatcher)
+	}
+
+	// Initialize templates instantiated as apps
+	if cfg.Templates.Enabled {
+		templateStore, err := templates.NewStore(cfg.Templates.FilePath)
+		if err != nil {
+			logger.Fatal().Err(err).Str("path", cfg.Templates.FilePath).Msg("Failed to initialize templates")
+		}
+		server.SetTemplates(templateStore)
+	}
 	server.Setup()
 
 	// Start server in a goroutine
@@ -62,7 +176,7 @@ func main() {
 			for {
 				select {
 				case <-ticker.C:
-					if err := dataStore.Save(); err != nil {
+					if err := environments.Save(); err != nil {
 						logger.Error().Err(err).Msg("Failed to auto-save store data")
 					} else {
 						logger.Debug().Msg("Store data auto-saved")
@@ -72,10 +186,50 @@ func main() {
 		}()
 	}
 
+	// Reload TLS certificates when the files change or on SIGHUP
+	watchDone := make(chan struct{})
+	if tlsReloader != nil {
+		if cfg.Server.TLS.ReloadInterval > 0 {
+			go tlsReloader.Watch(cfg.Server.TLS.ReloadInterval, watchDone)
+		}
+
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go func() {
+			for {
+				select {
+				case <-hup:
+					logger.Info().Msg("Received SIGHUP, reloading TLS certificates")
+					if err := tlsReloader.Reload(); err != nil {
+						logger.Error().Err(err).Msg("Failed to reload TLS certificates")
+					}
+				case <-watchDone:
+					return
+				}
+			}
+		}()
+	}
+
+	// Compare the stored configuration with the state reported by Traefik
+	if reconciler != nil {
+		go reconciler.Run(cfg.Reconcile.Interval, watchDone)
+	}
+
+	// Check backend servers with their services' health check settings
+	if healthProber != nil {
+		go healthProber.Run(watchDone)
+	}
+
+	// Deliver queued webhook notifications
+	if webhookDispatcher != nil {
+		go webhookDispatcher.Run(watchDone)
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
+	close(watchDone)
 
 	// Create shutdown context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -88,9 +242,15 @@ func main() {
 
 	// Save store data
 	logger.Info().Msg("Saving store data")
-	if err := dataStore.Save(); err != nil {
+	if err := environments.Save(); err != nil {
 		logger.Error().Err(err).Msg("Failed to save store data")
 	}
 
+	if auditLog != nil {
+		if err := auditLog.Close(); err != nil {
+			logger.Error().Err(err).Msg("Failed to close audit log")
+		}
+	}
+
 	logger.Info().Msg("Server stopped")
 }
@@ -0,0 +1,178 @@
+// internal/api/handlers/apikey.go
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/apikeys"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	customMiddleware "github.com/sistemica/traefik-manager/internal/middleware"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// APIKeyHandler handles API key management requests
+type APIKeyHandler struct {
+	Keys *apikeys.Store
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler
+func NewAPIKeyHandler(keys *apikeys.Store) *APIKeyHandler {
+	return &APIKeyHandler{
+		Keys: keys,
+	}
+}
+
+// List handles the GET /apikeys endpoint to list all API keys
+func (h *APIKeyHandler) List(c echo.Context) error {
+	logger.Debug().Msg("Listing API keys")
+
+	return c.JSON(http.StatusOK, h.Keys.List())
+}
+
+// Get handles the GET /apikeys/:name endpoint to get a specific API key
+func (h *APIKeyHandler) Get(c echo.Context) error {
+	name := c.Param("name")
+	logger.Debug().Str("name", name).Msg("Getting API key")
+
+	key, err := h.Keys.Get(name)
+	if err != nil {
+		if store.IsNotFound(err) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "API key not found",
+			})
+		}
+		logger.Error().Err(err).Str("name", name).Msg("Failed to get API key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to get API key",
+		})
+	}
+
+	return c.JSON(http.StatusOK, key)
+}
+
+// Create handles the POST /apikeys endpoint to create a new API key.
+// The generated key is only returned in this response.
+func (h *APIKeyHandler) Create(c echo.Context) error {
+	logger.Debug().Msg("Creating API key")
+
+	var key models.APIKey
+	if err := c.Bind(&key); err != nil {
+		logger.Warn().Err(err).Msg("Invalid API key data")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid API key data",
+		})
+	}
+
+	if key.Name == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "API key name is required",
+		})
+	}
+
+	if !models.ValidRole(key.Role) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid role, expected one of: reader, editor, admin, provider-only",
+		})
+	}
+
+	if strings.Contains(key.Namespace, store.NamespaceSeparator) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid namespace: " + key.Namespace,
+		})
+	}
+
+	for _, scope := range key.Scopes {
+		switch scope.ResourceType {
+		case "routers", "services", "middlewares", "*":
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid scope resource type: " + scope.ResourceType,
+			})
+		}
+	}
+
+	// Admin keys manage keys, the audit log and webhooks of all namespaces and can't be restricted
+	if key.Role == models.RoleAdmin && (key.Namespace != "" || len(key.Scopes) > 0) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Admin keys can't be bound to a namespace or limited by scopes",
+		})
+	}
+
+	if identity := customMiddleware.GetIdentity(c); identity != nil && broaderThan(&key, identity) {
+		logger.Warn().Str("name", key.Name).Str("caller", identity.Name).Msg("API key broader than its creator")
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "API key can't grant more than the key creating it",
+		})
+	}
+
+	secret, err := h.Keys.Create(&key)
+	if err != nil {
+		if store.IsAlreadyExists(err) {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": "API key already exists",
+			})
+		}
+		logger.Error().Err(err).Str("name", key.Name).Msg("Failed to create API key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create API key",
+		})
+	}
+
+	logger.Info().Str("name", key.Name).Str("role", key.Role).Msg("API key created")
+
+	return c.JSON(http.StatusCreated, models.APIKeyCreated{
+		APIKey: key,
+		Key:    secret,
+	})
+}
+
+// Delete handles the DELETE /apikeys/:name endpoint to revoke an API key
+func (h *APIKeyHandler) Delete(c echo.Context) error {
+	name := c.Param("name")
+	logger.Debug().Str("name", name).Msg("Deleting API key")
+
+	if err := h.Keys.Delete(name); err != nil {
+		if store.IsNotFound(err) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "API key not found",
+			})
+		}
+		logger.Error().Err(err).Str("name", name).Msg("Failed to delete API key")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to delete API key",
+		})
+	}
+
+	logger.Info().Str("name", name).Msg("API key deleted")
+
+	return c.JSON(http.StatusOK, models.ResourceResponse{
+		ID:      name,
+		Deleted: true,
+	})
+}
+
+// broaderThan returns true if the API key grants access the identity doesn't have: another role
+// than a non-admin identity, another namespace, or scopes the identity isn't limited to
+func broaderThan(key *models.APIKey, identity *customMiddleware.Identity) bool {
+	if identity.Role != models.RoleAdmin && key.Role != identity.Role {
+		return true
+	}
+	if identity.Namespace != "" && key.Namespace != identity.Namespace {
+		return true
+	}
+	if len(identity.Scopes) > 0 {
+		if len(key.Scopes) == 0 {
+			return true
+		}
+		for _, scope := range key.Scopes {
+			if !slices.Contains(identity.Scopes, scope) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/apikeys"
+	customMiddleware "github.com/sistemica/traefik-manager/internal/middleware"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// TestAPIKeyCreate tests that created API keys are never broader than the key creating them
+func TestAPIKeyCreate(t *testing.T) {
+	e := echo.New()
+
+	keys, err := apikeys.NewStore(filepath.Join(t.TempDir(), "apikeys.json"))
+	if err != nil {
+		t.Fatalf("Failed to create key store: %v", err)
+	}
+	handler := NewAPIKeyHandler(keys)
+
+	admin := &customMiddleware.Identity{Name: "admin", Role: models.RoleAdmin}
+	namespaceAdmin := &customMiddleware.Identity{Name: "namespace-admin", Role: models.RoleAdmin, Namespace: "team-a"}
+	scopedAdmin := &customMiddleware.Identity{Name: "scoped-admin", Role: models.RoleAdmin, Scopes: []models.APIKeyScope{
+		{ResourceType: "routers", IDPattern: "team-a-*"},
+	}}
+
+	tests := []struct {
+		name     string
+		identity *customMiddleware.Identity
+		body     string
+		expected int
+	}{
+		{"Admin Creates Admin", admin, `{"name":"root","role":"admin"}`, http.StatusCreated},
+		{"Namespaced Admin Key", admin, `{"name":"team-a-admin","role":"admin","namespace":"team-a"}`, http.StatusBadRequest},
+		{"Scoped Admin Key", admin, `{"name":"routers-admin","role":"admin","scopes":[{"resourceType":"routers"}]}`, http.StatusBadRequest},
+		{"Namespace Admin Creates Unbound Key", namespaceAdmin, `{"name":"unbound","role":"editor"}`, http.StatusForbidden},
+		{"Namespace Admin Creates Bound Key", namespaceAdmin, `{"name":"bound","role":"editor","namespace":"team-a"}`, http.StatusCreated},
+		{"Scoped Admin Creates Unscoped Key", scopedAdmin, `{"name":"unscoped","role":"editor"}`, http.StatusForbidden},
+		{"Scoped Admin Creates Other Scope", scopedAdmin, `{"name":"other","role":"editor","scopes":[{"resourceType":"routers","idPattern":"*"}]}`, http.StatusForbidden},
+		{"Scoped Admin Creates Same Scope", scopedAdmin, `{"name":"same","role":"editor","scopes":[{"resourceType":"routers","idPattern":"team-a-*"}]}`, http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/apikeys", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			customMiddleware.SetIdentity(c, tt.identity)
+
+			if err := handler.Create(c); err != nil {
+				t.Fatalf("Handler returned error: %v", err)
+			}
+			if rec.Code != tt.expected {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// internal/api/handlers/app.go
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// appRequest is the body of app create requests. Routers take the same form as in POST /routers.
+type appRequest struct {
+	ID          string                   `json:"id"`
+	Description string                   `json:"description"`
+	Routers     []map[string]interface{} `json:"routers"`
+	Services    []models.Service         `json:"services"`
+	Middlewares []models.Middleware      `json:"middlewares"`
+}
+
+// AppHandler handles app-related requests
+type AppHandler struct {
+	BaseHandler
+}
+
+// NewAppHandler creates a new AppHandler
+func NewAppHandler(store store.Store) *AppHandler {
+	return &AppHandler{
+		BaseHandler: BaseHandler{Store: store},
+	}
+}
+
+// List handles the GET /apps endpoint to list all apps with their components.
+// Apps can be filtered by updatedSince and are paginated with limit and cursor.
+func (h *AppHandler) List(c echo.Context) error {
+	logger.Debug().Msg("Listing apps")
+
+	options, err := parseListOptions(c)
+	if err != nil {
+		return listError(c, err)
+	}
+
+	apps, err := h.StoreFor(c).ListApps()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to list apps")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to list apps",
+		})
+	}
+
+	filtered := make([]models.App, 0, len(apps))
+	for _, app := range apps {
+		if options.matches(app.ResourceMeta) {
+			filtered = append(filtered, app)
+		}
+	}
+
+	return c.JSON(http.StatusOK, paginate(c, filtered, options, func(app models.App) string {
+		return options.key(app.ID, app.ResourceMeta)
+	}))
+}
+
+// Get handles the GET /apps/:id endpoint to get an app with its routers, services and middlewares
+func (h *AppHandler) Get(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Getting app")
+
+	app, err := h.StoreFor(c).GetApp(id)
+	if err != nil {
+		if store.IsNotFound(err) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "App not found",
+			})
+		}
+		logger.Error().Err(err).Str("id", id).Msg("Failed to get app")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to get app",
+		})
+	}
+
+	return c.JSON(http.StatusOK, app)
+}
+
+// Create handles the POST /apps endpoint to create an app with all its components at once.
+// Nothing is created if one of the components is invalid or already exists.
+func (h *AppHandler) Create(c echo.Context) error {
+	logger.Debug().Msg("Creating app")
+
+	var request appRequest
+	if err := c.Bind(&request); err != nil {
+		logger.Warn().Err(err).Msg("Invalid app data")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid app data",
+		})
+	}
+
+	app, err := parseApp(request)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Invalid app")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.StoreFor(c).CreateApp(&app); err != nil {
+		logger.Warn().Err(err).Str("id", app.ID).Msg("Failed to create app")
+		if store.IsAlreadyExists(err) {
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	logger.Info().Str("id", app.ID).Int("routers", len(app.Routers)).Int("services", len(app.Services)).
+		Int("middlewares", len(app.Middlewares)).Msg("App created")
+
+	return c.JSON(http.StatusCreated, models.ResourceResponse{
+		ID:      app.ID,
+		Created: true,
+	})
+}
+
+// Delete handles the DELETE /apps/:id endpoint to delete an app. Its components are deleted
+// with it, except those shared with other apps or still used by other resources.
+func (h *AppHandler) Delete(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Deleting app")
+
+	response, err := h.StoreFor(c).DeleteApp(id)
+	if err != nil {
+		if store.IsNotFound(err) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "App not found",
+			})
+		}
+		logger.Error().Err(err).Str("id", id).Msg("Failed to delete app")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to delete app",
+		})
+	}
+
+	logger.Info().Str("id", id).Strs("kept_services", response.Kept.Services).
+		Strs("kept_middlewares", response.Kept.Middlewares).Msg("App deleted")
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// parseApp converts and validates an app create request
+func parseApp(request appRequest) (models.App, error) {
+	app := models.App{
+		ID:          request.ID,
+		Description: request.Description,
+		Services:    request.Services,
+		Middlewares: request.Middlewares,
+	}
+
+	if app.ID == "" {
+		return app, errors.New("App ID is required")
+	}
+	if len(request.Routers) == 0 {
+		return app, errors.New("An app requires at least one router")
+	}
+
+	for _, middleware := range app.Middlewares {
+		if middleware.ID == "" {
+			return app, errors.New("Middleware ID is required")
+		}
+		if middleware.Type == "" {
+			return app, fmt.Errorf("middleware %s: Middleware type is required", middleware.ID)
+		}
+	}
+
+	for i := range app.Services {
+		service := &app.Services[i]
+		if service.ID == "" {
+			return app, errors.New("Service ID is required")
+		}
+		if err := validateServiceConfiguration(service); err != nil {
+			return app, fmt.Errorf("service %s: %w", service.ID, err)
+		}
+	}
+
+	for _, requestData := range request.Routers {
+		router := parseRouter(requestData)
+		switch {
+		case router.ID == "":
+			return app, errors.New("Router ID is required")
+		case router.Rule == "":
+			return app, fmt.Errorf("router %s: Router rule is required", router.ID)
+		case router.Service.ID == "":
+			return app, fmt.Errorf("router %s: Router service ID is required", router.ID)
+		}
+		app.Routers = append(app.Routers, router)
+	}
+
+	return app, nil
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// TestAppHandler tests creating, getting and deleting apps
+func TestAppHandler(t *testing.T) {
+	e := echo.New()
+
+	fs, err := store.NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	handler := NewAppHandler(fs)
+
+	request := func(handlerFunc echo.HandlerFunc, method, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		if err := handlerFunc(c); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		return rec
+	}
+
+	shop := `{
+		"id": "shop",
+		"routers": [{"id": "shop", "rule": "Host(` + "`shop.example.com`" + `)", "service": "shop", "middlewares": ["auth"]}],
+		"services": [{"id": "shop", "url": "http://shop:8080"}],
+		"middlewares": [{"id": "auth", "type": "basicAuth", "config": {"users": ["admin:x"]}}]
+	}`
+
+	t.Run("Create And Get", func(t *testing.T) {
+		if rec := request(handler.Create, http.MethodPost, "", shop); rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+		}
+
+		rec := request(handler.Get, http.MethodGet, "shop", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var app models.App
+		if err := json.Unmarshal(rec.Body.Bytes(), &app); err != nil {
+			t.Fatalf("Failed to parse app: %v", err)
+		}
+		if len(app.Routers) != 1 || app.Routers[0].Middlewares[0].ID != "auth" || len(app.Services) != 1 || len(app.Middlewares) != 1 {
+			t.Fatalf("Expected the whole bundle, got %+v", app)
+		}
+	})
+
+	t.Run("Invalid Apps", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			body     string
+			expected int
+		}{
+			{"No Routers", `{"id":"empty"}`, http.StatusBadRequest},
+			{"Router Without Rule", `{"id":"x","routers":[{"id":"x","service":"shop"}]}`, http.StatusBadRequest},
+			{"Invalid Service", `{"id":"x","routers":[{"id":"x","rule":"Path(` + "`/`" + `)","service":"x"}],"services":[{"id":"x"}]}`, http.StatusBadRequest},
+			{"Existing App", shop, http.StatusConflict},
+			{"Existing Component", strings.Replace(shop, `"id": "shop",`, `"id": "other",`, 1), http.StatusConflict},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if rec := request(handler.Create, http.MethodPost, "", tt.body); rec.Code != tt.expected {
+					t.Fatalf("Expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+				}
+			})
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		rec := request(handler.Delete, http.MethodDelete, "shop", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var response models.AppDeleteResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if len(response.Deleted.Routers) != 1 || len(response.Deleted.Services) != 1 || len(response.Deleted.Middlewares) != 1 {
+			t.Fatalf("Expected all components to be deleted, got %+v", response)
+		}
+
+		if rec := request(handler.Get, http.MethodGet, "shop", ""); rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+		}
+		if rec := request(handler.Delete, http.MethodDelete, "shop", ""); rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+		}
+	})
+}
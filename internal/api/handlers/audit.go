@@ -0,0 +1,77 @@
+// internal/api/handlers/audit.go
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/audit"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// defaultAuditLimit is the number of audit entries returned when no limit is given
+const defaultAuditLimit = 100
+
+// AuditHandler handles audit log queries
+type AuditHandler struct {
+	Log *audit.Log
+}
+
+// NewAuditHandler creates a new AuditHandler
+func NewAuditHandler(log *audit.Log) *AuditHandler {
+	return &AuditHandler{
+		Log: log,
+	}
+}
+
+// Query handles the GET /audit endpoint to query the audit log.
+// Supported filters are actor, resourceType, resourceId, method, outcome,
+// since and until (RFC 3339) and limit.
+func (h *AuditHandler) Query(c echo.Context) error {
+	query := models.AuditQuery{
+		Actor:        c.QueryParam("actor"),
+		ResourceType: c.QueryParam("resourceType"),
+		ResourceID:   c.QueryParam("resourceId"),
+		Method:       strings.ToUpper(c.QueryParam("method")),
+		Outcome:      c.QueryParam("outcome"),
+		Limit:        defaultAuditLimit,
+	}
+
+	for param, target := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		if value := c.QueryParam(param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Invalid " + param + " parameter, expected RFC 3339 time",
+				})
+			}
+			*target = parsed
+		}
+	}
+
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid limit parameter",
+			})
+		}
+		query.Limit = limit
+	}
+
+	logger.Debug().Interface("query", query).Msg("Querying audit log")
+
+	entries, err := h.Log.Query(query)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to query audit log")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to query audit log",
+		})
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
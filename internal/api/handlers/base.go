@@ -2,9 +2,15 @@
 package handlers
 
 import (
+	"github.com/labstack/echo/v4"
+	customMiddleware "github.com/sistemica/traefik-manager/internal/middleware"
+	"github.com/sistemica/traefik-manager/internal/models"
 	"github.com/sistemica/traefik-manager/internal/store"
 )
 
+// NamespaceHeader lets callers without a namespace-bound key act within a namespace
+const NamespaceHeader = "X-Namespace"
+
 // BaseHandler contains common dependencies for all handlers
 type BaseHandler struct {
 	Store store.Store
@@ -16,3 +22,40 @@ func NewBaseHandler(store store.Store) BaseHandler {
 		Store: store,
 	}
 }
+
+// StoreFor returns the store as seen by the caller of the request.
+// Callers bound to a namespace only see that namespace; other callers
+// may select a namespace with the X-Namespace header.
+// Changes made through the returned store are attributed to the caller.
+func (h BaseHandler) StoreFor(c echo.Context) store.Store {
+	return h.Store.WithActor(requestActor(c)).Namespace(requestNamespace(c))
+}
+
+// requestActor returns the name of the authenticated caller, empty if authentication is disabled
+func requestActor(c echo.Context) string {
+	if identity := customMiddleware.GetIdentity(c); identity != nil {
+		return identity.Name
+	}
+	return ""
+}
+
+// requestNamespace returns the namespace the request operates in
+func requestNamespace(c echo.Context) string {
+	if identity := customMiddleware.GetIdentity(c); identity != nil && identity.Namespace != "" {
+		return identity.Namespace
+	}
+	return c.Request().Header.Get(NamespaceHeader)
+}
+
+// requestAuthorizer returns the authorizer limiting the resources a request may change to the
+// scopes of the caller, nil if the caller isn't limited by scopes
+func requestAuthorizer(c echo.Context) store.Authorizer {
+	identity := customMiddleware.GetIdentity(c)
+	if identity == nil || len(identity.Scopes) == 0 {
+		return nil
+	}
+	return func(ref models.ResourceRef) bool {
+		// Scopes use the names of the resource endpoints, e.g. "routers" for a router
+		return identity.InScope(ref.ResourceType+"s", ref.ID)
+	}
+}
@@ -0,0 +1,142 @@
+// internal/api/handlers/bulk.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/labels"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// requireSelector parses the selector parameter of a bulk request. An empty selector
+// is rejected so that a missing parameter never deletes everything.
+func requireSelector(c echo.Context) (labels.Selector, error) {
+	selector, err := parseSelector(c)
+	if err != nil {
+		return nil, err
+	}
+	if selector.Empty() {
+		return nil, errors.New("A selector parameter is required")
+	}
+	return selector, nil
+}
+
+// deleteMatching deletes the given resources and reports the ones that could not be deleted,
+// e.g. because they are still in use, without stopping. Resources in use are retried after
+// the others, as they may only be used by resources deleted in the meantime.
+func deleteMatching(ids []string, remove func(id string) error) models.BulkDeleteResponse {
+	sort.Strings(ids)
+	response := models.BulkDeleteResponse{Deleted: []string{}}
+
+	pending := ids
+	for len(pending) > 0 {
+		var failed []models.BulkDeleteFailure
+		for _, id := range pending {
+			if err := remove(id); err != nil {
+				failed = append(failed, models.BulkDeleteFailure{ID: id, Error: err.Error()})
+				continue
+			}
+			response.Deleted = append(response.Deleted, id)
+		}
+
+		if len(failed) == len(pending) {
+			response.Failed = failed
+			break
+		}
+		pending = nil
+		for _, failure := range failed {
+			pending = append(pending, failure.ID)
+		}
+	}
+
+	sort.Strings(response.Deleted)
+	return response
+}
+
+// DeleteBySelector handles the DELETE /routers?selector= endpoint to delete the routers matching a label selector
+func (h *RouterHandler) DeleteBySelector(c echo.Context) error {
+	selector, err := requireSelector(c)
+	if err != nil {
+		return listError(c, err)
+	}
+
+	s := h.StoreFor(c)
+	routers, err := s.ListRouters()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to list routers")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to list routers",
+		})
+	}
+
+	var ids []string
+	for _, router := range routers {
+		if selector.Matches(router.Labels) {
+			ids = append(ids, router.ID)
+		}
+	}
+
+	response := deleteMatching(ids, s.DeleteRouter)
+	logger.Info().Str("selector", c.QueryParam("selector")).Strs("deleted", response.Deleted).Int("failed", len(response.Failed)).Msg("Routers deleted by selector")
+	return c.JSON(http.StatusOK, response)
+}
+
+// DeleteBySelector handles the DELETE /services?selector= endpoint to delete the services matching a label selector
+func (h *ServiceHandler) DeleteBySelector(c echo.Context) error {
+	selector, err := requireSelector(c)
+	if err != nil {
+		return listError(c, err)
+	}
+
+	s := h.StoreFor(c)
+	services, err := s.ListServices()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to list services")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to list services",
+		})
+	}
+
+	var ids []string
+	for _, service := range services {
+		if selector.Matches(service.Labels) {
+			ids = append(ids, service.ID)
+		}
+	}
+
+	response := deleteMatching(ids, s.DeleteService)
+	logger.Info().Str("selector", c.QueryParam("selector")).Strs("deleted", response.Deleted).Int("failed", len(response.Failed)).Msg("Services deleted by selector")
+	return c.JSON(http.StatusOK, response)
+}
+
+// DeleteBySelector handles the DELETE /middlewares?selector= endpoint to delete the middlewares matching a label selector
+func (h *MiddlewareHandler) DeleteBySelector(c echo.Context) error {
+	selector, err := requireSelector(c)
+	if err != nil {
+		return listError(c, err)
+	}
+
+	s := h.StoreFor(c)
+	middlewares, err := s.ListMiddlewares()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to list middlewares")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to list middlewares",
+		})
+	}
+
+	var ids []string
+	for _, middleware := range middlewares {
+		if selector.Matches(middleware.Labels) {
+			ids = append(ids, middleware.ID)
+		}
+	}
+
+	response := deleteMatching(ids, s.DeleteMiddleware)
+	logger.Info().Str("selector", c.QueryParam("selector")).Strs("deleted", response.Deleted).Int("failed", len(response.Failed)).Msg("Middlewares deleted by selector")
+	return c.JSON(http.StatusOK, response)
+}
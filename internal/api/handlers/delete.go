@@ -0,0 +1,104 @@
+// internal/api/handlers/delete.go
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// parseDeleteOptions parses the optional cascade and dryRun parameters of a delete request.
+// The resources deleted with cascade are limited to the scopes of the caller.
+func parseDeleteOptions(c echo.Context) (store.DeleteOptions, error) {
+	var options store.DeleteOptions
+	cascade, err := parseBoolFilter(c, "cascade")
+	if err != nil {
+		return options, err
+	}
+	dryRun, err := parseBoolFilter(c, "dryRun")
+	if err != nil {
+		return options, err
+	}
+	options.Cascade = cascade != nil && *cascade
+	options.DryRun = dryRun != nil && *dryRun
+	options.Authorize = requestAuthorizer(c)
+	return options, nil
+}
+
+// deleteWithOptions runs a delete with cascade or dry run and writes its response
+func deleteWithOptions(c echo.Context, resourceName, id string, options store.DeleteOptions,
+	remove func(id string, options store.DeleteOptions) ([]models.ResourceRef, error)) error {
+	deleted, err := remove(id, options)
+	if err != nil {
+		logger.Warn().Err(err).Str("id", id).Bool("cascade", options.Cascade).Bool("dry_run", options.DryRun).
+			Msgf("Failed to delete %s", strings.ToLower(resourceName))
+		return deleteError(c, resourceName, id, err)
+	}
+
+	if !options.DryRun {
+		logger.Info().Str("id", id).Int("deleted", len(deleted)).Msgf("%s deleted", resourceName)
+	}
+
+	return c.JSON(http.StatusOK, models.CascadeDeleteResponse{
+		ID:      id,
+		DryRun:  options.DryRun,
+		Deleted: deleted,
+	})
+}
+
+// deleteError writes the response of a failed delete. A resource in use is reported
+// with the resources depending on it.
+func deleteError(c echo.Context, resourceName, id string, err error) error {
+	switch {
+	case store.IsNotFound(err):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": resourceName + " not found",
+		})
+	case store.IsForbidden(err):
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "Deleting " + strings.ToLower(resourceName) + " " + id + " reaches resources outside the scopes of the caller",
+		})
+	case store.IsResourceInUse(err):
+		return c.JSON(http.StatusConflict, inUseResponse(resourceName, store.GetDependencies(err)))
+	}
+
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "Failed to delete " + strings.ToLower(resourceName),
+	})
+}
+
+// inUseResponse returns the body of the conflict reported for a resource in use: the dependencies
+// with the fields holding the references, and the dependents in the "type:id" form as used_by
+func inUseResponse(resourceName string, dependencies []store.Dependency) map[string]interface{} {
+	if dependencies == nil {
+		dependencies = []store.Dependency{}
+	}
+	usedBy := []string{}
+	for _, dependency := range dependencies {
+		user := dependency.ResourceType + ":" + dependency.ID
+		if !slices.Contains(usedBy, user) {
+			usedBy = append(usedBy, user)
+		}
+	}
+
+	return map[string]interface{}{
+		"error":        resourceName + " is in use by other resources and cannot be deleted",
+		"used_by":      usedBy,
+		"dependencies": dependencies,
+	}
+}
+
+// usedByDependencies converts dependents in the "type:id" form of the InUse methods to dependencies
+func usedByDependencies(usedBy []string) []store.Dependency {
+	dependencies := make([]store.Dependency, 0, len(usedBy))
+	for _, user := range usedBy {
+		resourceType, id, _ := strings.Cut(user, ":")
+		dependencies = append(dependencies, store.Dependency{ResourceType: resourceType, ID: id})
+	}
+	return dependencies
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	customMiddleware "github.com/sistemica/traefik-manager/internal/middleware"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// TestDeleteWithOptions tests the dependencies of resources in use and cascading deletes
+func TestDeleteWithOptions(t *testing.T) {
+	e := echo.New()
+
+	fs, err := store.NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	fs.CreateService(&models.Service{ID: "api", URL: "http://api:8080"})
+	fs.CreateRouter(&models.Router{ID: "api", Rule: "Host(`api.example.com`)", Service: models.Service{ID: "api"}})
+	handler := NewServiceHandler(fs)
+
+	deleteService := func(query string, identity ...*customMiddleware.Identity) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/services/api"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if len(identity) > 0 {
+			customMiddleware.SetIdentity(c, identity[0])
+		}
+		c.SetParamNames("id")
+		c.SetParamValues("api")
+		if err := handler.Delete(c); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		return rec
+	}
+
+	t.Run("In Use", func(t *testing.T) {
+		rec := deleteService("")
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("Expected status %d, got %d", http.StatusConflict, rec.Code)
+		}
+		var response struct {
+			UsedBy       []string           `json:"used_by"`
+			Dependencies []store.Dependency `json:"dependencies"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if len(response.Dependencies) != 1 || response.Dependencies[0] != (store.Dependency{ResourceType: "router", ID: "api", Field: "service"}) {
+			t.Fatalf("Expected the router as dependency, got %s", rec.Body.String())
+		}
+		if len(response.UsedBy) != 1 || response.UsedBy[0] != "router:api" {
+			t.Fatalf("Expected the router in used_by, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("Invalid Option", func(t *testing.T) {
+		if rec := deleteService("?cascade=yes"); rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Cascade Out Of Scope", func(t *testing.T) {
+		scoped := &customMiddleware.Identity{Name: "scoped", Role: models.RoleEditor, Scopes: []models.APIKeyScope{
+			{ResourceType: "services", IDPattern: "api"},
+		}}
+		for _, query := range []string{"?cascade=true&dryRun=true", "?cascade=true"} {
+			if rec := deleteService(query, scoped); rec.Code != http.StatusForbidden {
+				t.Fatalf("Expected status %d for %s, got %d: %s", http.StatusForbidden, query, rec.Code, rec.Body.String())
+			}
+		}
+		if exists, _ := fs.RouterExists("api"); !exists {
+			t.Fatal("Expected the router to be kept")
+		}
+	})
+
+	t.Run("Dry Run And Cascade", func(t *testing.T) {
+		for _, query := range []string{"?cascade=true&dryRun=true", "?cascade=true"} {
+			rec := deleteService(query)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status %d for %s, got %d: %s", http.StatusOK, query, rec.Code, rec.Body.String())
+			}
+			var response models.CascadeDeleteResponse
+			json.Unmarshal(rec.Body.Bytes(), &response)
+			if len(response.Deleted) != 2 || response.Deleted[0].ResourceType != "router" {
+				t.Fatalf("Expected the router and the service for %s, got %+v", query, response)
+			}
+		}
+
+		if exists, _ := fs.ServiceExists("api"); exists {
+			t.Fatal("Expected the service to be deleted")
+		}
+		if rec := deleteService("?cascade=true"); rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+		}
+	})
+}
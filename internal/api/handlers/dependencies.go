@@ -0,0 +1,97 @@
+// internal/api/handlers/dependencies.go
+package handlers
+
+import (
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// dependencySet collects services and middlewares together with everything they reference,
+// following router, weighted, mirroring, failover, chain and errors references
+type dependencySet struct {
+	services    []models.Service
+	middlewares []models.Middleware
+
+	servicesByID    map[string]models.Service
+	middlewaresByID map[string]models.Middleware
+
+	selectedServices    map[string]bool
+	selectedMiddlewares map[string]bool
+}
+
+// newDependencySet creates a dependencySet resolving references against the given resources
+func newDependencySet(services []models.Service, middlewares []models.Middleware) *dependencySet {
+	set := &dependencySet{
+		services:            services,
+		middlewares:         middlewares,
+		servicesByID:        make(map[string]models.Service, len(services)),
+		middlewaresByID:     make(map[string]models.Middleware, len(middlewares)),
+		selectedServices:    make(map[string]bool),
+		selectedMiddlewares: make(map[string]bool),
+	}
+
+	for _, svc := range services {
+		set.servicesByID[svc.ID] = svc
+	}
+	for _, mw := range middlewares {
+		set.middlewaresByID[mw.ID] = mw
+	}
+
+	return set
+}
+
+// addRouter adds the service and middlewares referenced by a router
+func (d *dependencySet) addRouter(router models.Router) {
+	d.addService(router.Service.ID)
+	for _, mw := range router.Middlewares {
+		d.addMiddleware(mw.ID)
+	}
+}
+
+// addService adds a service and the services it references
+func (d *dependencySet) addService(id string) {
+	svc, ok := d.servicesByID[id]
+	if !ok || d.selectedServices[id] {
+		return
+	}
+	d.selectedServices[id] = true
+	for _, ref := range svc.ServiceRefs() {
+		d.addService(ref)
+	}
+}
+
+// addMiddleware adds a middleware and the middlewares and services it references
+func (d *dependencySet) addMiddleware(id string) {
+	mw, ok := d.middlewaresByID[id]
+	if !ok || d.selectedMiddlewares[id] {
+		return
+	}
+	d.selectedMiddlewares[id] = true
+	for _, ref := range mw.MiddlewareRefs() {
+		d.addMiddleware(ref)
+	}
+	for _, ref := range mw.ServiceRefs() {
+		d.addService(ref)
+	}
+}
+
+// Services returns the collected services in their original order
+func (d *dependencySet) Services() []models.Service {
+	result := make([]models.Service, 0, len(d.selectedServices))
+	for _, svc := range d.services {
+		if d.selectedServices[svc.ID] {
+			result = append(result, svc)
+		}
+	}
+	return result
+}
+
+// Middlewares returns the collected middlewares in their original order
+func (d *dependencySet) Middlewares() []models.Middleware {
+	result := make([]models.Middleware, 0, len(d.selectedMiddlewares))
+	for _, mw := range d.middlewares {
+		if d.selectedMiddlewares[mw.ID] {
+			result = append(result, mw)
+		}
+	}
+	return result
+}
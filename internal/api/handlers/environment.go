@@ -0,0 +1,340 @@
+// internal/api/handlers/environment.go
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// EnvironmentHandler handles environment listing and promotion requests
+type EnvironmentHandler struct {
+	Environments  *store.Environments
+	ProviderPaths map[string]string
+}
+
+// NewEnvironmentHandler creates a new EnvironmentHandler.
+// providerPaths maps each environment name to its provider endpoint path.
+func NewEnvironmentHandler(environments *store.Environments, providerPaths map[string]string) *EnvironmentHandler {
+	return &EnvironmentHandler{
+		Environments:  environments,
+		ProviderPaths: providerPaths,
+	}
+}
+
+// List handles the GET /environments endpoint to list all environments
+func (h *EnvironmentHandler) List(c echo.Context) error {
+	logger.Debug().Msg("Listing environments")
+
+	names := h.Environments.Names()
+	environments := make([]models.Environment, 0, len(names))
+	for _, name := range names {
+		environments = append(environments, models.Environment{
+			Name:         name,
+			ProviderPath: h.ProviderPaths[name],
+		})
+	}
+
+	return c.JSON(http.StatusOK, environments)
+}
+
+// Promote handles the POST /environments/:from/promote/:to endpoint.
+// It copies the selected resources and their dependencies from one environment to another,
+// returning a diff preview for dry runs and refusing to overwrite differing resources unless asked to.
+func (h *EnvironmentHandler) Promote(c echo.Context) error {
+	from := c.Param("from")
+	to := c.Param("to")
+	logger.Debug().Str("from", from).Str("to", to).Msg("Promoting resources")
+
+	if from == to {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Source and target environments must differ",
+		})
+	}
+
+	source, err := h.Environments.Get(from)
+	if err != nil {
+		logger.Warn().Str("environment", from).Msg("Environment not found")
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Environment not found: " + from,
+		})
+	}
+
+	target, err := h.Environments.Get(to)
+	if err != nil {
+		logger.Warn().Str("environment", to).Msg("Environment not found")
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Environment not found: " + to,
+		})
+	}
+
+	// Namespace-bound callers only promote the resources of their namespace
+	namespace := requestNamespace(c)
+	source = source.Namespace(namespace)
+	target = target.WithActor(requestActor(c)).Namespace(namespace)
+
+	var request models.PromotionRequest
+	if err := c.Bind(&request); err != nil {
+		logger.Warn().Err(err).Msg("Invalid promotion request")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid promotion request",
+		})
+	}
+
+	if c.QueryParam("dryRun") == "true" {
+		request.DryRun = true
+	}
+
+	if !request.All && len(request.Routers) == 0 && len(request.Services) == 0 && len(request.Middlewares) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Select resources to promote or set 'all' to true",
+		})
+	}
+
+	routers, services, middlewares, err := selectPromotion(source, request)
+	if err != nil {
+		if store.IsNotFound(err) {
+			logger.Warn().Err(err).Str("environment", from).Msg("Selected resource not found")
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		logger.Error().Err(err).Str("environment", from).Msg("Failed to read source environment")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to read source environment",
+		})
+	}
+
+	result, err := planPromotion(target, routers, services, middlewares, request.Overwrite)
+	if err != nil {
+		logger.Error().Err(err).Str("environment", to).Msg("Failed to read target environment")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to read target environment",
+		})
+	}
+	result.From = from
+	result.To = to
+	result.DryRun = request.DryRun
+
+	if request.DryRun {
+		return c.JSON(http.StatusOK, result)
+	}
+
+	if len(result.Conflicts) > 0 {
+		logger.Warn().Str("from", from).Str("to", to).Int("conflicts", len(result.Conflicts)).Msg("Promotion has conflicts")
+		return c.JSON(http.StatusConflict, result)
+	}
+
+	if err := applyPromotion(target, routers, services, middlewares, result.Changes); err != nil {
+		logger.Error().Err(err).Str("from", from).Str("to", to).Msg("Failed to apply promotion")
+		switch {
+		case store.IsValidationError(err) || store.IsInvalidID(err):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		case store.IsAlreadyExists(err) || store.IsResourceInUse(err):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to apply promotion",
+		})
+	}
+	result.Applied = true
+
+	logger.Info().Str("from", from).Str("to", to).Int("changes", len(result.Changes)).Msg("Resources promoted")
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// selectPromotion resolves the requested resources and their dependencies in the source environment
+func selectPromotion(source store.Store, request models.PromotionRequest) ([]models.Router, []models.Service, []models.Middleware, error) {
+	routers, err := source.ListRouters()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	services, err := source.ListServices()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	middlewares, err := source.ListMiddlewares()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	deps := newDependencySet(services, middlewares)
+
+	if request.All {
+		for _, svc := range services {
+			deps.addService(svc.ID)
+		}
+		for _, mw := range middlewares {
+			deps.addMiddleware(mw.ID)
+		}
+		return routers, deps.Services(), deps.Middlewares(), nil
+	}
+
+	routersByID := make(map[string]models.Router, len(routers))
+	for _, router := range routers {
+		routersByID[router.ID] = router
+	}
+
+	selectedRouters := make([]models.Router, 0, len(request.Routers))
+	for _, id := range request.Routers {
+		router, ok := routersByID[id]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("router %s: %w", id, store.ErrNotFound)
+		}
+		selectedRouters = append(selectedRouters, router)
+		deps.addRouter(router)
+	}
+
+	for _, id := range request.Services {
+		if _, ok := deps.servicesByID[id]; !ok {
+			return nil, nil, nil, fmt.Errorf("service %s: %w", id, store.ErrNotFound)
+		}
+		deps.addService(id)
+	}
+
+	for _, id := range request.Middlewares {
+		if _, ok := deps.middlewaresByID[id]; !ok {
+			return nil, nil, nil, fmt.Errorf("middleware %s: %w", id, store.ErrNotFound)
+		}
+		deps.addMiddleware(id)
+	}
+
+	return selectedRouters, deps.Services(), deps.Middlewares(), nil
+}
+
+// planPromotion compares the selected resources with the target environment
+func planPromotion(target store.Store, routers []models.Router, services []models.Service, middlewares []models.Middleware, overwrite bool) (*models.PromotionResult, error) {
+	result := &models.PromotionResult{
+		Changes: []models.PromotionChange{},
+	}
+
+	add := func(resourceType, id string, current, promoted interface{}, getErr error) error {
+		change := models.PromotionChange{
+			ResourceType: resourceType,
+			ID:           id,
+			Promoted:     promoted,
+		}
+
+		switch {
+		case store.IsNotFound(getErr):
+			change.Action = models.PromotionCreate
+		case getErr != nil:
+			return getErr
+		case equalResources(current, promoted):
+			change.Action = models.PromotionUnchanged
+		case overwrite:
+			change.Action = models.PromotionUpdate
+			change.Current = current
+		default:
+			change.Action = models.PromotionConflict
+			change.Current = current
+			result.Conflicts = append(result.Conflicts, change)
+		}
+
+		result.Changes = append(result.Changes, change)
+		return nil
+	}
+
+	for _, mw := range middlewares {
+		current, err := target.GetMiddleware(mw.ID)
+		if err := add("middleware", mw.ID, current, &mw, err); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, svc := range services {
+		current, err := target.GetService(svc.ID)
+		if err := add("service", svc.ID, current, &svc, err); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, router := range routers {
+		current, err := target.GetRouter(router.ID)
+		if err := add("router", router.ID, current, &router, err); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// applyPromotion writes the planned changes to the target environment in one transaction,
+// so that a failing change leaves the target environment untouched
+func applyPromotion(target store.Store, routers []models.Router, services []models.Service, middlewares []models.Middleware, changes []models.PromotionChange) error {
+	actions := make(map[string]string, len(changes))
+	for _, change := range changes {
+		actions[change.ResourceType+":"+change.ID] = change.Action
+	}
+
+	batch := &store.Batch{
+		Routers:     []models.Router{},
+		Services:    []models.Service{},
+		Middlewares: []models.Middleware{},
+		Updates:     make(map[models.ResourceRef]bool),
+	}
+	add := func(resourceType, id string) bool {
+		switch actions[resourceType+":"+id] {
+		case models.PromotionUpdate:
+			batch.Updates[models.ResourceRef{ResourceType: resourceType, ID: id}] = true
+			return true
+		case models.PromotionCreate:
+			return true
+		}
+		return false
+	}
+
+	for _, svc := range services {
+		if add("service", svc.ID) {
+			batch.Services = append(batch.Services, svc)
+		}
+	}
+	for _, mw := range middlewares {
+		if add("middleware", mw.ID) {
+			batch.Middlewares = append(batch.Middlewares, mw)
+		}
+	}
+	for _, router := range routers {
+		if add("router", router.ID) {
+			batch.Routers = append(batch.Routers, router)
+		}
+	}
+
+	return target.ApplyBatch(batch)
+}
+
+// resourceMetaFields are the JSON fields of models.ResourceMeta, which differ between environments
+var resourceMetaFields = []string{"createdAt", "updatedAt", "createdBy", "updatedBy", "resourceVersion"}
+
+// equalResources compares two resources by their JSON representation, ignoring their metadata
+func equalResources(a, b interface{}) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	var aValue, bValue interface{}
+	if json.Unmarshal(aJSON, &aValue) != nil || json.Unmarshal(bJSON, &bValue) != nil {
+		return false
+	}
+	for _, value := range []interface{}{aValue, bValue} {
+		if fields, ok := value.(map[string]interface{}); ok {
+			for _, field := range resourceMetaFields {
+				delete(fields, field)
+			}
+		}
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// TestEnvironmentPromotion tests promoting resources between environments
+func TestEnvironmentPromotion(t *testing.T) {
+	e := echo.New()
+
+	staging := NewMockStore()
+	setupTestData(t, staging)
+	production := NewMockStore()
+
+	environments := store.NewEnvironments(NewMockStore())
+	if err := environments.Add("staging", staging); err != nil {
+		t.Fatalf("Failed to add staging environment: %v", err)
+	}
+	if err := environments.Add("production", production); err != nil {
+		t.Fatalf("Failed to add production environment: %v", err)
+	}
+
+	handler := NewEnvironmentHandler(environments, nil)
+
+	promote := func(from, to, body string) (*httptest.ResponseRecorder, models.PromotionResult) {
+		req := httptest.NewRequest(http.MethodPost, "/environments/"+from+"/promote/"+to, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("from", "to")
+		c.SetParamValues(from, to)
+
+		if err := handler.Promote(c); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+
+		var result models.PromotionResult
+		_ = json.Unmarshal(rec.Body.Bytes(), &result)
+		return rec, result
+	}
+
+	t.Run("Dry Run Preview", func(t *testing.T) {
+		rec, result := promote("staging", "production", `{"routers":["api-router"],"dryRun":true}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		// The router pulls in its service and middleware
+		if len(result.Changes) != 3 {
+			t.Fatalf("Expected 3 changes, got %d", len(result.Changes))
+		}
+		for _, change := range result.Changes {
+			if change.Action != models.PromotionCreate {
+				t.Fatalf("Expected action 'create' for %s %s, got '%s'", change.ResourceType, change.ID, change.Action)
+			}
+		}
+
+		if len(production.routers) != 0 {
+			t.Fatalf("Dry run must not modify the target environment")
+		}
+	})
+
+	t.Run("Promote Selected Router", func(t *testing.T) {
+		rec, result := promote("staging", "production", `{"routers":["api-router"]}`)
+		if rec.Code != http.StatusOK || !result.Applied {
+			t.Fatalf("Expected applied promotion, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		if _, ok := production.routers["api-router"]; !ok {
+			t.Fatalf("api-router not promoted")
+		}
+		if _, ok := production.services["load-balanced-service"]; !ok {
+			t.Fatalf("load-balanced-service not promoted")
+		}
+		if _, ok := production.middlewares["strip-prefix"]; !ok {
+			t.Fatalf("strip-prefix not promoted")
+		}
+		if _, ok := production.routers["test-router"]; ok {
+			t.Fatalf("test-router must not be promoted")
+		}
+	})
+
+	t.Run("Conflict Reporting", func(t *testing.T) {
+		// Diverge the service in production
+		svc := production.services["load-balanced-service"]
+		svc.LoadBalancer = &models.LoadBalancerService{Servers: []models.Server{{URL: "http://prod:8080"}}}
+		production.services["load-balanced-service"] = svc
+
+		rec, result := promote("staging", "production", `{"all":true}`)
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("Expected status code %d, got %d", http.StatusConflict, rec.Code)
+		}
+		if len(result.Conflicts) != 1 || result.Conflicts[0].ID != "load-balanced-service" {
+			t.Fatalf("Expected a conflict for load-balanced-service, got %+v", result.Conflicts)
+		}
+		if _, ok := production.routers["test-router"]; ok {
+			t.Fatalf("Conflicting promotion must not modify the target environment")
+		}
+
+		rec, result = promote("staging", "production", `{"all":true,"overwrite":true}`)
+		if rec.Code != http.StatusOK || !result.Applied {
+			t.Fatalf("Expected applied promotion with overwrite, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if production.services["load-balanced-service"].LoadBalancer.Servers[0].URL != "http://server1:8080" {
+			t.Fatalf("Expected load-balanced-service to be overwritten")
+		}
+	})
+
+	t.Run("Unknown Environment", func(t *testing.T) {
+		if rec, _ := promote("staging", "qa", `{"all":true}`); rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected status code %d, got %d", http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+// TestEnvironmentPromotionRollback tests that a failing promotion leaves the target environment untouched
+func TestEnvironmentPromotionRollback(t *testing.T) {
+	e := echo.New()
+
+	tests := []struct {
+		name     string
+		prepare  func(staging *MockStore, production *store.FileStore)
+		expected int
+		message  string
+	}{
+		{
+			// The mock store doesn't validate references, so the source can hold a broken router
+			name: "Broken Router",
+			prepare: func(staging *MockStore, production *store.FileStore) {
+				staging.routers["broken"] = models.Router{ID: "broken", Rule: "Host(`broken.example.com`)", Service: models.Service{ID: "missing"}}
+			},
+			expected: http.StatusBadRequest,
+			message:  "service missing not found",
+		},
+		{
+			name: "Invalid Service",
+			prepare: func(staging *MockStore, production *store.FileStore) {
+				production.CreateService(&models.Service{ID: "team-a-api", URL: "http://api:8080"})
+				staging.services["team-a/api"] = models.Service{ID: "team-a/api", URL: "http://api:8080"}
+			},
+			expected: http.StatusBadRequest,
+			message:  "provider name team-a-api is already used",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			staging := NewMockStore()
+			setupTestData(t, staging)
+
+			production, err := store.NewFileStore(filepath.Join(t.TempDir(), "production.json"))
+			if err != nil {
+				t.Fatalf("Failed to create store: %v", err)
+			}
+			defer production.Close()
+
+			tt.prepare(staging, production)
+			version := production.Version()
+
+			environments := store.NewEnvironments(NewMockStore())
+			environments.Add("staging", staging)
+			environments.Add("production", production)
+			handler := NewEnvironmentHandler(environments, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/environments/staging/promote/production", strings.NewReader(`{"all":true}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("from", "to")
+			c.SetParamValues("staging", "production")
+			if err := handler.Promote(c); err != nil {
+				t.Fatalf("Handler returned error: %v", err)
+			}
+
+			if rec.Code != tt.expected || !strings.Contains(rec.Body.String(), tt.message) {
+				t.Fatalf("Expected status %d with %q, got %d: %s", tt.expected, tt.message, rec.Code, rec.Body.String())
+			}
+			if production.Version() != version {
+				t.Fatalf("Expected the target to be unchanged, got version %d instead of %d", production.Version(), version)
+			}
+			if routers, _ := production.ListRouters(); len(routers) != 0 {
+				t.Fatalf("Expected no router to be promoted, got %+v", routers)
+			}
+		})
+	}
+}
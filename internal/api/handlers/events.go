@@ -0,0 +1,158 @@
+// internal/api/handlers/events.go
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/events"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// eventsKeepAlive is the interval of comments keeping idle streams open through proxies
+const eventsKeepAlive = 15 * time.Second
+
+// EventsHandler streams resource changes as Server-Sent Events
+type EventsHandler struct {
+	Broker *events.Broker
+}
+
+// NewEventsHandler creates a new EventsHandler
+func NewEventsHandler(broker *events.Broker) *EventsHandler {
+	return &EventsHandler{
+		Broker: broker,
+	}
+}
+
+// eventFilter selects the events sent to a subscriber
+type eventFilter struct {
+	types     map[string]bool
+	prefix    string
+	namespace string
+}
+
+// apply returns the event as seen by the subscriber and whether it matches the filter.
+// Events of namespace-bound subscribers carry local IDs.
+func (f eventFilter) apply(event models.ChangeEvent) (models.ChangeEvent, bool) {
+	if len(f.types) > 0 && !f.types[event.Type] {
+		return event, false
+	}
+	if f.namespace != "" {
+		namespace, id := store.SplitQualifiedID(event.ID)
+		if namespace != f.namespace {
+			return event, false
+		}
+		event.ID = id
+	}
+	return event, strings.HasPrefix(event.ID, f.prefix)
+}
+
+// Stream handles the GET /events endpoint.
+// Supported filters are type (comma-separated resource types) and prefix (ID prefix).
+// Clients resume with the Last-Event-ID header or the lastEventId query parameter.
+func (h *EventsHandler) Stream(c echo.Context) error {
+	filter := eventFilter{
+		prefix:    c.QueryParam("prefix"),
+		namespace: requestNamespace(c),
+	}
+	if types := c.QueryParam("type"); types != "" {
+		filter.types = make(map[string]bool)
+		for _, resourceType := range strings.Split(types, ",") {
+			switch resourceType = strings.TrimSpace(resourceType); resourceType {
+			case store.ResourceRouters, store.ResourceServices, store.ResourceMiddlewares, store.ResourceApps:
+				filter.types[resourceType] = true
+			default:
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "Invalid type parameter: " + resourceType,
+				})
+			}
+		}
+	}
+
+	lastEventID := c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("lastEventId")
+	}
+	var after uint64
+	if lastEventID != "" {
+		var err error
+		if after, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid Last-Event-ID",
+			})
+		}
+	}
+
+	sub, backlog, complete := h.Broker.Subscribe(after, lastEventID != "")
+	defer sub.Close()
+
+	// Streams outlive the server's write timeout
+	res := c.Response()
+	if err := http.NewResponseController(res).SetWriteDeadline(time.Time{}); err != nil {
+		logger.Debug().Err(err).Msg("Failed to clear write deadline of event stream")
+	}
+
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	// Resumed clients whose events are no longer available have to reload the current state
+	if !complete {
+		fmt.Fprintf(res, "id: %d\nevent: reset\ndata: {\"version\":%d}\n\n", h.Broker.Version(), h.Broker.Version())
+	}
+	for _, event := range backlog {
+		if err := writeChangeEvent(res, filter, event); err != nil {
+			return nil
+		}
+	}
+	res.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Dropped for falling behind, the client resumes with its last event ID
+				logger.Warn().Msg("Event stream subscriber fell behind")
+				return nil
+			}
+			if err := writeChangeEvent(res, filter, event); err != nil {
+				return nil
+			}
+			res.Flush()
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case <-c.Request().Context().Done():
+			return nil
+		}
+	}
+}
+
+// writeChangeEvent writes an event in the SSE format if it matches the filter
+func writeChangeEvent(res *echo.Response, filter eventFilter, event models.ChangeEvent) error {
+	event, ok := filter.apply(event)
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(res, "id: %d\nevent: change\ndata: %s\n\n", event.Version, data)
+	return err
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/events"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// TestEventsHandler tests the Server-Sent Events change stream
+func TestEventsHandler(t *testing.T) {
+	broker := events.NewBroker(10, 0)
+	publish := func(version uint64, resourceType, id string) {
+		broker.Publish(models.ChangeEvent{Version: version, Action: models.ChangeUpdated, Type: resourceType, ID: id, Actor: "alice"})
+	}
+	publish(1, "routers", "api-1")
+	publish(2, "services", "api-1")
+	publish(3, "routers", "web")
+
+	e := echo.New()
+	e.GET("/events", NewEventsHandler(broker).Stream)
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	// readEvents connects and returns the first n events as "event id data" lines
+	readEvents := func(t *testing.T, query, lastEventID string, n int, afterConnect func()) []string {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events"+query, nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Fatalf("Expected text/event-stream, got %q", ct)
+		}
+		if afterConnect != nil {
+			afterConnect()
+		}
+
+		var result []string
+		var id, name string
+		scanner := bufio.NewScanner(resp.Body)
+		for len(result) < n && scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data := strings.TrimPrefix(line, "data: ")
+				if name == "change" {
+					var event models.ChangeEvent
+					if err := json.Unmarshal([]byte(data), &event); err != nil {
+						t.Fatalf("Invalid event data %q: %v", data, err)
+					}
+					data = event.Type + "/" + event.ID
+				}
+				result = append(result, name+" "+id+" "+data)
+			}
+		}
+		return result
+	}
+
+	t.Run("Resume With Filters", func(t *testing.T) {
+		got := readEvents(t, "?type=routers&prefix=api", "0", 2, func() {
+			publish(4, "routers", "api-2")
+		})
+		expected := []string{"change 1 routers/api-1", "change 4 routers/api-2"}
+		if strings.Join(got, "|") != strings.Join(expected, "|") {
+			t.Fatalf("Expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Reset When History Is Gone", func(t *testing.T) {
+		got := readEvents(t, "", "99", 1, nil)
+		if len(got) != 1 || !strings.HasPrefix(got[0], "reset 4 ") {
+			t.Fatalf("Expected reset event, got %v", got)
+		}
+	})
+
+	t.Run("Invalid Type", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?type=widgets", nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
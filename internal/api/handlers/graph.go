@@ -0,0 +1,78 @@
+// internal/api/handlers/graph.go
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// dotContentType is the media type of Graphviz DOT documents
+const dotContentType = "text/vnd.graphviz; charset=utf-8"
+
+// GraphHandler serves the dependency graph of routers, services and middlewares
+type GraphHandler struct {
+	BaseHandler
+}
+
+// NewGraphHandler creates a new GraphHandler
+func NewGraphHandler(store store.Store) *GraphHandler {
+	return &GraphHandler{
+		BaseHandler: BaseHandler{Store: store},
+	}
+}
+
+// Get handles the GET /graph endpoint. The graph is returned as JSON nodes and edges,
+// or as Graphviz DOT with format=dot.
+func (h *GraphHandler) Get(c echo.Context) error {
+	logger.Debug().Msg("Getting dependency graph")
+
+	format := c.QueryParam("format")
+	if format != "" && format != "json" && format != "dot" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid format parameter, expected json or dot",
+		})
+	}
+
+	graph, err := h.StoreFor(c).Graph()
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get dependency graph")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to get dependency graph",
+		})
+	}
+
+	if format == "dot" {
+		return c.Blob(http.StatusOK, dotContentType, []byte(dot(graph)))
+	}
+	return c.JSON(http.StatusOK, graph)
+}
+
+// dot renders a graph as a Graphviz DOT digraph, with one node shape per resource type
+func dot(graph *models.Graph) string {
+	shapes := map[string]string{
+		"router":     "box",
+		"service":    "ellipse",
+		"middleware": "hexagon",
+	}
+	node := func(ref models.ResourceRef) string {
+		return fmt.Sprintf("%q", ref.ResourceType+":"+ref.ID)
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, ref := range graph.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%q, shape=%s];\n", node(ref), ref.ID, shapes[ref.ResourceType])
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%q];\n", node(edge.From), node(edge.To), edge.Field)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// TestGraphHandler tests the dependency graph as JSON and as Graphviz DOT
+func TestGraphHandler(t *testing.T) {
+	e := echo.New()
+
+	fs, err := store.NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	fs.CreateService(&models.Service{ID: "api", URL: "http://api:8080"})
+	fs.CreateMiddleware(&models.Middleware{ID: "auth", Type: "basicAuth", Config: map[string]interface{}{}})
+	fs.CreateRouter(&models.Router{ID: "api", Rule: "Host(`api.example.com`)", Service: models.Service{ID: "api"}, Middlewares: []models.Middleware{{ID: "auth"}}})
+	handler := NewGraphHandler(fs)
+
+	getGraph := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/graph"+query, nil)
+		rec := httptest.NewRecorder()
+		if err := handler.Get(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		return rec
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		rec := getGraph("")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		var graph models.Graph
+		json.Unmarshal(rec.Body.Bytes(), &graph)
+		if len(graph.Nodes) != 3 || len(graph.Edges) != 2 {
+			t.Fatalf("Expected 3 nodes and 2 edges, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("DOT", func(t *testing.T) {
+		rec := getGraph("?format=dot")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		if contentType := rec.Header().Get(echo.HeaderContentType); contentType != dotContentType {
+			t.Fatalf("Expected content type %s, got %s", dotContentType, contentType)
+		}
+		body := rec.Body.String()
+		for _, expected := range []string{
+			"digraph dependencies {",
+			`"router:api" [label="api", shape=box];`,
+			`"router:api" -> "middleware:auth" [label="middlewares"];`,
+			`"router:api" -> "service:api" [label="service"];`,
+		} {
+			if !strings.Contains(body, expected) {
+				t.Fatalf("Expected %q in %s", expected, body)
+			}
+		}
+	})
+
+	t.Run("Invalid Format", func(t *testing.T) {
+		if rec := getGraph("?format=svg"); rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
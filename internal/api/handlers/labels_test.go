@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// TestLabelSelectors tests listing and bulk deleting resources by label selector
+func TestLabelSelectors(t *testing.T) {
+	e := echo.New()
+
+	fs, err := store.NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	payments := models.Labels{"team": "payments", "env": "prod"}
+	services := []models.Service{
+		{ID: "pay-api", URL: "http://pay-api:8080", Labels: payments},
+		{ID: "pay-web", URL: "http://pay-web:8080", Labels: models.Labels{"team": "payments", "env": "dev"}},
+		{ID: "search", URL: "http://search:8080", Labels: models.Labels{"team": "search"}},
+		{ID: "legacy", URL: "http://legacy:8080"},
+	}
+	for i := range services {
+		if err := fs.CreateService(&services[i]); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+	if err := fs.CreateService(&models.Service{ID: "pay-split", Labels: payments, Weighted: &models.WeightedService{
+		Services: []models.WeightedServiceItem{{Name: models.Service{ID: "pay-api"}, Weight: 1}},
+	}}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	if err := fs.CreateRouter(&models.Router{
+		ID: "search", Rule: "Host(`search.example.com`)", Service: models.Service{ID: "search"},
+		Labels: models.Labels{"team": "search"}, Annotations: models.Annotations{"owner": "search@example.com"},
+	}); err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	serviceHandler := NewServiceHandler(fs)
+
+	request := func(handler echo.HandlerFunc, method, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/"+query, nil)
+		rec := httptest.NewRecorder()
+		if err := handler(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		return rec
+	}
+
+	t.Run("List By Selector", func(t *testing.T) {
+		tests := []struct {
+			selector string
+			expected string
+		}{
+			{"team=payments", "[pay-api pay-split pay-web]"},
+			{"team=payments,env!=dev", "[pay-api pay-split]"},
+			{"!team", "[legacy]"},
+			{"team,team!=payments", "[search]"},
+		}
+
+		for _, tt := range tests {
+			rec := request(serviceHandler.List, http.MethodGet, "?selector="+tt.selector)
+			var items []models.Service
+			json.Unmarshal(rec.Body.Bytes(), &items)
+			ids := make([]string, len(items))
+			for i, item := range items {
+				ids[i] = item.ID
+			}
+			if rec.Code != http.StatusOK || fmt.Sprint(ids) != tt.expected {
+				t.Fatalf("%s: expected %s, got status %d and %v", tt.selector, tt.expected, rec.Code, ids)
+			}
+		}
+
+		if rec := request(serviceHandler.List, http.MethodGet, "?selector=team=a,,b"); rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Provider Output Has No Labels", func(t *testing.T) {
+		routers, services, middlewares, err := listProviderResources(fs)
+		if err != nil {
+			t.Fatalf("Failed to list resources: %v", err)
+		}
+		data, _ := json.Marshal(convertToTraefikConfig(routers, services, middlewares))
+		if strings.Contains(string(data), "payments") || strings.Contains(string(data), "search@example.com") {
+			t.Fatalf("Expected labels and annotations to be stripped, got %s", data)
+		}
+	})
+
+	t.Run("Delete Requires Selector", func(t *testing.T) {
+		if rec := request(serviceHandler.DeleteBySelector, http.MethodDelete, ""); rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+		if services, _ := fs.ListServices(); len(services) != 5 {
+			t.Fatalf("Expected no service to be deleted, got %d services", len(services))
+		}
+	})
+
+	t.Run("Delete By Selector", func(t *testing.T) {
+		rec := request(serviceHandler.DeleteBySelector, http.MethodDelete, "?selector=team")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		var response models.BulkDeleteResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		if fmt.Sprint(response.Deleted) != "[pay-api pay-split pay-web]" {
+			t.Fatalf("Expected the payments services to be deleted, got %v", response.Deleted)
+		}
+		// search is still used by its router
+		if len(response.Failed) != 1 || response.Failed[0].ID != "search" {
+			t.Fatalf("Expected search to fail, got %+v", response.Failed)
+		}
+
+		if services, _ := fs.ListServices(); len(services) != 2 {
+			t.Fatalf("Expected 2 services to remain, got %d", len(services))
+		}
+	})
+}
@@ -0,0 +1,199 @@
+// internal/api/handlers/list.go
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/labels"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// Pagination limits of the list endpoints
+const (
+	maxListLimit = 1000
+)
+
+// Headers of paginated list responses
+const (
+	// HeaderTotalCount holds the number of resources matching the filters across all pages
+	HeaderTotalCount = "X-Total-Count"
+	// HeaderNextCursor holds the cursor of the next page, absent on the last page
+	HeaderNextCursor = "X-Next-Cursor"
+)
+
+// Sort orders of the list endpoints
+const (
+	sortByID        = "id"
+	sortByUpdatedAt = "updatedAt"
+)
+
+// listOptions are the pagination and sorting parameters of a list request
+type listOptions struct {
+	limit int
+	// after is the sort key of the last resource of the previous page
+	after string
+	sort  string
+	desc  bool
+	// updatedSince excludes the resources last updated before it
+	updatedSince *time.Time
+}
+
+// parseListOptions reads the limit, cursor, sort and updatedSince query parameters.
+// sort is "id" or "updatedAt", prefixed with "-" for descending order.
+func parseListOptions(c echo.Context) (listOptions, error) {
+	options := listOptions{sort: sortByID}
+
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return options, errors.New("Invalid limit parameter")
+		}
+		if limit > maxListLimit {
+			limit = maxListLimit
+		}
+		options.limit = limit
+	}
+
+	if value := c.QueryParam("sort"); value != "" {
+		options.desc = strings.HasPrefix(value, "-")
+		options.sort = strings.TrimPrefix(value, "-")
+		switch options.sort {
+		case sortByID, sortByUpdatedAt:
+		default:
+			return options, errors.New("Invalid sort parameter, expected id or updatedAt, optionally prefixed with -")
+		}
+	}
+
+	if value := c.QueryParam("updatedSince"); value != "" {
+		updatedSince, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return options, errors.New("Invalid updatedSince parameter, expected an RFC 3339 timestamp")
+		}
+		options.updatedSince = &updatedSince
+	}
+
+	if value := c.QueryParam("cursor"); value != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return options, errors.New("Invalid cursor parameter")
+		}
+		// The cursor is only valid for the order it was created with
+		sortOrder, after, ok := strings.Cut(string(decoded), "\n")
+		if !ok || sortOrder != options.order() {
+			return options, errors.New("Invalid cursor parameter")
+		}
+		options.after = after
+	}
+
+	return options, nil
+}
+
+// order returns the sort parameter the options were parsed from
+func (o listOptions) order() string {
+	if o.desc {
+		return "-" + o.sort
+	}
+	return o.sort
+}
+
+// matches returns true if a resource with the given metadata passes the updatedSince filter.
+// Resources without metadata were last updated before it was tracked.
+func (o listOptions) matches(meta models.ResourceMeta) bool {
+	if o.updatedSince == nil {
+		return true
+	}
+	return meta.UpdatedAt != nil && !meta.UpdatedAt.Before(*o.updatedSince)
+}
+
+// key returns the sort key of a resource. Keys of the updatedAt order are the
+// fixed-width update time followed by the ID, so that they sort like times and are unique.
+func (o listOptions) key(id string, meta models.ResourceMeta) string {
+	if o.sort != sortByUpdatedAt {
+		return id
+	}
+	updatedAt := ""
+	if meta.UpdatedAt != nil {
+		updatedAt = meta.UpdatedAt.UTC().Format("20060102150405.000000000")
+	}
+	return updatedAt + "\x00" + id
+}
+
+// cursor returns the cursor of the page following the resource with the given sort key
+func (o listOptions) cursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(o.order() + "\n" + key))
+}
+
+// paginate sorts the resources by their keys, returns the requested page and sets the
+// total count and next cursor headers. Keys must be unique, e.g. by ending with the ID.
+func paginate[T any](c echo.Context, items []T, options listOptions, key func(T) string) []T {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = key(item)
+	}
+	indexes := make([]int, len(items))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		if options.desc {
+			return keys[indexes[i]] > keys[indexes[j]]
+		}
+		return keys[indexes[i]] < keys[indexes[j]]
+	})
+
+	c.Response().Header().Set(HeaderTotalCount, strconv.Itoa(len(items)))
+
+	page := make([]T, 0, len(items))
+	for _, i := range indexes {
+		if options.after != "" {
+			if !options.desc && keys[i] <= options.after {
+				continue
+			}
+			if options.desc && keys[i] >= options.after {
+				continue
+			}
+		}
+		if options.limit > 0 && len(page) == options.limit {
+			c.Response().Header().Set(HeaderNextCursor, options.cursor(key(page[len(page)-1])))
+			break
+		}
+		page = append(page, items[i])
+	}
+	return page
+}
+
+// parseBoolFilter parses an optional boolean filter parameter
+func parseBoolFilter(c echo.Context, name string) (*bool, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, errors.New("Invalid " + name + " parameter, expected true or false")
+	}
+	return &parsed, nil
+}
+
+// parseSelector parses the optional label selector parameter, e.g. "team=payments,env!=dev"
+func parseSelector(c echo.Context) (labels.Selector, error) {
+	selector, err := labels.Parse(c.QueryParam("selector"))
+	if err != nil {
+		return nil, errors.New("Invalid selector parameter: " + err.Error())
+	}
+	return selector, nil
+}
+
+// listError writes the error of an invalid list request
+func listError(c echo.Context, err error) error {
+	return c.JSON(http.StatusBadRequest, map[string]string{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// TestListFiltersAndPagination tests filtering, sorting and cursor pagination of the list endpoints
+func TestListFiltersAndPagination(t *testing.T) {
+	e := echo.New()
+	mockStore := NewMockStore()
+
+	mockStore.CreateService(&models.Service{ID: "svc-a", URL: "http://a:8080"})
+	mockStore.CreateService(&models.Service{ID: "svc-w", Weighted: &models.WeightedService{
+		Services: []models.WeightedServiceItem{{Name: models.Service{ID: "svc-a"}, Weight: 1}},
+	}})
+	mockStore.CreateMiddleware(&models.Middleware{ID: "auth", Type: "basicAuth"})
+	mockStore.CreateMiddleware(&models.Middleware{ID: "strip", Type: "stripPrefix"})
+	// Routers with higher numbers were updated earlier
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		updatedAt := base.Add(time.Duration(6-i) * time.Hour)
+		router := &models.Router{
+			ID:           fmt.Sprintf("router-%d", i),
+			Rule:         fmt.Sprintf("Host(`app%d.example.com`)", i),
+			Service:      models.Service{ID: "svc-a"},
+			EntryPoints:  []string{"web"},
+			ResourceMeta: models.ResourceMeta{UpdatedAt: &updatedAt},
+		}
+		if i%2 == 0 {
+			router.EntryPoints = []string{"websecure"}
+			router.TLS = &models.RouterTLS{CertResolver: "le"}
+			router.Middlewares = []models.Middleware{{ID: "auth"}}
+		}
+		if err := mockStore.CreateRouter(router); err != nil {
+			t.Fatalf("Failed to create router: %v", err)
+		}
+	}
+
+	routerHandler := NewRouterHandler(mockStore)
+	serviceHandler := NewServiceHandler(mockStore)
+	middlewareHandler := NewMiddlewareHandler(mockStore)
+
+	list := func(handler echo.HandlerFunc, query string) (*httptest.ResponseRecorder, []string) {
+		req := httptest.NewRequest(http.MethodGet, "/"+query, nil)
+		rec := httptest.NewRecorder()
+		if err := handler(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		var items []struct {
+			ID string `json:"id"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &items)
+		ids := make([]string, len(items))
+		for i, item := range items {
+			ids[i] = item.ID
+		}
+		return rec, ids
+	}
+
+	t.Run("Router Filters", func(t *testing.T) {
+		tests := []struct {
+			query    string
+			expected int
+		}{
+			{"?entrypoint=websecure", 4},
+			{"?tls=false", 3},
+			{"?middleware=auth&tls=true", 4},
+			{"?service=svc-a", 7},
+			{"?service=svc-w", 0},
+			{"?rule=app3.", 1},
+		}
+
+		for _, tt := range tests {
+			rec, ids := list(routerHandler.List, tt.query)
+			if rec.Code != http.StatusOK || len(ids) != tt.expected {
+				t.Fatalf("%s: expected %d routers, got status %d and %v", tt.query, tt.expected, rec.Code, ids)
+			}
+			if rec.Header().Get(HeaderTotalCount) != fmt.Sprint(tt.expected) {
+				t.Fatalf("%s: expected total count %d, got %s", tt.query, tt.expected, rec.Header().Get(HeaderTotalCount))
+			}
+		}
+	})
+
+	t.Run("Cursor Pagination", func(t *testing.T) {
+		var all []string
+		query := "?limit=3&sort=-id"
+		for pages := 0; ; pages++ {
+			if pages > 5 {
+				t.Fatal("Pagination did not end")
+			}
+			rec, ids := list(routerHandler.List, query)
+			if rec.Header().Get(HeaderTotalCount) != "7" {
+				t.Fatalf("Expected total count 7, got %s", rec.Header().Get(HeaderTotalCount))
+			}
+			all = append(all, ids...)
+
+			cursor := rec.Header().Get(HeaderNextCursor)
+			if cursor == "" {
+				break
+			}
+			query = "?limit=3&sort=-id&cursor=" + cursor
+		}
+
+		expected := []string{"router-6", "router-5", "router-4", "router-3", "router-2", "router-1", "router-0"}
+		if fmt.Sprint(all) != fmt.Sprint(expected) {
+			t.Fatalf("Expected %v, got %v", expected, all)
+		}
+	})
+
+	t.Run("Cursor Of Other Order", func(t *testing.T) {
+		rec, _ := list(routerHandler.List, "?limit=3")
+		cursor := rec.Header().Get(HeaderNextCursor)
+		if rec, _ := list(routerHandler.List, "?limit=3&sort=-id&cursor="+cursor); rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Updated Since And Order", func(t *testing.T) {
+		if _, ids := list(routerHandler.List, "?sort=updatedAt&limit=3"); fmt.Sprint(ids) != "[router-6 router-5 router-4]" {
+			t.Fatalf("Expected the least recently updated routers first, got %v", ids)
+		}
+
+		since := base.Add(5 * time.Hour).Format(time.RFC3339)
+		rec, ids := list(routerHandler.List, "?sort=-updatedAt&updatedSince="+since)
+		if fmt.Sprint(ids) != "[router-0 router-1]" || rec.Header().Get(HeaderTotalCount) != "2" {
+			t.Fatalf("Expected the routers updated since %s, got %v", since, ids)
+		}
+		// Services without metadata predate the filter
+		if _, ids := list(serviceHandler.List, "?updatedSince="+since); len(ids) != 0 {
+			t.Fatalf("Expected no services, got %v", ids)
+		}
+	})
+
+	t.Run("Service And Middleware Types", func(t *testing.T) {
+		if _, ids := list(serviceHandler.List, "?type=weighted"); fmt.Sprint(ids) != "[svc-w]" {
+			t.Fatalf("Expected weighted service, got %v", ids)
+		}
+		if _, ids := list(serviceHandler.List, "?type=loadBalancer"); fmt.Sprint(ids) != "[svc-a]" {
+			t.Fatalf("Expected URL service to be a load balancer, got %v", ids)
+		}
+		if _, ids := list(middlewareHandler.List, "?type=stripPrefix"); fmt.Sprint(ids) != "[strip]" {
+			t.Fatalf("Expected stripPrefix middleware, got %v", ids)
+		}
+	})
+
+	t.Run("Invalid Parameters", func(t *testing.T) {
+		for _, query := range []string{"?limit=0", "?sort=name", "?cursor=!", "?tls=maybe", "?updatedSince=yesterday"} {
+			if rec, _ := list(routerHandler.List, query); rec.Code != http.StatusBadRequest {
+				t.Fatalf("%s: expected status %d, got %d", query, http.StatusBadRequest, rec.Code)
+			}
+		}
+		if rec, _ := list(serviceHandler.List, "?type=url"); rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+// internal/api/handlers/metrics.go
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/metrics"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// metricsContentType is the content type of the Prometheus text exposition format
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// MetricsHandler serves Prometheus metrics
+type MetricsHandler struct {
+	BaseHandler
+	// Environments holds the stores of the named environments, nil if none are configured
+	Environments *store.Environments
+}
+
+// NewMetricsHandler creates a new MetricsHandler
+func NewMetricsHandler(store store.Store, environments *store.Environments) *MetricsHandler {
+	return &MetricsHandler{
+		BaseHandler:  NewBaseHandler(store),
+		Environments: environments,
+	}
+}
+
+// Serve handles the GET /metrics endpoint.
+// Resource counts are read from the stores on every scrape.
+func (h *MetricsHandler) Serve(c echo.Context) error {
+	h.updateResourceCounts(store.DefaultEnvironment, h.Store)
+	if h.Environments != nil {
+		for _, name := range h.Environments.Names() {
+			if name == store.DefaultEnvironment {
+				continue
+			}
+			envStore, err := h.Environments.Get(name)
+			if err != nil {
+				continue
+			}
+			h.updateResourceCounts(name, envStore)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := metrics.Default.WriteText(&buf); err != nil {
+		logger.Error().Err(err).Msg("Failed to write metrics")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to write metrics",
+		})
+	}
+
+	return c.Blob(http.StatusOK, metricsContentType, buf.Bytes())
+}
+
+// updateResourceCounts sets the resource gauges of an environment
+func (h *MetricsHandler) updateResourceCounts(environment string, s store.Store) {
+	if routers, err := s.ListRouters(); err == nil {
+		metrics.Resources.Set(float64(len(routers)), environment, "routers")
+	}
+	if services, err := s.ListServices(); err == nil {
+		metrics.Resources.Set(float64(len(services)), environment, "services")
+	}
+	if middlewares, err := s.ListMiddlewares(); err == nil {
+		metrics.Resources.Set(float64(len(middlewares)), environment, "middlewares")
+	}
+}
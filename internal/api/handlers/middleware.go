@@ -2,6 +2,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -22,11 +23,23 @@ func NewMiddlewareHandler(store store.Store) *MiddlewareHandler {
 	}
 }
 
-// List handles the GET /middlewares endpoint to list all middlewares
+// List handles the GET /middlewares endpoint to list all middlewares.
+// Middlewares can be filtered by type, label selector and updatedSince and are paginated
+// with limit and cursor.
 func (h *MiddlewareHandler) List(c echo.Context) error {
 	logger.Debug().Msg("Listing middlewares")
 
-	middlewares, err := h.Store.ListMiddlewares()
+	options, err := parseListOptions(c)
+	if err != nil {
+		return listError(c, err)
+	}
+	middlewareType := c.QueryParam("type")
+	selector, err := parseSelector(c)
+	if err != nil {
+		return listError(c, err)
+	}
+
+	middlewares, err := h.StoreFor(c).ListMiddlewares()
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to list middlewares")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -34,7 +47,23 @@ func (h *MiddlewareHandler) List(c echo.Context) error {
 		})
 	}
 
-	return c.JSON(http.StatusOK, middlewares)
+	filtered := make([]models.Middleware, 0, len(middlewares))
+	for _, middleware := range middlewares {
+		if middlewareType != "" && middleware.Type != middlewareType {
+			continue
+		}
+		if !selector.Matches(middleware.Labels) {
+			continue
+		}
+		if !options.matches(middleware.ResourceMeta) {
+			continue
+		}
+		filtered = append(filtered, middleware)
+	}
+
+	return c.JSON(http.StatusOK, paginate(c, filtered, options, func(middleware models.Middleware) string {
+		return options.key(middleware.ID, middleware.ResourceMeta)
+	}))
 }
 
 // Get handles the GET /middlewares/:id endpoint to get a specific middleware
@@ -42,7 +71,7 @@ func (h *MiddlewareHandler) Get(c echo.Context) error {
 	id := c.Param("id")
 	logger.Debug().Str("id", id).Msg("Getting middleware")
 
-	middleware, err := h.Store.GetMiddleware(id)
+	middleware, err := h.StoreFor(c).GetMiddleware(id)
 	if err != nil {
 		if store.IsNotFound(err) {
 			logger.Debug().Str("id", id).Msg("Middleware not found")
@@ -87,7 +116,7 @@ func (h *MiddlewareHandler) Create(c echo.Context) error {
 	}
 
 	// Check if middleware already exists
-	exists, err := h.Store.MiddlewareExists(middleware.ID)
+	exists, err := h.StoreFor(c).MiddlewareExists(middleware.ID)
 	if err != nil {
 		logger.Error().Err(err).Str("id", middleware.ID).Msg("Failed to check if middleware exists")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -103,8 +132,13 @@ func (h *MiddlewareHandler) Create(c echo.Context) error {
 	}
 
 	// Create the middleware
-	if err := h.Store.CreateMiddleware(&middleware); err != nil {
+	if err := h.StoreFor(c).CreateMiddleware(&middleware); err != nil {
 		logger.Error().Err(err).Str("id", middleware.ID).Msg("Failed to create middleware")
+		if store.IsValidationError(err) || store.IsInvalidID(err) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Failed to create middleware",
 		})
@@ -147,7 +181,7 @@ func (h *MiddlewareHandler) Update(c echo.Context) error {
 	}
 
 	// Check if middleware exists
-	exists, err := h.Store.MiddlewareExists(id)
+	exists, err := h.StoreFor(c).MiddlewareExists(id)
 	if err != nil {
 		logger.Error().Err(err).Str("id", id).Msg("Failed to check if middleware exists")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -163,8 +197,13 @@ func (h *MiddlewareHandler) Update(c echo.Context) error {
 	}
 
 	// Update the middleware
-	if err := h.Store.UpdateMiddleware(id, &middleware); err != nil {
+	if err := h.StoreFor(c).UpdateMiddleware(id, &middleware); err != nil {
 		logger.Error().Err(err).Str("id", id).Msg("Failed to update middleware")
+		if store.IsValidationError(err) || store.IsInvalidID(err) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Failed to update middleware",
 		})
@@ -183,60 +222,113 @@ func (h *MiddlewareHandler) Update(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
-// Delete handles the DELETE /middlewares/:id endpoint to delete a middleware
-func (h *MiddlewareHandler) Delete(c echo.Context) error {
+// Patch handles the PATCH /middlewares/:id endpoint to partially update a middleware.
+// The body is a JSON Merge Patch or a JSON Patch applied to the current middleware.
+func (h *MiddlewareHandler) Patch(c echo.Context) error {
 	id := c.Param("id")
-	logger.Debug().Str("id", id).Msg("Deleting middleware")
+	logger.Debug().Str("id", id).Msg("Patching middleware")
 
-	// Check if middleware exists
-	exists, err := h.Store.MiddlewareExists(id)
+	current, err := h.StoreFor(c).GetMiddleware(id)
 	if err != nil {
-		logger.Error().Err(err).Str("id", id).Msg("Failed to check if middleware exists")
+		if store.IsNotFound(err) {
+			logger.Warn().Str("id", id).Msg("Middleware not found")
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Middleware not found",
+			})
+		}
+		logger.Error().Err(err).Str("id", id).Msg("Failed to get middleware")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to check if middleware exists",
+			"error": "Failed to get middleware",
 		})
 	}
 
-	if !exists {
-		logger.Warn().Str("id", id).Msg("Middleware not found")
-		return c.JSON(http.StatusNotFound, map[string]string{
-			"error": "Middleware not found",
-		})
+	patched, patchErr := applyPatch(c, current)
+	if patchErr != nil {
+		return patchErr.respond(c)
 	}
 
-	// Check if middleware is in use
-	inUse, usedBy, err := h.Store.MiddlewareInUse(id)
-	if err != nil {
-		logger.Error().Err(err).Str("id", id).Msg("Failed to check if middleware is in use")
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to check if middleware is in use",
+	var middleware models.Middleware
+	if err := json.Unmarshal(patched, &middleware); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid patched middleware: " + err.Error(),
 		})
 	}
 
-	if inUse {
-		logger.Warn().Str("id", id).Strs("used_by", usedBy).Msg("Middleware is in use")
-		return c.JSON(http.StatusConflict, map[string]interface{}{
-			"error":   "Middleware is in use by other resources and cannot be deleted",
-			"used_by": usedBy,
+	if middleware.ID != id {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Middleware ID cannot be changed",
 		})
 	}
 
-	// Delete the middleware
-	if err := h.Store.DeleteMiddleware(id); err != nil {
-		logger.Error().Err(err).Str("id", id).Msg("Failed to delete middleware")
+	if middleware.Type == "" {
+		logger.Warn().Str("id", id).Msg("Middleware type is required")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Middleware type is required",
+		})
+	}
 
-		if store.IsResourceInUse(err) {
-			// This is a fallback for any resource-in-use errors not caught by the explicit check
-			return c.JSON(http.StatusConflict, map[string]string{
-				"error": "Middleware is in use by other resources and cannot be deleted",
+	if err := h.StoreFor(c).UpdateMiddleware(id, &middleware); err != nil {
+		if store.IsNotFound(err) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Middleware not found",
+			})
+		}
+		logger.Error().Err(err).Str("id", id).Msg("Failed to patch middleware")
+		if store.IsValidationError(err) || store.IsInvalidID(err) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
 			})
 		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to patch middleware",
+		})
+	}
 
+	logger.Info().Str("id", id).Msg("Middleware patched")
+
+	return c.JSON(http.StatusOK, models.ResourceResponse{
+		ID:      id,
+		Updated: true,
+	})
+}
+
+// Delete handles the DELETE /middlewares/:id endpoint to delete a middleware
+func (h *MiddlewareHandler) Delete(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Deleting middleware")
+
+	options, err := parseDeleteOptions(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if options.Cascade || options.DryRun {
+		return deleteWithOptions(c, "Middleware", id, options, h.StoreFor(c).DeleteMiddlewareWithOptions)
+	}
+
+	// Check if middleware exists
+	exists, err := h.StoreFor(c).MiddlewareExists(id)
+	if err != nil {
+		logger.Error().Err(err).Str("id", id).Msg("Failed to check if middleware exists")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to delete middleware",
+			"error": "Failed to check if middleware exists",
+		})
+	}
+
+	if !exists {
+		logger.Warn().Str("id", id).Msg("Middleware not found")
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Middleware not found",
 		})
 	}
 
+	// Delete the middleware
+	if err := h.StoreFor(c).DeleteMiddleware(id); err != nil {
+		logger.Warn().Err(err).Str("id", id).Msg("Failed to delete middleware")
+		return deleteError(c, "Middleware", id, err)
+	}
+
 	logger.Info().Str("id", id).Msg("Middleware deleted")
 
 	// Return success response
@@ -249,3 +341,9 @@ func (h *MiddlewareHandler) Delete(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, response)
 }
+
+// Rename handles the POST /middlewares/:id/rename endpoint to change the ID of a middleware
+// and rewrite the references to it
+func (h *MiddlewareHandler) Rename(c echo.Context) error {
+	return rename(c, "Middleware", c.Param("id"), h.StoreFor(c).RenameMiddleware)
+}
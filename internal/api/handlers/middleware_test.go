@@ -81,6 +81,14 @@ func (m *MockStore) DeleteMiddleware(id string) error {
 	return nil
 }
 
+func (m *MockStore) DeleteMiddlewareWithOptions(id string, options store.DeleteOptions) ([]models.ResourceRef, error) {
+	return nil, store.ErrInternalError
+}
+
+func (m *MockStore) RenameMiddleware(id, newID string, authorize store.Authorizer) ([]models.ResourceRef, error) {
+	return nil, store.ErrInternalError
+}
+
 func (m *MockStore) MiddlewareExists(id string) (bool, error) {
 	_, exists := m.middlewares[id]
 	return exists, nil
@@ -150,6 +158,14 @@ func (m *MockStore) DeleteService(id string) error {
 	return nil
 }
 
+func (m *MockStore) DeleteServiceWithOptions(id string, options store.DeleteOptions) ([]models.ResourceRef, error) {
+	return nil, store.ErrInternalError
+}
+
+func (m *MockStore) RenameService(id, newID string, authorize store.Authorizer) ([]models.ResourceRef, error) {
+	return nil, store.ErrInternalError
+}
+
 func (m *MockStore) ServiceExists(id string) (bool, error) {
 	_, exists := m.services[id]
 	return exists, nil
@@ -242,6 +258,63 @@ func (m *MockStore) RouterInUse(id string) (bool, []string, error) {
 	return false, nil, nil
 }
 
+// App methods, apps are not modelled in the mock
+func (m *MockStore) ListApps() ([]models.App, error) {
+	return []models.App{}, nil
+}
+
+func (m *MockStore) GetApp(id string) (*models.App, error) {
+	return nil, store.ErrNotFound
+}
+
+func (m *MockStore) CreateApp(app *models.App) error {
+	return store.ErrInternalError
+}
+
+func (m *MockStore) ApplyBatch(batch *store.Batch) error {
+	for _, service := range models.OrderServices(batch.Services) {
+		if batch.Updates[models.ResourceRef{ResourceType: "service", ID: service.ID}] {
+			m.UpdateService(service.ID, &service)
+		} else {
+			m.CreateService(&service)
+		}
+	}
+	for _, middleware := range models.OrderMiddlewares(batch.Middlewares) {
+		if batch.Updates[models.ResourceRef{ResourceType: "middleware", ID: middleware.ID}] {
+			m.UpdateMiddleware(middleware.ID, &middleware)
+		} else {
+			m.CreateMiddleware(&middleware)
+		}
+	}
+	for _, router := range batch.Routers {
+		if batch.Updates[models.ResourceRef{ResourceType: "router", ID: router.ID}] {
+			m.UpdateRouter(router.ID, &router)
+		} else {
+			m.CreateRouter(&router)
+		}
+	}
+	return nil
+}
+
+func (m *MockStore) DeleteApp(id string) (*models.AppDeleteResponse, error) {
+	return nil, store.ErrNotFound
+}
+
+// Graph returns an empty graph, dependencies are not modelled in the mock
+func (m *MockStore) Graph() (*models.Graph, error) {
+	return &models.Graph{Nodes: []models.ResourceRef{}, Edges: []models.GraphEdge{}}, nil
+}
+
+// Namespace returns the mock itself, namespaces are not modelled in the mock
+func (m *MockStore) Namespace(name string) store.Store {
+	return m
+}
+
+// WithActor returns the mock itself, actors are not recorded by the mock
+func (m *MockStore) WithActor(actor string) store.Store {
+	return m
+}
+
 // Persistence methods (no-op for mock)
 func (m *MockStore) Save() error {
 	return nil
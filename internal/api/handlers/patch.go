@@ -0,0 +1,69 @@
+// internal/api/handlers/patch.go
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/jsonpatch"
+	"github.com/sistemica/traefik-manager/internal/logger"
+)
+
+// maxPatchSize limits the size of patch request bodies
+const maxPatchSize = 1 << 20
+
+// patchError is an error response of a patch request
+type patchError struct {
+	status  int
+	message string
+}
+
+// applyPatch applies the JSON Merge Patch or JSON Patch in the request body to
+// the JSON encoding of a resource and returns the patched document
+func applyPatch(c echo.Context, resource interface{}) ([]byte, *patchError) {
+	mediaType, _, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil || (mediaType != jsonpatch.MergePatchType && mediaType != jsonpatch.JSONPatchType) {
+		return nil, &patchError{http.StatusUnsupportedMediaType, "Content-Type must be " + jsonpatch.MergePatchType + " or " + jsonpatch.JSONPatchType}
+	}
+
+	patch, err := io.ReadAll(io.LimitReader(c.Request().Body, maxPatchSize+1))
+	if err != nil {
+		return nil, &patchError{http.StatusBadRequest, "Failed to read patch"}
+	}
+	if len(patch) > maxPatchSize {
+		return nil, &patchError{http.StatusRequestEntityTooLarge, "Patch is too large"}
+	}
+
+	doc, err := json.Marshal(resource)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to encode resource for patching")
+		return nil, &patchError{http.StatusInternalServerError, "Failed to apply patch"}
+	}
+
+	var patched []byte
+	if mediaType == jsonpatch.MergePatchType {
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	} else {
+		patched, err = jsonpatch.Apply(doc, patch)
+	}
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to apply patch")
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return nil, &patchError{http.StatusConflict, err.Error()}
+		}
+		return nil, &patchError{http.StatusBadRequest, "Invalid patch: " + err.Error()}
+	}
+
+	return patched, nil
+}
+
+// respond writes the patch error
+func (e *patchError) respond(c echo.Context) error {
+	return c.JSON(e.status, map[string]string{
+		"error": e.message,
+	})
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/jsonpatch"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// TestPatchHandlers tests partial updates with JSON Merge Patch and JSON Patch
+func TestPatchHandlers(t *testing.T) {
+	e := echo.New()
+
+	fs, err := store.NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	if err := fs.CreateService(&models.Service{ID: "api", LoadBalancer: &models.LoadBalancerService{
+		Servers: []models.Server{{URL: "http://api-1:8080", Weight: 1}, {URL: "http://api-2:8080", Weight: 1}},
+	}}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	if err := fs.CreateMiddleware(&models.Middleware{ID: "auth", Type: "basicAuth", Config: map[string]interface{}{"users": []string{"admin:x"}}}); err != nil {
+		t.Fatalf("Failed to create middleware: %v", err)
+	}
+	if err := fs.CreateRouter(&models.Router{
+		ID: "api", Rule: "Host(`api.example.com`)", Service: models.Service{ID: "api"},
+		EntryPoints: []string{"websecure"}, Priority: 10, TLS: &models.RouterTLS{CertResolver: "le"},
+	}); err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+
+	routerHandler := NewRouterHandler(fs)
+	serviceHandler := NewServiceHandler(fs)
+	middlewareHandler := NewMiddlewareHandler(fs)
+
+	patch := func(handler echo.HandlerFunc, id, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		if err := handler(c); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		return rec
+	}
+
+	t.Run("JSON Patch Server Weight", func(t *testing.T) {
+		rec := patch(serviceHandler.Patch, "api", jsonpatch.JSONPatchType,
+			`[{"op":"test","path":"/loadBalancer/servers/1/url","value":"http://api-2:8080"},{"op":"replace","path":"/loadBalancer/servers/1/weight","value":5}]`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		service, _ := fs.GetService("api")
+		if service.LoadBalancer.Servers[1].Weight != 5 || service.LoadBalancer.Servers[0].Weight != 1 {
+			t.Fatalf("Expected only the second weight to change, got %+v", service.LoadBalancer.Servers)
+		}
+	})
+
+	t.Run("Merge Patch Router Middlewares", func(t *testing.T) {
+		rec := patch(routerHandler.Patch, "api", jsonpatch.MergePatchType, `{"middlewares":["auth"]}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		router, _ := fs.GetRouter("api")
+		if len(router.Middlewares) != 1 || router.Middlewares[0].ID != "auth" {
+			t.Fatalf("Expected middleware auth, got %+v", router.Middlewares)
+		}
+		if router.Priority != 10 || router.TLS == nil || router.TLS.CertResolver != "le" || router.Service.ID != "api" {
+			t.Fatalf("Expected the other fields to be kept, got %+v", router)
+		}
+	})
+
+	t.Run("Patched Result Is Validated", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			handler  echo.HandlerFunc
+			body     string
+			expected int
+		}{
+			{"Missing Middleware", routerHandler.Patch, `{"middlewares":["missing"]}`, http.StatusBadRequest},
+			{"Removed Rule", routerHandler.Patch, `{"rule":null}`, http.StatusBadRequest},
+			{"Changed ID", routerHandler.Patch, `{"id":"other"}`, http.StatusBadRequest},
+			{"Invalid Service", serviceHandler.Patch, `{"loadBalancer":{"servers":[]}}`, http.StatusBadRequest},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if rec := patch(tt.handler, "api", jsonpatch.MergePatchType, tt.body); rec.Code != tt.expected {
+					t.Fatalf("Expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+				}
+			})
+		}
+
+		router, _ := fs.GetRouter("api")
+		if router.Rule == "" || len(router.Middlewares) != 1 {
+			t.Fatalf("Expected router to be unchanged, got %+v", router)
+		}
+	})
+
+	t.Run("Removed Middleware Type", func(t *testing.T) {
+		for contentType, body := range map[string]string{
+			jsonpatch.MergePatchType: `{"type":null}`,
+			jsonpatch.JSONPatchType:  `[{"op":"remove","path":"/type"}]`,
+		} {
+			if rec := patch(middlewareHandler.Patch, "auth", contentType, body); rec.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status %d for %s, got %d: %s", http.StatusBadRequest, contentType, rec.Code, rec.Body.String())
+			}
+		}
+
+		if middleware, _ := fs.GetMiddleware("auth"); middleware.Type != "basicAuth" {
+			t.Fatalf("Expected the middleware type to be kept, got %q", middleware.Type)
+		}
+	})
+
+	t.Run("Failed Test Operation", func(t *testing.T) {
+		rec := patch(serviceHandler.Patch, "api", jsonpatch.JSONPatchType, `[{"op":"test","path":"/loadBalancer/servers/0/weight","value":7}]`)
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("Expected status %d, got %d", http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("Unsupported Content Type", func(t *testing.T) {
+		rec := patch(serviceHandler.Patch, "api", echo.MIMEApplicationJSON, `{"url":"http://other"}`)
+		if rec.Code != http.StatusUnsupportedMediaType {
+			t.Fatalf("Expected status %d, got %d", http.StatusUnsupportedMediaType, rec.Code)
+		}
+	})
+
+	t.Run("Update Keeps The Fields Of Create", func(t *testing.T) {
+		body := `{"rule":"Host(` + "`api.example.com`" + `)","ruleSyntax":"v3","service":"api","priority":20,` +
+			`"tls":{"certResolver":"le"},"observability":{"accessLogs":true}}`
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("api")
+		if err := routerHandler.Update(c); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		router, _ := fs.GetRouter("api")
+		if router.Priority != 20 || router.RuleSyntax != "v3" || router.TLS == nil || router.TLS.CertResolver != "le" ||
+			router.Observability == nil || !router.Observability.AccessLogs {
+			t.Fatalf("Expected priority, rule syntax, TLS and observability to be updated, got %+v", router)
+		}
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		if rec := patch(routerHandler.Patch, "missing", jsonpatch.MergePatchType, `{}`); rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+// internal/api/handlers/pollers.go
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/pollers"
+)
+
+// ProviderClientHandler lists the Traefik instances polling the provider endpoints
+type ProviderClientHandler struct {
+	Clients *pollers.Tracker
+}
+
+// NewProviderClientHandler creates a new ProviderClientHandler
+func NewProviderClientHandler(clients *pollers.Tracker) *ProviderClientHandler {
+	return &ProviderClientHandler{
+		Clients: clients,
+	}
+}
+
+// List handles the GET /provider/clients endpoint
+func (h *ProviderClientHandler) List(c echo.Context) error {
+	logger.Debug().Msg("Listing provider clients")
+
+	return c.JSON(http.StatusOK, h.Clients.List())
+}
@@ -0,0 +1,49 @@
+// internal/api/handlers/probe.go
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/prober"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// ServiceHealthHandler serves the backend health measured by the manager's prober
+type ServiceHealthHandler struct {
+	BaseHandler
+	Prober *prober.Prober
+}
+
+// NewServiceHealthHandler creates a new ServiceHealthHandler
+func NewServiceHealthHandler(store store.Store, prober *prober.Prober) *ServiceHealthHandler {
+	return &ServiceHealthHandler{
+		BaseHandler: NewBaseHandler(store),
+		Prober:      prober,
+	}
+}
+
+// Get handles the GET /services/:id/health endpoint
+func (h *ServiceHealthHandler) Get(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Getting service health")
+
+	if _, err := h.StoreFor(c).GetService(id); err != nil {
+		if store.IsNotFound(err) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Service not found",
+			})
+		}
+		logger.Error().Err(err).Str("id", id).Msg("Failed to get service")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to get service",
+		})
+	}
+
+	// The prober works on store-wide IDs
+	health := h.Prober.Health(store.QualifiedID(requestNamespace(c), id))
+	health.ServiceID = id
+
+	return c.JSON(http.StatusOK, health)
+}
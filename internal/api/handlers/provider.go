@@ -2,19 +2,49 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/sistemica/traefik-manager/internal/config"
+	"github.com/sistemica/traefik-manager/internal/credentials"
+	"github.com/sistemica/traefik-manager/internal/events"
+	"github.com/sistemica/traefik-manager/internal/labels"
 	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/metrics"
+	customMiddleware "github.com/sistemica/traefik-manager/internal/middleware"
 	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/pollers"
 	"github.com/sistemica/traefik-manager/internal/store"
 	"github.com/sistemica/traefik-manager/internal/traefik"
 )
 
+// HeaderConfigHash is the response header holding the hash of the served configuration,
+// which long polls pass back as the since parameter
+const HeaderConfigHash = "X-Config-Hash"
+
+// defaultProviderMaxWait caps long polls when no maximum is configured
+const defaultProviderMaxWait = time.Minute
+
+// ProviderPolling holds the settings shared by the provider endpoints for serving polls
+type ProviderPolling struct {
+	// Clients optionally records the Traefik instances polling the endpoint
+	Clients *pollers.Tracker
+	// InstanceHeader is the request header identifying the polling Traefik instance
+	InstanceHeader string
+	// Changes wakes up long polls when the store changes, long polls aren't held without it
+	Changes *events.Signal
+	// MaxWait caps the wait parameter of long polls
+	MaxWait time.Duration
+}
+
 // ProviderHandler handles Traefik provider endpoint requests
 type ProviderHandler struct {
 	BaseHandler
+	ProviderPolling
 }
 
 // NewProviderHandler creates a new ProviderHandler
@@ -28,6 +58,11 @@ func NewProviderHandler(store store.Store) *ProviderHandler {
 type ProviderHandlerWithAuth struct {
 	BaseHandler
 	AuthConfig *config.Auth
+	// Keys optionally accepts managed API keys with the provider-only or admin role
+	Keys customMiddleware.KeyAuthenticator
+	ProviderPolling
+
+	credentials *credentials.Set
 }
 
 // NewProviderHandlerWithAuth creates a new ProviderHandler with auth settings
@@ -35,101 +70,342 @@ func NewProviderHandlerWithAuth(store store.Store, authConfig *config.Auth) *Pro
 	return &ProviderHandlerWithAuth{
 		BaseHandler: NewBaseHandler(store),
 		AuthConfig:  authConfig,
+		credentials: providerCredentials(authConfig),
 	}
 }
 
 // GetConfigWithAuth handles the provider endpoint with direct auth check
 func (h *ProviderHandlerWithAuth) GetConfigWithAuth(c echo.Context) error {
 	// Handle authentication if enabled
-	if h.AuthConfig != nil && h.AuthConfig.Enabled {
-		// Check API key
-		apiKey := c.Request().Header.Get(h.AuthConfig.HeaderName)
-		if apiKey == "" {
-			logger.Warn().Str("path", c.Request().URL.Path).Msg("Missing API key")
-			return c.JSON(http.StatusUnauthorized, map[string]string{
-				"error": "API key missing",
-			})
-		}
-
-		if apiKey != h.AuthConfig.Key {
-			logger.Warn().Str("path", c.Request().URL.Path).Msg("Invalid API key")
-			return c.JSON(http.StatusUnauthorized, map[string]string{
-				"error": "Invalid API key",
-			})
-		}
+	if ok, err := checkProviderAuth(c, h.AuthConfig, h.credentials, h.Keys); !ok {
+		return err
 	}
 
 	// After authentication succeeds or if auth is disabled, serve the configuration
 	logger.Debug().Msg("Traefik requesting configuration")
 
-	// Get all resources from store
-	routers, err := h.Store.ListRouters()
-	if err != nil {
-		logger.Error().Err(err).Msg("Failed to list routers")
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to retrieve configuration",
-		})
+	return h.serveStore(c, h.Store)
+}
+
+// GetConfig handles the provider endpoint that Traefik polls for configuration
+func (h *ProviderHandler) GetConfig(c echo.Context) error {
+	logger.Debug().Msg("Traefik requesting configuration")
+
+	return h.serveStore(c, h.Store)
+}
+
+// ProviderTargetHandler serves filtered provider configurations for named Traefik targets
+type ProviderTargetHandler struct {
+	BaseHandler
+	Targets     map[string]providerTarget
+	DefaultAuth *config.Auth
+	// Keys optionally accepts managed API keys with the provider-only or admin role
+	Keys customMiddleware.KeyAuthenticator
+	ProviderPolling
+
+	defaultCredentials *credentials.Set
+}
+
+// providerTarget is a provider target with its parsed label selector
+type providerTarget struct {
+	config.ProviderTarget
+	selector    labels.Selector
+	credentials *credentials.Set
+}
+
+// NewProviderTargetHandler creates a new ProviderTargetHandler for the given targets.
+// Targets without their own auth settings use defaultAuth.
+func NewProviderTargetHandler(store store.Store, targets []config.ProviderTarget, defaultAuth *config.Auth) *ProviderTargetHandler {
+	handler := &ProviderTargetHandler{
+		BaseHandler: NewBaseHandler(store),
+		Targets:     make(map[string]providerTarget, len(targets)),
+		DefaultAuth: defaultAuth,
+
+		defaultCredentials: providerCredentials(defaultAuth),
 	}
 
-	services, err := h.Store.ListServices()
-	if err != nil {
-		logger.Error().Err(err).Msg("Failed to list services")
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to retrieve configuration",
-		})
+	for _, target := range targets {
+		// Selectors are validated when the configuration is loaded
+		selector, err := labels.Parse(target.Selector)
+		if err != nil {
+			logger.Error().Err(err).Str("target", target.Name).Msg("Invalid provider target selector")
+			continue
+		}
+		handler.Targets[target.Name] = providerTarget{
+			ProviderTarget: target,
+			selector:       selector,
+			credentials:    providerCredentials(target.Auth),
+		}
 	}
 
-	middlewares, err := h.Store.ListMiddlewares()
-	if err != nil {
-		logger.Error().Err(err).Msg("Failed to list middlewares")
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to retrieve configuration",
+	return handler
+}
+
+// GetTargetConfig handles the GET /traefik/provider/:target endpoint.
+// It serves only the routers assigned to the target and the services and middlewares they reference.
+func (h *ProviderTargetHandler) GetTargetConfig(c echo.Context) error {
+	name := c.Param("target")
+
+	target, ok := h.Targets[name]
+	if !ok {
+		logger.Warn().Str("target", name).Msg("Unknown provider target")
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Provider target not found",
 		})
 	}
 
-	// Convert to Traefik configuration
-	config := convertToTraefikConfig(routers, services, middlewares)
+	authConfig, keySet := target.Auth, target.credentials
+	if authConfig == nil {
+		authConfig, keySet = h.DefaultAuth, h.defaultCredentials
+	}
+	if ok, err := checkProviderAuth(c, authConfig, keySet, h.Keys); !ok {
+		return err
+	}
+
+	logger.Debug().Str("target", name).Msg("Traefik requesting target configuration")
+
+	return h.serve(c, func() (*traefik.DynamicConfig, error) {
+		// Get all resources from store
+		routers, services, middlewares, err := listProviderResources(h.Store)
+		if err != nil {
+			return nil, err
+		}
+
+		// Keep only the routers assigned to the target and their dependencies
+		routers, services, middlewares = target.filter(routers, services, middlewares)
+
+		config := convertToTraefikConfig(routers, services, middlewares)
 
-	logger.Debug().Int("routers", len(routers)).Int("services", len(services)).Int("middlewares", len(middlewares)).Msg("Configuration served to Traefik")
+		logger.Debug().Str("target", name).Int("routers", len(routers)).Int("services", len(services)).Int("middlewares", len(middlewares)).Msg("Target configuration served to Traefik")
 
-	return c.JSON(http.StatusOK, config)
+		return config, nil
+	})
 }
 
-// GetConfig handles the provider endpoint that Traefik polls for configuration
-func (h *ProviderHandler) GetConfig(c echo.Context) error {
-	logger.Debug().Msg("Traefik requesting configuration")
+// matches returns true if the router is assigned to the target.
+// A router must be on one of the target's entrypoints (if any are configured)
+// and match the target's selector (if one is configured).
+func (t providerTarget) matches(router models.Router) bool {
+	if len(t.EntryPoints) > 0 {
+		found := false
+		for _, want := range t.EntryPoints {
+			for _, ep := range router.EntryPoints {
+				if ep == want {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return t.selector.Matches(router.Labels)
+}
+
+// filter returns the routers assigned to the target together with the services
+// and middlewares they reference, directly or through nested references
+func (t providerTarget) filter(routers []models.Router, services []models.Service, middlewares []models.Middleware) ([]models.Router, []models.Service, []models.Middleware) {
+	deps := newDependencySet(services, middlewares)
 
-	// Get all resources from store
-	routers, err := h.Store.ListRouters()
+	selectedRouters := make([]models.Router, 0)
+	for _, router := range routers {
+		if !t.matches(router) {
+			continue
+		}
+		selectedRouters = append(selectedRouters, router)
+		deps.addRouter(router)
+	}
+
+	return selectedRouters, deps.Services(), deps.Middlewares()
+}
+
+// listProviderResources returns all resources of the store
+func listProviderResources(s store.Store) ([]models.Router, []models.Service, []models.Middleware, error) {
+	routers, err := s.ListRouters()
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to list routers")
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to retrieve configuration",
-		})
+		return nil, nil, nil, err
 	}
 
-	services, err := h.Store.ListServices()
+	services, err := s.ListServices()
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to list services")
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to retrieve configuration",
-		})
+		return nil, nil, nil, err
 	}
 
-	middlewares, err := h.Store.ListMiddlewares()
+	middlewares, err := s.ListMiddlewares()
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to list middlewares")
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to retrieve configuration",
+		return nil, nil, nil, err
+	}
+
+	return routers, services, middlewares, nil
+}
+
+// serveStore serves the configuration of all resources of the store
+func (p ProviderPolling) serveStore(c echo.Context, s store.Store) error {
+	return p.serve(c, func() (*traefik.DynamicConfig, error) {
+		// Get all resources from store
+		routers, services, middlewares, err := listProviderResources(s)
+		if err != nil {
+			return nil, err
+		}
+
+		// Convert to Traefik configuration
+		config := convertToTraefikConfig(routers, services, middlewares)
+
+		logger.Debug().Int("routers", len(routers)).Int("services", len(services)).Int("middlewares", len(middlewares)).Msg("Configuration served to Traefik")
+
+		return config, nil
+	})
+}
+
+// serve writes the rendered configuration and records the poll.
+// The render duration covers reading the store, converting and encoding the configuration.
+//
+// With ?wait=<duration>&since=<hash> the request is held until the configuration's hash differs
+// from since or the wait is over, in which case 304 Not Modified is returned.
+// The hash of the served configuration is returned in the X-Config-Hash header.
+func (p ProviderPolling) serve(c echo.Context, render func() (*traefik.DynamicConfig, error)) error {
+	since := c.QueryParam("since")
+	var wait time.Duration
+	if value := c.QueryParam("wait"); value != "" {
+		var err error
+		if wait, err = time.ParseDuration(value); err != nil || wait < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Invalid wait parameter, expected a duration such as 30s",
+			})
+		}
+		maxWait := p.MaxWait
+		if maxWait <= 0 {
+			maxWait = defaultProviderMaxWait
+		}
+		if wait > maxWait {
+			wait = maxWait
+		}
+	}
+
+	longPoll := wait > 0 && since != "" && p.Changes != nil
+	var timeout <-chan time.Time
+	if longPoll {
+		// Long polls outlive the server's write timeout
+		if err := http.NewResponseController(c.Response()).SetWriteDeadline(time.Time{}); err != nil {
+			logger.Debug().Err(err).Msg("Failed to clear write deadline of long poll")
+		}
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		// Wait on the change before rendering, so a change while rendering isn't missed
+		var changed <-chan struct{}
+		if longPoll {
+			changed = p.Changes.Wait()
+		}
+
+		start := time.Now()
+		config, err := render()
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to retrieve configuration",
+			})
+		}
+
+		data, err := json.Marshal(config)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to encode configuration")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to retrieve configuration",
+			})
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+		c.Response().Header().Set(HeaderConfigHash, hash)
+
+		if !longPoll || hash != since {
+			metrics.ObserveProviderPoll(c.Request().URL.Path, c.RealIP(), time.Since(start), len(data))
+			p.record(c, hash)
+			return c.JSONBlob(http.StatusOK, data)
+		}
+
+		select {
+		case <-changed:
+		case <-timeout:
+			p.record(c, hash)
+			return c.NoContent(http.StatusNotModified)
+		case <-c.Request().Context().Done():
+			return nil
+		}
+	}
+}
+
+// record records a poll of the configuration with the given hash
+func (p ProviderPolling) record(c echo.Context, hash string) {
+	if p.Clients == nil {
+		return
+	}
+
+	poll := pollers.Poll{
+		Endpoint:   c.Request().URL.Path,
+		RemoteAddr: c.RealIP(),
+		UserAgent:  c.Request().UserAgent(),
+		ConfigHash: hash,
+	}
+	if p.InstanceHeader != "" {
+		poll.Instance = c.Request().Header.Get(p.InstanceHeader)
+	}
+	p.Clients.Record(poll)
+}
+
+// providerCredentials returns the keys accepted by a provider auth configuration
+func providerCredentials(authConfig *config.Auth) *credentials.Set {
+	if authConfig == nil {
+		return nil
+	}
+	return credentials.NewSet(authConfig.Key, authConfig.KeyHashes)
+}
+
+// checkProviderAuth validates the provider API key if auth is enabled.
+// Besides the configured keys, managed keys with the provider-only or admin role are accepted.
+// It returns false together with the already written error response if the request is rejected.
+func checkProviderAuth(c echo.Context, authConfig *config.Auth, keySet *credentials.Set, keys customMiddleware.KeyAuthenticator) (bool, error) {
+	if authConfig == nil || !authConfig.Enabled {
+		return true, nil
+	}
+
+	// Check API key
+	apiKey := c.Request().Header.Get(authConfig.HeaderName)
+	if apiKey == "" {
+		logger.Warn().Str("path", c.Request().URL.Path).Msg("Missing API key")
+		metrics.AuthFailures.Inc(metrics.AuthMissingCredentials)
+		return false, c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "API key missing",
 		})
 	}
 
-	// Convert to Traefik configuration
-	config := convertToTraefikConfig(routers, services, middlewares)
+	if !keySet.Verify(apiKey) && !providerKey(apiKey, keys) {
+		logger.Warn().Str("path", c.Request().URL.Path).Msg("Invalid API key")
+		metrics.AuthFailures.Inc(metrics.AuthInvalidCredentials)
+		return false, c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "Invalid API key",
+		})
+	}
 
-	logger.Debug().Int("routers", len(routers)).Int("services", len(services)).Int("middlewares", len(middlewares)).Msg("Configuration served to Traefik")
+	return true, nil
+}
 
-	return c.JSON(http.StatusOK, config)
+// providerKey returns true if the key is a managed API key allowed to fetch the provider configuration
+func providerKey(apiKey string, keys customMiddleware.KeyAuthenticator) bool {
+	if keys == nil {
+		return false
+	}
+	identity, ok := keys.AuthenticateKey(apiKey)
+	if !ok {
+		return false
+	}
+	return identity.Role == models.RoleProviderOnly || identity.Role == models.RoleAdmin
 }
 
 // convertToTraefikConfig converts internal models to Traefik's dynamic configuration
@@ -145,39 +421,49 @@ func convertToTraefikConfig(routers []models.Router, services []models.Service,
 
 	// Convert middlewares
 	for _, mw := range middlewares {
-		traefikMw := convertMiddleware(mw)
-		config.HTTP.Middlewares[mw.ID] = traefikMw
+		name := uniqueProviderName(config.HTTP.Middlewares, "middleware", mw.ID)
+		config.HTTP.Middlewares[name] = convertMiddleware(mw)
 	}
 
 	// Convert services
 	for _, svc := range services {
-		traefikSvc := convertService(svc)
-		config.HTTP.Services[svc.ID] = traefikSvc
+		name := uniqueProviderName(config.HTTP.Services, "service", svc.ID)
+		config.HTTP.Services[name] = convertService(svc)
 	}
 
 	// Convert routers
 	for _, router := range routers {
-		traefikRouter := convertRouter(router)
-		config.HTTP.Routers[router.ID] = traefikRouter
+		name := uniqueProviderName(config.HTTP.Routers, "router", router.ID)
+		config.HTTP.Routers[name] = convertRouter(router)
 	}
 
 	return config
 }
 
+// uniqueProviderName returns the provider name of a resource, warning if another resource of the
+// configuration already has it. Creating such resources is refused, but they may predate the check.
+func uniqueProviderName[T any](existing map[string]*T, resourceType, id string) string {
+	name := store.ProviderName(id)
+	if _, ok := existing[name]; ok {
+		logger.Warn().Str("type", resourceType).Str("id", id).Str("name", name).Msg("Provider name used by several resources, replacing the previous one")
+	}
+	return name
+}
+
 // convertRouter converts a models.Router to a traefik.Router
 func convertRouter(router models.Router) *traefik.Router {
 	traefikRouter := &traefik.Router{
 		EntryPoints: router.EntryPoints,
 		Rule:        router.Rule,
 		Priority:    router.Priority,
-		Service:     router.Service.ID,
+		Service:     store.ProviderName(router.Service.ID),
 	}
 
 	// Convert middlewares (just need the IDs)
 	if router.Middlewares != nil && len(router.Middlewares) > 0 {
 		traefikRouter.Middlewares = make([]string, len(router.Middlewares))
 		for i, mw := range router.Middlewares {
-			traefikRouter.Middlewares[i] = mw.ID
+			traefikRouter.Middlewares[i] = store.ProviderName(mw.ID)
 		}
 	}
 
@@ -291,7 +577,7 @@ func convertService(service models.Service) *traefik.Service {
 			weighted.Services = make([]traefik.WeightedServiceItem, len(service.Weighted.Services))
 			for i, item := range service.Weighted.Services {
 				weighted.Services[i] = traefik.WeightedServiceItem{
-					Name:   item.Name.ID,
+					Name:   store.ProviderName(item.Name.ID),
 					Weight: item.Weight,
 				}
 			}
@@ -314,7 +600,7 @@ func convertService(service models.Service) *traefik.Service {
 	// Handle Mirroring service
 	if service.Mirroring != nil {
 		mirroring := &traefik.MirroringService{
-			Service: service.Mirroring.Service.ID,
+			Service: store.ProviderName(service.Mirroring.Service.ID),
 		}
 
 		// Set mirrorBody default or value
@@ -335,7 +621,7 @@ func convertService(service models.Service) *traefik.Service {
 			mirroring.Mirrors = make([]traefik.MirrorServiceItem, len(service.Mirroring.Mirrors))
 			for i, mirror := range service.Mirroring.Mirrors {
 				mirroring.Mirrors[i] = traefik.MirrorServiceItem{
-					Name:    mirror.Name.ID,
+					Name:    store.ProviderName(mirror.Name.ID),
 					Percent: mirror.Percent,
 				}
 			}
@@ -353,8 +639,8 @@ func convertService(service models.Service) *traefik.Service {
 	// Handle Failover service
 	if service.Failover != nil {
 		failover := &traefik.FailoverService{
-			Service:  service.Failover.Service.ID,
-			Fallback: service.Failover.Fallback.ID,
+			Service:  store.ProviderName(service.Failover.Service.ID),
+			Fallback: store.ProviderName(service.Failover.Fallback.ID),
 		}
 
 		// Convert health check if present
@@ -1,13 +1,24 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/config"
+	"github.com/sistemica/traefik-manager/internal/credentials"
+	"github.com/sistemica/traefik-manager/internal/events"
+	customMiddleware "github.com/sistemica/traefik-manager/internal/middleware"
 	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/pollers"
+	"github.com/sistemica/traefik-manager/internal/store"
 	"github.com/sistemica/traefik-manager/internal/traefik"
 )
 
@@ -119,6 +130,233 @@ func TestProviderHandler(t *testing.T) {
 			t.Fatalf("Expected redirect scheme 'https', got '%s'", testMiddleware.RedirectScheme.Scheme)
 		}
 	})
+
+	// Test that polling Traefik instances are tracked with the configuration they received
+	t.Run("Client Tracking", func(t *testing.T) {
+		tracked := NewProviderHandler(mockStore)
+		tracked.Clients = pollers.NewTracker(time.Minute)
+		tracked.InstanceHeader = "X-Traefik-Instance"
+
+		req := httptest.NewRequest(http.MethodGet, "/traefik/provider", nil)
+		req.RemoteAddr = "192.0.2.20:41000"
+		req.Header.Set("X-Traefik-Instance", "edge-1")
+		req.Header.Set("User-Agent", "traefik/3.0")
+		rec := httptest.NewRecorder()
+		if err := tracked.GetConfig(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+
+		clientHandler := NewProviderClientHandler(tracked.Clients)
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/provider/clients", nil)
+		rec = httptest.NewRecorder()
+		if err := clientHandler.List(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+
+		var clients []models.ProviderClient
+		if err := json.Unmarshal(rec.Body.Bytes(), &clients); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if len(clients) != 1 {
+			t.Fatalf("Expected 1 client, got %d", len(clients))
+		}
+
+		client := clients[0]
+		if client.Instance != "edge-1" || client.RemoteAddr != "192.0.2.20" || client.UserAgent != "traefik/3.0" {
+			t.Fatalf("Unexpected client: %+v", client)
+		}
+		if client.ConfigHash == "" || !client.UpToDate || client.Stale {
+			t.Fatalf("Expected an up to date client with a config hash, got %+v", client)
+		}
+	})
+
+	// Test that polls and the rendered configuration show up in the metrics
+	t.Run("Poll Metrics", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/traefik/provider", nil)
+		req.RemoteAddr = "192.0.2.10:41000"
+		rec := httptest.NewRecorder()
+		if err := handler.GetConfig(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+
+		metricsHandler := NewMetricsHandler(mockStore, nil)
+		req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		rec = httptest.NewRecorder()
+		if err := metricsHandler.Serve(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+
+		body := rec.Body.String()
+		for _, want := range []string{
+			`traefik_manager_provider_polls_total{endpoint="/traefik/provider",client="192.0.2.10"}`,
+			`traefik_manager_provider_last_poll_timestamp_seconds{endpoint="/traefik/provider",client="192.0.2.10"}`,
+			`traefik_manager_provider_render_size_bytes{endpoint="/traefik/provider"}`,
+			`traefik_manager_resources{environment="default",type="routers"} 2`,
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("Expected %q in metrics:\n%s", want, body)
+			}
+		}
+	})
+}
+
+// TestProviderTargetHandler tests the per-target provider endpoint
+func TestProviderTargetHandler(t *testing.T) {
+	e := echo.New()
+
+	mockStore := NewMockStore()
+	setupTestData(t, mockStore)
+
+	// Assign the API router to the internal fleet via labels
+	apiRouter := mockStore.routers["api-router"]
+	apiRouter.Labels = models.Labels{"fleet": "internal"}
+	mockStore.routers["api-router"] = apiRouter
+
+	handler := NewProviderTargetHandler(mockStore, []config.ProviderTarget{
+		{Name: "edge", EntryPoints: []string{"web"}},
+		{Name: "internal", Selector: "fleet=internal", Auth: &config.Auth{Enabled: true, HeaderName: "X-API-Key", Key: "internal-key"}},
+	}, nil)
+
+	getTarget := func(name, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/traefik/provider/"+name, nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("target")
+		c.SetParamValues(name)
+
+		if err := handler.GetTargetConfig(c); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		return rec
+	}
+
+	t.Run("Filter By EntryPoint", func(t *testing.T) {
+		rec := getTarget("edge", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		var config traefik.DynamicConfig
+		if err := json.Unmarshal(rec.Body.Bytes(), &config); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+
+		if len(config.HTTP.Routers) != 1 || config.HTTP.Routers["test-router"] == nil {
+			t.Fatalf("Expected only test-router, got %v", config.HTTP.Routers)
+		}
+		if len(config.HTTP.Services) != 1 || config.HTTP.Services["test-service"] == nil {
+			t.Fatalf("Expected only test-service, got %v", config.HTTP.Services)
+		}
+		if len(config.HTTP.Middlewares) != 1 || config.HTTP.Middlewares["test-middleware"] == nil {
+			t.Fatalf("Expected only test-middleware, got %v", config.HTTP.Middlewares)
+		}
+	})
+
+	t.Run("Filter By Selector With Auth", func(t *testing.T) {
+		if rec := getTarget("internal", ""); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected status code %d without key, got %d", http.StatusUnauthorized, rec.Code)
+		}
+
+		rec := getTarget("internal", "internal-key")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		var config traefik.DynamicConfig
+		if err := json.Unmarshal(rec.Body.Bytes(), &config); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+
+		if len(config.HTTP.Routers) != 1 || config.HTTP.Routers["api-router"] == nil {
+			t.Fatalf("Expected only api-router, got %v", config.HTTP.Routers)
+		}
+		if len(config.HTTP.Services) != 1 || config.HTTP.Services["load-balanced-service"] == nil {
+			t.Fatalf("Expected only load-balanced-service, got %v", config.HTTP.Services)
+		}
+		if len(config.HTTP.Middlewares) != 1 || config.HTTP.Middlewares["strip-prefix"] == nil {
+			t.Fatalf("Expected only strip-prefix, got %v", config.HTTP.Middlewares)
+		}
+	})
+
+	t.Run("Managed Provider Key", func(t *testing.T) {
+		handler.Keys = providerKeys{
+			"provider-key": {Name: "traefik", Role: models.RoleProviderOnly},
+			"reader-key":   {Name: "reader", Role: models.RoleReader},
+		}
+		defer func() { handler.Keys = nil }()
+
+		if rec := getTarget("internal", "provider-key"); rec.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d with provider-only key, got %d", http.StatusOK, rec.Code)
+		}
+		if rec := getTarget("internal", "reader-key"); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected status code %d with reader key, got %d", http.StatusUnauthorized, rec.Code)
+		}
+	})
+
+	t.Run("Hashed Key With Rotation Overlap", func(t *testing.T) {
+		oldDigest := sha256.Sum256([]byte("old-key"))
+		newDigest := sha256.Sum256([]byte("new-key"))
+		rotating := NewProviderTargetHandler(mockStore, []config.ProviderTarget{
+			{Name: "fleet", Auth: &config.Auth{Enabled: true, HeaderName: "X-API-Key", KeyHashes: []credentials.Credential{
+				{Hash: "sha256:" + hex.EncodeToString(newDigest[:])},
+				{Hash: "sha256:" + hex.EncodeToString(oldDigest[:]), NotAfter: time.Now().Add(time.Hour)},
+			}}},
+		}, nil)
+
+		for key, expected := range map[string]int{"old-key": http.StatusOK, "new-key": http.StatusOK, "other-key": http.StatusUnauthorized} {
+			req := httptest.NewRequest(http.MethodGet, "/traefik/provider/fleet", nil)
+			req.Header.Set("X-API-Key", key)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("target")
+			c.SetParamValues("fleet")
+
+			if err := rotating.GetTargetConfig(c); err != nil {
+				t.Fatalf("Handler returned error: %v", err)
+			}
+			if rec.Code != expected {
+				t.Errorf("Expected status code %d for %s, got %d", expected, key, rec.Code)
+			}
+		}
+	})
+
+	t.Run("Unknown Target", func(t *testing.T) {
+		if rec := getTarget("staging", ""); rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected status code %d, got %d", http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+// TestProviderNamespacedNames tests that namespaced resources are prefixed in the provider output
+func TestProviderNamespacedNames(t *testing.T) {
+	routers := []models.Router{
+		{ID: "team-a/api", Rule: "Host(`api`)", Service: models.Service{ID: "team-a/api"}, Middlewares: []models.Middleware{{ID: "team-b/auth"}}},
+	}
+	services := []models.Service{
+		{ID: "team-a/api", URL: "http://api:8080"},
+	}
+	middlewares := []models.Middleware{
+		{ID: "team-b/auth", Type: "basicAuth", Config: map[string]interface{}{}},
+	}
+
+	config := convertToTraefikConfig(routers, services, middlewares)
+
+	router, ok := config.HTTP.Routers["team-a-api"]
+	if !ok {
+		t.Fatalf("Expected router 'team-a-api', got %v", config.HTTP.Routers)
+	}
+	if router.Service != "team-a-api" || router.Middlewares[0] != "team-b-auth" {
+		t.Fatalf("Expected prefixed references, got service '%s' and middlewares %v", router.Service, router.Middlewares)
+	}
+	if _, ok := config.HTTP.Services["team-a-api"]; !ok {
+		t.Fatalf("Expected service 'team-a-api', got %v", config.HTTP.Services)
+	}
+	if _, ok := config.HTTP.Middlewares["team-b-auth"]; !ok {
+		t.Fatalf("Expected middleware 'team-b-auth', got %v", config.HTTP.Middlewares)
+	}
 }
 
 // setupTestData adds test data to the mock store
@@ -217,3 +455,81 @@ func setupTestData(t *testing.T, mockStore *MockStore) {
 		t.Fatalf("Failed to create API router: %v", err)
 	}
 }
+
+// providerKeys is a KeyAuthenticator backed by a fixed set of identities
+type providerKeys map[string]*customMiddleware.Identity
+
+func (k providerKeys) AuthenticateKey(key string) (*customMiddleware.Identity, bool) {
+	identity, ok := k[key]
+	return identity, ok
+}
+
+// TestProviderLongPolling tests that polls with wait and since are held until the configuration changes
+func TestProviderLongPolling(t *testing.T) {
+	e := echo.New()
+
+	fs, err := store.NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	handler := NewProviderHandler(fs)
+	handler.Changes = events.NewSignal()
+	fs.OnChange(handler.Changes.Notify)
+
+	poll := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/traefik/provider"+query, nil)
+		rec := httptest.NewRecorder()
+		if err := handler.GetConfig(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		return rec
+	}
+
+	rec := poll("")
+	hash := rec.Header().Get(HeaderConfigHash)
+	if rec.Code != http.StatusOK || hash == "" {
+		t.Fatalf("Expected config with hash, got status %d and hash %q", rec.Code, hash)
+	}
+
+	t.Run("Not Modified After Wait", func(t *testing.T) {
+		start := time.Now()
+		rec := poll("?wait=50ms&since=" + hash)
+		if rec.Code != http.StatusNotModified {
+			t.Fatalf("Expected status %d, got %d", http.StatusNotModified, rec.Code)
+		}
+		if time.Since(start) < 50*time.Millisecond {
+			t.Fatal("Expected the poll to be held for the wait duration")
+		}
+	})
+
+	t.Run("Returns On Change", func(t *testing.T) {
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			fs.CreateService(&models.Service{ID: "api", URL: "http://api:8080"})
+		}()
+
+		start := time.Now()
+		rec := poll("?wait=10s&since=" + hash)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("Expected the poll to return when the configuration changed")
+		}
+		if rec.Header().Get(HeaderConfigHash) == hash || !strings.Contains(rec.Body.String(), "http://api:8080") {
+			t.Fatalf("Expected the changed configuration, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("Different Hash Returns Immediately", func(t *testing.T) {
+		if rec := poll("?wait=10s&since=outdated"); rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("Invalid Wait", func(t *testing.T) {
+		if rec := poll("?wait=soon&since=" + hash); rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+// internal/api/handlers/reconcile.go
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/reconcile"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// StatusHandler serves the state of resources as reported by the reconciled Traefik instances
+type StatusHandler struct {
+	BaseHandler
+	Reconciler *reconcile.Reconciler
+}
+
+// NewStatusHandler creates a new StatusHandler
+func NewStatusHandler(store store.Store, reconciler *reconcile.Reconciler) *StatusHandler {
+	return &StatusHandler{
+		BaseHandler: NewBaseHandler(store),
+		Reconciler:  reconciler,
+	}
+}
+
+// RouterStatus handles the GET /routers/:id/status endpoint
+func (h *StatusHandler) RouterStatus(c echo.Context) error {
+	id := c.Param("id")
+	if _, err := h.StoreFor(c).GetRouter(id); err != nil {
+		return h.notFound(c, err, id, "Router")
+	}
+	return c.JSON(http.StatusOK, h.status(c, reconcile.TypeRouters, id))
+}
+
+// ServiceStatus handles the GET /services/:id/status endpoint
+func (h *StatusHandler) ServiceStatus(c echo.Context) error {
+	id := c.Param("id")
+	if _, err := h.StoreFor(c).GetService(id); err != nil {
+		return h.notFound(c, err, id, "Service")
+	}
+	return c.JSON(http.StatusOK, h.status(c, reconcile.TypeServices, id))
+}
+
+// MiddlewareStatus handles the GET /middlewares/:id/status endpoint
+func (h *StatusHandler) MiddlewareStatus(c echo.Context) error {
+	id := c.Param("id")
+	if _, err := h.StoreFor(c).GetMiddleware(id); err != nil {
+		return h.notFound(c, err, id, "Middleware")
+	}
+	return c.JSON(http.StatusOK, h.status(c, reconcile.TypeMiddlewares, id))
+}
+
+// Report handles the GET /reconcile endpoint listing the instances and all rejected resources
+func (h *StatusHandler) Report(c echo.Context) error {
+	s := h.StoreFor(c)
+
+	routers, err := s.ListRouters()
+	if err != nil {
+		return h.listFailed(c, err)
+	}
+	services, err := s.ListServices()
+	if err != nil {
+		return h.listFailed(c, err)
+	}
+	middlewares, err := s.ListMiddlewares()
+	if err != nil {
+		return h.listFailed(c, err)
+	}
+
+	ids := map[string][]string{}
+	for _, router := range routers {
+		ids[reconcile.TypeRouters] = append(ids[reconcile.TypeRouters], router.ID)
+	}
+	for _, service := range services {
+		ids[reconcile.TypeServices] = append(ids[reconcile.TypeServices], service.ID)
+	}
+	for _, middleware := range middlewares {
+		ids[reconcile.TypeMiddlewares] = append(ids[reconcile.TypeMiddlewares], middleware.ID)
+	}
+
+	report := models.ReconcileReport{
+		Instances: h.Reconciler.Instances(),
+		Rejected:  []models.ResourceStatus{},
+	}
+	for _, resourceType := range []string{reconcile.TypeRouters, reconcile.TypeServices, reconcile.TypeMiddlewares} {
+		for _, id := range ids[resourceType] {
+			if status := h.status(c, resourceType, id); status.Rejected {
+				report.Rejected = append(report.Rejected, status)
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// status returns the state of a resource identified by its ID as seen by the caller
+func (h *StatusHandler) status(c echo.Context, resourceType, id string) models.ResourceStatus {
+	name := store.ProviderName(store.QualifiedID(requestNamespace(c), id))
+
+	status := models.ResourceStatus{
+		ID:          id,
+		Type:        resourceType,
+		TraefikName: name + reconcile.ProviderSuffix,
+		Instances:   h.Reconciler.Status(resourceType, name),
+	}
+	for _, instance := range status.Instances {
+		if instance.Rejected {
+			status.Rejected = true
+		}
+	}
+	return status
+}
+
+// listFailed writes the error response for a failed resource listing
+func (h *StatusHandler) listFailed(c echo.Context, err error) error {
+	logger.Error().Err(err).Msg("Failed to list resources")
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "Failed to list resources",
+	})
+}
+
+// notFound writes the error response for a failed resource lookup
+func (h *StatusHandler) notFound(c echo.Context, err error, id, kind string) error {
+	if store.IsNotFound(err) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": kind + " not found",
+		})
+	}
+	logger.Error().Err(err).Str("id", id).Msg("Failed to get resource")
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "Failed to get resource",
+	})
+}
@@ -0,0 +1,72 @@
+// internal/api/handlers/rename.go
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// rename changes the ID of a resource with the given store method and writes the response.
+// The new ID and the resources whose references are rewritten are limited to the scopes of the caller.
+func rename(c echo.Context, resourceName, id string,
+	renameFn func(id, newID string, authorize store.Authorizer) ([]models.ResourceRef, error)) error {
+	logger.Debug().Str("id", id).Msgf("Renaming %s", strings.ToLower(resourceName))
+
+	var request models.RenameRequest
+	if err := c.Bind(&request); err != nil {
+		logger.Warn().Err(err).Msg("Invalid rename data")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid rename data",
+		})
+	}
+	if request.ID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "New " + strings.ToLower(resourceName) + " ID is required",
+		})
+	}
+
+	updated, err := renameFn(id, request.ID, requestAuthorizer(c))
+	if err != nil {
+		logger.Warn().Err(err).Str("id", id).Str("new_id", request.ID).Msgf("Failed to rename %s", strings.ToLower(resourceName))
+		switch {
+		case store.IsNotFound(err):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": resourceName + " not found",
+			})
+		case store.IsAlreadyExists(err):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": resourceName + " " + request.ID + " already exists",
+			})
+		case store.IsForbidden(err):
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": "Renaming " + strings.ToLower(resourceName) + " " + id + " reaches resources outside the scopes of the caller",
+			})
+		case store.IsInvalidID(err) || store.IsValidationError(err):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		case store.IsResourceInUse(err):
+			// Resources of other namespaces referencing the resource can't be rewritten
+			return c.JSON(http.StatusConflict, map[string]interface{}{
+				"error":        resourceName + " is in use by resources of other namespaces and cannot be renamed",
+				"dependencies": store.GetDependencies(err),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to rename " + strings.ToLower(resourceName),
+		})
+	}
+
+	logger.Info().Str("id", id).Str("new_id", request.ID).Int("updated", len(updated)).Msgf("%s renamed", resourceName)
+
+	return c.JSON(http.StatusOK, models.RenameResponse{
+		ID:         request.ID,
+		PreviousID: id,
+		Updated:    updated,
+	})
+}
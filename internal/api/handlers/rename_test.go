@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	customMiddleware "github.com/sistemica/traefik-manager/internal/middleware"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// TestRename tests renaming services and middlewares with the references to them
+func TestRename(t *testing.T) {
+	e := echo.New()
+
+	fs, err := store.NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	fs.CreateService(&models.Service{ID: "api", URL: "http://api:8080"})
+	fs.CreateService(&models.Service{ID: "web", URL: "http://web:8080"})
+	fs.CreateMiddleware(&models.Middleware{ID: "auth", Type: "basicAuth", Config: map[string]interface{}{}})
+	fs.CreateRouter(&models.Router{ID: "api", Rule: "Host(`api.example.com`)", Service: models.Service{ID: "api"}, Middlewares: []models.Middleware{{ID: "auth"}}})
+
+	rename := func(handler echo.HandlerFunc, resource, id, body string, identity ...*customMiddleware.Identity) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/"+resource+"/"+id+"/rename", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if len(identity) > 0 {
+			customMiddleware.SetIdentity(c, identity[0])
+		}
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		if err := handler(c); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		return rec
+	}
+	services, middlewares := NewServiceHandler(fs), NewMiddlewareHandler(fs)
+
+	t.Run("Service", func(t *testing.T) {
+		rec := rename(services.Rename, "services", "api", `{"id":"backend"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		var response models.RenameResponse
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if response.ID != "backend" || response.PreviousID != "api" || len(response.Updated) != 1 || response.Updated[0].ResourceType != "router" {
+			t.Fatalf("Expected the router to be updated, got %+v", response)
+		}
+	})
+
+	t.Run("Middleware", func(t *testing.T) {
+		rec := rename(middlewares.Rename, "middlewares", "auth", `{"id":"basic-auth"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		if router, _ := fs.GetRouter("api"); router.Middlewares[0].ID != "basic-auth" {
+			t.Fatalf("Expected the router to be rewritten, got %+v", router.Middlewares)
+		}
+	})
+
+	t.Run("Out Of Scope", func(t *testing.T) {
+		scoped := func(scopes ...models.APIKeyScope) *customMiddleware.Identity {
+			return &customMiddleware.Identity{Name: "scoped", Role: models.RoleEditor, Scopes: scopes}
+		}
+		backendOnly := scoped(models.APIKeyScope{ResourceType: "services", IDPattern: "backend"})
+		allServices := scoped(models.APIKeyScope{ResourceType: "services", IDPattern: "*"})
+
+		// The new ID and the router referencing the service must be in scope
+		if rec := rename(services.Rename, "services", "backend", `{"id":"other"}`, backendOnly); rec.Code != http.StatusForbidden {
+			t.Fatalf("Expected status %d for the new ID, got %d: %s", http.StatusForbidden, rec.Code, rec.Body.String())
+		}
+		if rec := rename(services.Rename, "services", "backend", `{"id":"other"}`, allServices); rec.Code != http.StatusForbidden {
+			t.Fatalf("Expected status %d for the router, got %d: %s", http.StatusForbidden, rec.Code, rec.Body.String())
+		}
+		if exists, _ := fs.ServiceExists("backend"); !exists {
+			t.Fatal("Expected the service to keep its ID")
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			id       string
+			body     string
+			expected int
+		}{
+			{"Missing New ID", "backend", `{}`, http.StatusBadRequest},
+			{"Not Found", "api", `{"id":"other"}`, http.StatusNotFound},
+			{"Already Exists", "backend", `{"id":"web"}`, http.StatusConflict},
+			{"Other Namespace", "backend", `{"id":"team-a/backend"}`, http.StatusBadRequest},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if rec := rename(services.Rename, "services", tt.id, tt.body); rec.Code != tt.expected {
+					t.Fatalf("Expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+				}
+			})
+		}
+	})
+}
@@ -3,7 +3,9 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -24,11 +26,30 @@ func NewRouterHandler(store store.Store) *RouterHandler {
 	}
 }
 
-// List handles the GET /routers endpoint to list all routers
+// List handles the GET /routers endpoint to list all routers.
+// Routers can be filtered by entrypoint, service, middleware, rule substring, tls,
+// label selector and updatedSince, and are paginated with limit and cursor.
 func (h *RouterHandler) List(c echo.Context) error {
 	logger.Debug().Msg("Listing routers")
 
-	routers, err := h.Store.ListRouters()
+	options, err := parseListOptions(c)
+	if err != nil {
+		return listError(c, err)
+	}
+	hasTLS, err := parseBoolFilter(c, "tls")
+	if err != nil {
+		return listError(c, err)
+	}
+	entryPoint := c.QueryParam("entrypoint")
+	service := c.QueryParam("service")
+	middleware := c.QueryParam("middleware")
+	rule := c.QueryParam("rule")
+	selector, err := parseSelector(c)
+	if err != nil {
+		return listError(c, err)
+	}
+
+	routers, err := h.StoreFor(c).ListRouters()
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to list routers")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -36,7 +57,35 @@ func (h *RouterHandler) List(c echo.Context) error {
 		})
 	}
 
-	return c.JSON(http.StatusOK, routers)
+	filtered := make([]models.Router, 0, len(routers))
+	for _, router := range routers {
+		if entryPoint != "" && !containsString(router.EntryPoints, entryPoint) {
+			continue
+		}
+		if service != "" && router.Service.ID != service {
+			continue
+		}
+		if middleware != "" && !usesMiddleware(router, middleware) {
+			continue
+		}
+		if rule != "" && !strings.Contains(router.Rule, rule) {
+			continue
+		}
+		if hasTLS != nil && (router.TLS != nil) != *hasTLS {
+			continue
+		}
+		if !selector.Matches(router.Labels) {
+			continue
+		}
+		if !options.matches(router.ResourceMeta) {
+			continue
+		}
+		filtered = append(filtered, router)
+	}
+
+	return c.JSON(http.StatusOK, paginate(c, filtered, options, func(router models.Router) string {
+		return options.key(router.ID, router.ResourceMeta)
+	}))
 }
 
 // Get handles the GET /routers/:id endpoint to get a specific router
@@ -44,7 +93,7 @@ func (h *RouterHandler) Get(c echo.Context) error {
 	id := c.Param("id")
 	logger.Debug().Str("id", id).Msg("Getting router")
 
-	router, err := h.Store.GetRouter(id)
+	router, err := h.StoreFor(c).GetRouter(id)
 	if err != nil {
 		if store.IsNotFound(err) {
 			logger.Debug().Str("id", id).Msg("Router not found")
@@ -78,130 +127,7 @@ func (h *RouterHandler) Create(c echo.Context) error {
 		})
 	}
 
-	// Create router object
-	var router models.Router
-
-	// Set ID, rule, and entryPoints
-	if id, ok := requestData["id"].(string); ok {
-		router.ID = id
-	}
-
-	if rule, ok := requestData["rule"].(string); ok {
-		router.Rule = rule
-	}
-
-	if entryPoints, ok := requestData["entryPoints"].([]interface{}); ok {
-		router.EntryPoints = make([]string, len(entryPoints))
-		for i, ep := range entryPoints {
-			if epStr, ok := ep.(string); ok {
-				router.EntryPoints[i] = epStr
-			}
-		}
-	}
-
-	// Handle service field - can be either a string (ID) or an object
-	serviceField := requestData["service"]
-	if serviceID, ok := serviceField.(string); ok {
-		// If service is a string, create a Service with just the ID
-		router.Service = models.Service{
-			ID: serviceID,
-		}
-	} else if serviceMap, ok := serviceField.(map[string]interface{}); ok {
-		// If service is an object, extract the ID
-		if serviceID, ok := serviceMap["id"].(string); ok {
-			router.Service = models.Service{
-				ID: serviceID,
-			}
-		}
-	}
-
-	// Handle middlewares field - can be an array of strings or objects
-	if middlewaresField, ok := requestData["middlewares"].([]interface{}); ok {
-		router.Middlewares = make([]models.Middleware, 0, len(middlewaresField))
-
-		for _, mw := range middlewaresField {
-			if mwID, ok := mw.(string); ok {
-				// If middleware is a string, create a Middleware with just the ID
-				router.Middlewares = append(router.Middlewares, models.Middleware{
-					ID: mwID,
-				})
-			} else if mwMap, ok := mw.(map[string]interface{}); ok {
-				// If middleware is an object, extract the ID
-				if mwID, ok := mwMap["id"].(string); ok {
-					router.Middlewares = append(router.Middlewares, models.Middleware{
-						ID: mwID,
-					})
-				}
-			}
-		}
-	}
-
-	// Handle Priority
-	if priority, ok := requestData["priority"].(float64); ok {
-		router.Priority = int(priority)
-	}
-
-	// Handle TLS if present
-	if tlsField, ok := requestData["tls"].(map[string]interface{}); ok {
-		tls := &models.RouterTLS{}
-
-		if options, ok := tlsField["options"].(string); ok {
-			tls.Options = options
-		}
-
-		if certResolver, ok := tlsField["certResolver"].(string); ok {
-			tls.CertResolver = certResolver
-		}
-
-		if domainsField, ok := tlsField["domains"].([]interface{}); ok {
-			domains := make([]models.Domain, 0, len(domainsField))
-
-			for _, d := range domainsField {
-				if domainMap, ok := d.(map[string]interface{}); ok {
-					domain := models.Domain{}
-
-					if main, ok := domainMap["main"].(string); ok {
-						domain.Main = main
-					}
-
-					if sansField, ok := domainMap["sans"].([]interface{}); ok {
-						sans := make([]string, 0, len(sansField))
-						for _, s := range sansField {
-							if san, ok := s.(string); ok {
-								sans = append(sans, san)
-							}
-						}
-						domain.Sans = sans
-					}
-
-					domains = append(domains, domain)
-				}
-			}
-
-			tls.Domains = domains
-		}
-
-		router.TLS = tls
-	}
-
-	// Handle Observability if present
-	if obsField, ok := requestData["observability"].(map[string]interface{}); ok {
-		obs := &models.Observability{}
-
-		if accessLogs, ok := obsField["accessLogs"].(bool); ok {
-			obs.AccessLogs = accessLogs
-		}
-
-		if tracing, ok := obsField["tracing"].(bool); ok {
-			obs.Tracing = tracing
-		}
-
-		if metrics, ok := obsField["metrics"].(bool); ok {
-			obs.Metrics = metrics
-		}
-
-		router.Observability = obs
-	}
+	router := parseRouter(requestData)
 
 	// Validate router
 	if router.ID == "" {
@@ -231,7 +157,7 @@ func (h *RouterHandler) Create(c echo.Context) error {
 	serviceErrorChan := make(chan error, 1)
 
 	go func() {
-		exists, err := h.Store.ServiceExists(router.Service.ID)
+		exists, err := h.StoreFor(c).ServiceExists(router.Service.ID)
 		serviceExistsChan <- exists
 		serviceErrorChan <- err
 	}()
@@ -267,7 +193,7 @@ func (h *RouterHandler) Create(c echo.Context) error {
 			middlewareErrorChan := make(chan error, 1)
 
 			go func(mwID string) {
-				exists, err := h.Store.MiddlewareExists(mwID)
+				exists, err := h.StoreFor(c).MiddlewareExists(mwID)
 				middlewareExistsChan <- exists
 				middlewareErrorChan <- err
 			}(middleware.ID)
@@ -303,7 +229,7 @@ func (h *RouterHandler) Create(c echo.Context) error {
 	routerErrorChan := make(chan error, 1)
 
 	go func() {
-		exists, err := h.Store.RouterExists(router.ID)
+		exists, err := h.StoreFor(c).RouterExists(router.ID)
 		routerExistsChan <- exists
 		routerErrorChan <- err
 	}()
@@ -335,7 +261,7 @@ func (h *RouterHandler) Create(c echo.Context) error {
 	// Create the router
 	createChan := make(chan error, 1)
 	go func() {
-		createChan <- h.Store.CreateRouter(&router)
+		createChan <- h.StoreFor(c).CreateRouter(&router)
 	}()
 
 	// Wait for creation with timeout
@@ -348,6 +274,11 @@ func (h *RouterHandler) Create(c echo.Context) error {
 	case err := <-createChan:
 		if err != nil {
 			logger.Error().Err(err).Str("id", router.ID).Msg("Failed to create router")
+			if store.IsValidationError(err) || store.IsInvalidID(err) {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": err.Error(),
+				})
+			}
 			return c.JSON(http.StatusInternalServerError, map[string]string{
 				"error": "Failed to create router",
 			})
@@ -381,61 +312,10 @@ func (h *RouterHandler) Update(c echo.Context) error {
 		})
 	}
 
-	// Create router object
-	var router models.Router
+	// Parse the router like on creation, the ID is taken from the path
+	router := parseRouter(requestData)
 	router.ID = id
 
-	// Set rule and entryPoints
-	if rule, ok := requestData["rule"].(string); ok {
-		router.Rule = rule
-	}
-
-	if entryPoints, ok := requestData["entryPoints"].([]interface{}); ok {
-		router.EntryPoints = make([]string, len(entryPoints))
-		for i, ep := range entryPoints {
-			if epStr, ok := ep.(string); ok {
-				router.EntryPoints[i] = epStr
-			}
-		}
-	}
-
-	// Handle service field - can be either a string (ID) or an object
-	serviceField := requestData["service"]
-	if serviceID, ok := serviceField.(string); ok {
-		// If service is a string, create a Service with just the ID
-		router.Service = models.Service{
-			ID: serviceID,
-		}
-	} else if serviceMap, ok := serviceField.(map[string]interface{}); ok {
-		// If service is an object, extract the ID
-		if serviceID, ok := serviceMap["id"].(string); ok {
-			router.Service = models.Service{
-				ID: serviceID,
-			}
-		}
-	}
-
-	// Handle middlewares field - can be an array of strings or objects
-	if middlewaresField, ok := requestData["middlewares"].([]interface{}); ok {
-		router.Middlewares = make([]models.Middleware, 0, len(middlewaresField))
-
-		for _, mw := range middlewaresField {
-			if mwID, ok := mw.(string); ok {
-				// If middleware is a string, create a Middleware with just the ID
-				router.Middlewares = append(router.Middlewares, models.Middleware{
-					ID: mwID,
-				})
-			} else if mwMap, ok := mw.(map[string]interface{}); ok {
-				// If middleware is an object, extract the ID
-				if mwID, ok := mwMap["id"].(string); ok {
-					router.Middlewares = append(router.Middlewares, models.Middleware{
-						ID: mwID,
-					})
-				}
-			}
-		}
-	}
-
 	// Validate required fields
 	if router.Rule == "" {
 		logger.Warn().Msg("Router rule is required")
@@ -445,7 +325,7 @@ func (h *RouterHandler) Update(c echo.Context) error {
 	}
 
 	// Update the router with validation in a single operation
-	err := h.Store.UpdateRouter(id, &router)
+	err := h.StoreFor(c).UpdateRouter(id, &router)
 	if err != nil {
 		if store.IsNotFound(err) {
 			logger.Warn().Str("id", id).Msg("Router not found")
@@ -473,6 +353,79 @@ func (h *RouterHandler) Update(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+// Patch handles the PATCH /routers/:id endpoint to partially update a router.
+// The body is a JSON Merge Patch or a JSON Patch applied to the current router.
+func (h *RouterHandler) Patch(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Patching router")
+
+	current, err := h.StoreFor(c).GetRouter(id)
+	if err != nil {
+		if store.IsNotFound(err) {
+			logger.Warn().Str("id", id).Msg("Router not found")
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Router not found",
+			})
+		}
+		logger.Error().Err(err).Str("id", id).Msg("Failed to get router")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to get router",
+		})
+	}
+
+	patched, patchErr := applyPatch(c, current)
+	if patchErr != nil {
+		return patchErr.respond(c)
+	}
+
+	var requestData map[string]interface{}
+	if err := json.Unmarshal(patched, &requestData); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Patched router must be a JSON object",
+		})
+	}
+
+	// The patched router is validated like a new one
+	router := parseRouter(requestData)
+	if router.ID != id {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Router ID cannot be changed",
+		})
+	}
+
+	if router.Rule == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Router rule is required",
+		})
+	}
+
+	if router.Service.ID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Router service ID is required",
+		})
+	}
+
+	if err := h.StoreFor(c).UpdateRouter(id, &router); err != nil {
+		if store.IsNotFound(err) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Router not found",
+			})
+		}
+
+		logger.Error().Err(err).Str("id", id).Msg("Failed to patch router")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	logger.Info().Str("id", id).Msg("Router patched")
+
+	return c.JSON(http.StatusOK, models.ResourceResponse{
+		ID:      id,
+		Updated: true,
+	})
+}
+
 // Delete handles the DELETE /routers/:id endpoint to delete a router
 func (h *RouterHandler) Delete(c echo.Context) error {
 	id := c.Param("id")
@@ -487,7 +440,7 @@ func (h *RouterHandler) Delete(c echo.Context) error {
 	routerErrorChan := make(chan error, 1)
 
 	go func() {
-		exists, err := h.Store.RouterExists(id)
+		exists, err := h.StoreFor(c).RouterExists(id)
 		routerExistsChan <- exists
 		routerErrorChan <- err
 	}()
@@ -522,7 +475,7 @@ func (h *RouterHandler) Delete(c echo.Context) error {
 	routerInUseErrorChan := make(chan error, 1)
 
 	go func() {
-		inUse, usedBy, err := h.Store.RouterInUse(id)
+		inUse, usedBy, err := h.StoreFor(c).RouterInUse(id)
 		routerInUseChan <- inUse
 		routerUsedByChan <- usedBy
 		routerInUseErrorChan <- err
@@ -549,16 +502,13 @@ func (h *RouterHandler) Delete(c echo.Context) error {
 
 	if routerInUse {
 		logger.Warn().Str("id", id).Strs("used_by", routerUsedBy).Msg("Router is in use")
-		return c.JSON(http.StatusConflict, map[string]interface{}{
-			"error":   "Router is in use by other resources and cannot be deleted",
-			"used_by": routerUsedBy,
-		})
+		return c.JSON(http.StatusConflict, inUseResponse("Router", usedByDependencies(routerUsedBy)))
 	}
 
 	// Delete the router
 	deleteChan := make(chan error, 1)
 	go func() {
-		deleteChan <- h.Store.DeleteRouter(id)
+		deleteChan <- h.StoreFor(c).DeleteRouter(id)
 	}()
 
 	// Wait for deletion with timeout
@@ -571,17 +521,7 @@ func (h *RouterHandler) Delete(c echo.Context) error {
 	case err := <-deleteChan:
 		if err != nil {
 			logger.Error().Err(err).Str("id", id).Msg("Failed to delete router")
-
-			if store.IsResourceInUse(err) {
-				// This is a fallback for any resource-in-use errors not caught by the explicit check
-				return c.JSON(http.StatusConflict, map[string]string{
-					"error": "Router is in use by other resources and cannot be deleted",
-				})
-			}
-
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "Failed to delete router",
-			})
+			return deleteError(c, "Router", id, err)
 		}
 	}
 
@@ -597,3 +537,177 @@ func (h *RouterHandler) Delete(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, response)
 }
+
+// parseRouter builds a router from a generic JSON object.
+// The service and middlewares can be given as IDs or as objects with an id.
+func parseRouter(requestData map[string]interface{}) models.Router {
+	var router models.Router
+
+	// Set ID, rule, and entryPoints
+	if id, ok := requestData["id"].(string); ok {
+		router.ID = id
+	}
+
+	if rule, ok := requestData["rule"].(string); ok {
+		router.Rule = rule
+	}
+
+	if ruleSyntax, ok := requestData["ruleSyntax"].(string); ok {
+		router.RuleSyntax = ruleSyntax
+	}
+
+	if entryPoints, ok := requestData["entryPoints"].([]interface{}); ok {
+		router.EntryPoints = make([]string, len(entryPoints))
+		for i, ep := range entryPoints {
+			if epStr, ok := ep.(string); ok {
+				router.EntryPoints[i] = epStr
+			}
+		}
+	}
+
+	// Handle service field - can be either a string (ID) or an object
+	serviceField := requestData["service"]
+	if serviceID, ok := serviceField.(string); ok {
+		// If service is a string, create a Service with just the ID
+		router.Service = models.Service{
+			ID: serviceID,
+		}
+	} else if serviceMap, ok := serviceField.(map[string]interface{}); ok {
+		// If service is an object, extract the ID
+		if serviceID, ok := serviceMap["id"].(string); ok {
+			router.Service = models.Service{
+				ID: serviceID,
+			}
+		}
+	}
+
+	// Handle labels and annotations fields
+	if labelsField, ok := requestData["labels"].(map[string]interface{}); ok {
+		router.Labels = parseLabels(labelsField)
+	}
+	if annotationsField, ok := requestData["annotations"].(map[string]interface{}); ok {
+		router.Annotations = models.Annotations(parseLabels(annotationsField))
+	}
+
+	// Handle middlewares field - can be an array of strings or objects
+	if middlewaresField, ok := requestData["middlewares"].([]interface{}); ok {
+		router.Middlewares = make([]models.Middleware, 0, len(middlewaresField))
+
+		for _, mw := range middlewaresField {
+			if mwID, ok := mw.(string); ok {
+				// If middleware is a string, create a Middleware with just the ID
+				router.Middlewares = append(router.Middlewares, models.Middleware{
+					ID: mwID,
+				})
+			} else if mwMap, ok := mw.(map[string]interface{}); ok {
+				// If middleware is an object, extract the ID
+				if mwID, ok := mwMap["id"].(string); ok {
+					router.Middlewares = append(router.Middlewares, models.Middleware{
+						ID: mwID,
+					})
+				}
+			}
+		}
+	}
+
+	// Handle Priority
+	if priority, ok := requestData["priority"].(float64); ok {
+		router.Priority = int(priority)
+	}
+
+	// Handle TLS if present
+	if tlsField, ok := requestData["tls"].(map[string]interface{}); ok {
+		tls := &models.RouterTLS{}
+
+		if options, ok := tlsField["options"].(string); ok {
+			tls.Options = options
+		}
+
+		if certResolver, ok := tlsField["certResolver"].(string); ok {
+			tls.CertResolver = certResolver
+		}
+
+		if domainsField, ok := tlsField["domains"].([]interface{}); ok {
+			domains := make([]models.Domain, 0, len(domainsField))
+
+			for _, d := range domainsField {
+				if domainMap, ok := d.(map[string]interface{}); ok {
+					domain := models.Domain{}
+
+					if main, ok := domainMap["main"].(string); ok {
+						domain.Main = main
+					}
+
+					if sansField, ok := domainMap["sans"].([]interface{}); ok {
+						sans := make([]string, 0, len(sansField))
+						for _, s := range sansField {
+							if san, ok := s.(string); ok {
+								sans = append(sans, san)
+							}
+						}
+						domain.Sans = sans
+					}
+
+					domains = append(domains, domain)
+				}
+			}
+
+			tls.Domains = domains
+		}
+
+		router.TLS = tls
+	}
+
+	// Handle Observability if present
+	if obsField, ok := requestData["observability"].(map[string]interface{}); ok {
+		obs := &models.Observability{}
+
+		if accessLogs, ok := obsField["accessLogs"].(bool); ok {
+			obs.AccessLogs = accessLogs
+		}
+
+		if tracing, ok := obsField["tracing"].(bool); ok {
+			obs.Tracing = tracing
+		}
+
+		if metrics, ok := obsField["metrics"].(bool); ok {
+			obs.Metrics = metrics
+		}
+
+		router.Observability = obs
+	}
+
+	return router
+}
+
+// containsString returns true if the list contains the value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// usesMiddleware returns true if the router references the middleware
+func usesMiddleware(router models.Router, id string) bool {
+	for _, middleware := range router.Middlewares {
+		if middleware.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLabels converts a generic JSON object into labels, ignoring non-string values.
+// It is used for annotations as well.
+func parseLabels(labelsField map[string]interface{}) models.Labels {
+	labels := make(models.Labels, len(labelsField))
+	for key, value := range labelsField {
+		if strValue, ok := value.(string); ok {
+			labels[key] = strValue
+		}
+	}
+	return labels
+}
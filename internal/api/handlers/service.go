@@ -2,6 +2,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -23,11 +24,31 @@ func NewServiceHandler(store store.Store) *ServiceHandler {
 	}
 }
 
-// List handles the GET /services endpoint to list all services
+// List handles the GET /services endpoint to list all services.
+// Services can be filtered by type, label selector and updatedSince and are paginated
+// with limit and cursor.
 func (h *ServiceHandler) List(c echo.Context) error {
 	logger.Debug().Msg("Listing services")
 
-	services, err := h.Store.ListServices()
+	options, err := parseListOptions(c)
+	if err != nil {
+		return listError(c, err)
+	}
+	serviceType := c.QueryParam("type")
+	switch serviceType {
+	case "", serviceTypeLoadBalancer, serviceTypeWeighted, serviceTypeMirroring, serviceTypeFailover:
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid type parameter, expected one of: loadBalancer, weighted, mirroring, failover",
+		})
+	}
+
+	selector, err := parseSelector(c)
+	if err != nil {
+		return listError(c, err)
+	}
+
+	services, err := h.StoreFor(c).ListServices()
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to list services")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -35,7 +56,23 @@ func (h *ServiceHandler) List(c echo.Context) error {
 		})
 	}
 
-	return c.JSON(http.StatusOK, services)
+	filtered := make([]models.Service, 0, len(services))
+	for _, service := range services {
+		if serviceType != "" && serviceKind(service) != serviceType {
+			continue
+		}
+		if !selector.Matches(service.Labels) {
+			continue
+		}
+		if !options.matches(service.ResourceMeta) {
+			continue
+		}
+		filtered = append(filtered, service)
+	}
+
+	return c.JSON(http.StatusOK, paginate(c, filtered, options, func(service models.Service) string {
+		return options.key(service.ID, service.ResourceMeta)
+	}))
 }
 
 // Get handles the GET /services/:id endpoint to get a specific service
@@ -43,7 +80,7 @@ func (h *ServiceHandler) Get(c echo.Context) error {
 	id := c.Param("id")
 	logger.Debug().Str("id", id).Msg("Getting service")
 
-	service, err := h.Store.GetService(id)
+	service, err := h.StoreFor(c).GetService(id)
 	if err != nil {
 		if store.IsNotFound(err) {
 			logger.Debug().Str("id", id).Msg("Service not found")
@@ -89,7 +126,7 @@ func (h *ServiceHandler) Create(c echo.Context) error {
 	}
 
 	// Check if service already exists
-	exists, err := h.Store.ServiceExists(service.ID)
+	exists, err := h.StoreFor(c).ServiceExists(service.ID)
 	if err != nil {
 		logger.Error().Err(err).Str("id", service.ID).Msg("Failed to check if service exists")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -105,8 +142,13 @@ func (h *ServiceHandler) Create(c echo.Context) error {
 	}
 
 	// Create the service
-	if err := h.Store.CreateService(&service); err != nil {
+	if err := h.StoreFor(c).CreateService(&service); err != nil {
 		logger.Error().Err(err).Str("id", service.ID).Msg("Failed to create service")
+		if store.IsValidationError(err) || store.IsInvalidID(err) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Failed to create service",
 		})
@@ -157,7 +199,7 @@ func (h *ServiceHandler) Update(c echo.Context) error {
 	}
 
 	// Check if service exists
-	exists, err := h.Store.ServiceExists(id)
+	exists, err := h.StoreFor(c).ServiceExists(id)
 	if err != nil {
 		logger.Error().Err(err).Str("id", id).Msg("Failed to check if service exists")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -173,8 +215,13 @@ func (h *ServiceHandler) Update(c echo.Context) error {
 	}
 
 	// Update the service
-	if err := h.Store.UpdateService(id, &service); err != nil {
+	if err := h.StoreFor(c).UpdateService(id, &service); err != nil {
 		logger.Error().Err(err).Str("id", id).Msg("Failed to update service")
+		if store.IsValidationError(err) || store.IsInvalidID(err) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "Failed to update service",
 		})
@@ -193,60 +240,113 @@ func (h *ServiceHandler) Update(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
-// Delete handles the DELETE /services/:id endpoint to delete a service
-func (h *ServiceHandler) Delete(c echo.Context) error {
+// Patch handles the PATCH /services/:id endpoint to partially update a service.
+// The body is a JSON Merge Patch or a JSON Patch applied to the current service.
+func (h *ServiceHandler) Patch(c echo.Context) error {
 	id := c.Param("id")
-	logger.Debug().Str("id", id).Msg("Deleting service")
+	logger.Debug().Str("id", id).Msg("Patching service")
 
-	// Check if service exists
-	exists, err := h.Store.ServiceExists(id)
+	current, err := h.StoreFor(c).GetService(id)
 	if err != nil {
-		logger.Error().Err(err).Str("id", id).Msg("Failed to check if service exists")
+		if store.IsNotFound(err) {
+			logger.Warn().Str("id", id).Msg("Service not found")
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Service not found",
+			})
+		}
+		logger.Error().Err(err).Str("id", id).Msg("Failed to get service")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to check if service exists",
+			"error": "Failed to get service",
 		})
 	}
 
-	if !exists {
-		logger.Warn().Str("id", id).Msg("Service not found")
-		return c.JSON(http.StatusNotFound, map[string]string{
-			"error": "Service not found",
-		})
+	patched, patchErr := applyPatch(c, current)
+	if patchErr != nil {
+		return patchErr.respond(c)
 	}
 
-	// Check if service is in use
-	inUse, usedBy, err := h.Store.ServiceInUse(id)
-	if err != nil {
-		logger.Error().Err(err).Str("id", id).Msg("Failed to check if service is in use")
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to check if service is in use",
+	var service models.Service
+	if err := json.Unmarshal(patched, &service); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid patched service: " + err.Error(),
 		})
 	}
 
-	if inUse {
-		logger.Warn().Str("id", id).Strs("used_by", usedBy).Msg("Service is in use")
-		return c.JSON(http.StatusConflict, map[string]interface{}{
-			"error":   "Service is in use by other resources and cannot be deleted",
-			"used_by": usedBy,
+	if service.ID != id {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Service ID cannot be changed",
 		})
 	}
 
-	// Delete the service
-	if err := h.Store.DeleteService(id); err != nil {
-		logger.Error().Err(err).Str("id", id).Msg("Failed to delete service")
+	if err := validateServiceConfiguration(&service); err != nil {
+		logger.Warn().Err(err).Msg("Invalid service configuration")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
 
-		if store.IsResourceInUse(err) {
-			// This is a fallback for any resource-in-use errors not caught by the explicit check
-			return c.JSON(http.StatusConflict, map[string]string{
-				"error": "Service is in use by other resources and cannot be deleted",
+	if err := h.StoreFor(c).UpdateService(id, &service); err != nil {
+		if store.IsNotFound(err) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Service not found",
+			})
+		}
+		logger.Error().Err(err).Str("id", id).Msg("Failed to patch service")
+		if store.IsValidationError(err) || store.IsInvalidID(err) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
 			})
 		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to patch service",
+		})
+	}
+
+	logger.Info().Str("id", id).Msg("Service patched")
+
+	return c.JSON(http.StatusOK, models.ResourceResponse{
+		ID:      id,
+		Updated: true,
+	})
+}
+
+// Delete handles the DELETE /services/:id endpoint to delete a service
+func (h *ServiceHandler) Delete(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Deleting service")
 
+	options, err := parseDeleteOptions(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if options.Cascade || options.DryRun {
+		return deleteWithOptions(c, "Service", id, options, h.StoreFor(c).DeleteServiceWithOptions)
+	}
+
+	// Check if service exists
+	exists, err := h.StoreFor(c).ServiceExists(id)
+	if err != nil {
+		logger.Error().Err(err).Str("id", id).Msg("Failed to check if service exists")
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "Failed to delete service",
+			"error": "Failed to check if service exists",
 		})
 	}
 
+	if !exists {
+		logger.Warn().Str("id", id).Msg("Service not found")
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Service not found",
+		})
+	}
+
+	// Delete the service
+	if err := h.StoreFor(c).DeleteService(id); err != nil {
+		logger.Warn().Err(err).Str("id", id).Msg("Failed to delete service")
+		return deleteError(c, "Service", id, err)
+	}
+
 	logger.Info().Str("id", id).Msg("Service deleted")
 
 	// Return success response
@@ -260,6 +360,33 @@ func (h *ServiceHandler) Delete(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+// Rename handles the POST /services/:id/rename endpoint to change the ID of a service
+// and rewrite the references to it
+func (h *ServiceHandler) Rename(c echo.Context) error {
+	return rename(c, "Service", c.Param("id"), h.StoreFor(c).RenameService)
+}
+
+// Service types as named in Traefik's dynamic configuration
+const (
+	serviceTypeLoadBalancer = "loadBalancer"
+	serviceTypeWeighted     = "weighted"
+	serviceTypeMirroring    = "mirroring"
+	serviceTypeFailover     = "failover"
+)
+
+// serviceKind returns the Traefik type of a service. Services with a URL are load balancers.
+func serviceKind(service models.Service) string {
+	switch {
+	case service.Weighted != nil:
+		return serviceTypeWeighted
+	case service.Mirroring != nil:
+		return serviceTypeMirroring
+	case service.Failover != nil:
+		return serviceTypeFailover
+	}
+	return serviceTypeLoadBalancer
+}
+
 // validateServiceConfiguration checks if the service has a valid configuration
 func validateServiceConfiguration(service *models.Service) error {
 	// Simple URL service
@@ -0,0 +1,306 @@
+// internal/api/handlers/template.go
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+	"github.com/sistemica/traefik-manager/internal/templates"
+)
+
+// TemplateHandler handles template management and instantiation requests
+type TemplateHandler struct {
+	BaseHandler
+	Templates    *templates.Store
+	Environments *store.Environments
+}
+
+// NewTemplateHandler creates a new TemplateHandler instantiating templates into the given store
+// or one of the environments
+func NewTemplateHandler(s store.Store, templateStore *templates.Store, environments *store.Environments) *TemplateHandler {
+	return &TemplateHandler{
+		BaseHandler:  BaseHandler{Store: s},
+		Templates:    templateStore,
+		Environments: environments,
+	}
+}
+
+// List handles the GET /templates endpoint to list all templates
+func (h *TemplateHandler) List(c echo.Context) error {
+	logger.Debug().Msg("Listing templates")
+
+	return c.JSON(http.StatusOK, h.Templates.List())
+}
+
+// Get handles the GET /templates/:id endpoint to get a specific template
+func (h *TemplateHandler) Get(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Getting template")
+
+	template, err := h.Templates.Get(id)
+	if err != nil {
+		return h.storeError(c, err, id, "get")
+	}
+
+	return c.JSON(http.StatusOK, template)
+}
+
+// Create handles the POST /templates endpoint to create a new template
+func (h *TemplateHandler) Create(c echo.Context) error {
+	logger.Debug().Msg("Creating template")
+
+	var template models.Template
+	if err := c.Bind(&template); err != nil {
+		logger.Warn().Err(err).Msg("Invalid template data")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid template data",
+		})
+	}
+
+	if template.ID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Template ID is required",
+		})
+	}
+	if err := templates.Validate(template); err != nil {
+		logger.Warn().Err(err).Str("id", template.ID).Msg("Invalid template")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.Templates.Create(&template); err != nil {
+		return h.storeError(c, err, template.ID, "create")
+	}
+
+	logger.Info().Str("id", template.ID).Msg("Template created")
+
+	return c.JSON(http.StatusCreated, template)
+}
+
+// Update handles the PUT /templates/:id endpoint to replace a template
+func (h *TemplateHandler) Update(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Updating template")
+
+	var template models.Template
+	if err := c.Bind(&template); err != nil {
+		logger.Warn().Err(err).Msg("Invalid template data")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid template data",
+		})
+	}
+
+	if err := templates.Validate(template); err != nil {
+		logger.Warn().Err(err).Str("id", id).Msg("Invalid template")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.Templates.Update(id, &template); err != nil {
+		return h.storeError(c, err, id, "update")
+	}
+
+	logger.Info().Str("id", id).Msg("Template updated")
+
+	return c.JSON(http.StatusOK, template)
+}
+
+// Delete handles the DELETE /templates/:id endpoint to delete a template.
+// Apps instantiated from the template are kept.
+func (h *TemplateHandler) Delete(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Deleting template")
+
+	if err := h.Templates.Delete(id); err != nil {
+		return h.storeError(c, err, id, "delete")
+	}
+
+	logger.Info().Str("id", id).Msg("Template deleted")
+
+	return c.JSON(http.StatusOK, models.ResourceResponse{
+		ID:      id,
+		Deleted: true,
+	})
+}
+
+// Instantiate handles the POST /templates/:id/instantiate endpoint. It renders the template
+// with the given parameters and creates the result as an app, whose name prefixes the IDs
+// of its routers, services and middlewares.
+func (h *TemplateHandler) Instantiate(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Instantiating template")
+
+	var request models.TemplateInstantiation
+	if err := c.Bind(&request); err != nil {
+		logger.Warn().Err(err).Msg("Invalid instantiation data")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid instantiation data",
+		})
+	}
+
+	template, err := h.Templates.Get(id)
+	if err != nil {
+		return h.storeError(c, err, id, "get")
+	}
+
+	target := h.BaseHandler
+	if request.Environment != "" && request.Environment != store.DefaultEnvironment {
+		if h.Environments == nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Unknown environment: " + request.Environment,
+			})
+		}
+		envStore, err := h.Environments.Get(request.Environment)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "Unknown environment: " + request.Environment,
+			})
+		}
+		target = BaseHandler{Store: envStore}
+	}
+
+	if request.Name == "" {
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			logger.Error().Err(err).Msg("Failed to generate app name")
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "Failed to generate app name",
+			})
+		}
+		request.Name = template.ID + "-" + hex.EncodeToString(suffix)
+	}
+
+	rendered, err := templates.Render(*template, request.Parameters)
+	if err != nil {
+		logger.Warn().Err(err).Str("id", id).Msg("Invalid template parameters")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	app, err := templateApp(request.Name, rendered)
+	if err != nil {
+		logger.Warn().Err(err).Str("id", id).Msg("Rendered template is invalid")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	s := target.StoreFor(c)
+	if err := s.CreateApp(&app); err != nil {
+		switch {
+		case store.IsAlreadyExists(err):
+			logger.Warn().Err(err).Str("id", id).Str("app", app.ID).Msg("Failed to instantiate template")
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		case store.IsValidationError(err) || store.IsInvalidID(err) || store.IsDependencyError(err):
+			logger.Warn().Err(err).Str("id", id).Str("app", app.ID).Msg("Failed to instantiate template")
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		logger.Error().Err(err).Str("id", id).Str("app", app.ID).Msg("Failed to instantiate template")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to instantiate template",
+		})
+	}
+
+	logger.Info().Str("id", id).Str("app", app.ID).Msg("Template instantiated")
+
+	created, err := s.GetApp(app.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("app", app.ID).Msg("Failed to get instantiated app")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to get instantiated app",
+		})
+	}
+	return c.JSON(http.StatusCreated, created)
+}
+
+// storeError writes the response for a failed template store operation
+func (h *TemplateHandler) storeError(c echo.Context, err error, id, operation string) error {
+	switch {
+	case store.IsNotFound(err):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Template not found",
+		})
+	case store.IsAlreadyExists(err):
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": "Template already exists",
+		})
+	}
+
+	logger.Error().Err(err).Str("id", id).Msgf("Failed to %s template", operation)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "Failed to " + operation + " template",
+	})
+}
+
+// templateApp converts a rendered template into an app named name. The IDs of its components
+// and the references between them are prefixed with the name, references to other resources are kept.
+func templateApp(name string, rendered *models.Template) (models.App, error) {
+	request := appRequest{
+		ID:          name,
+		Description: "Instantiated from template " + rendered.ID,
+		Routers:     rendered.Routers,
+	}
+
+	// Services and middlewares are decoded like in a create request
+	data, err := json.Marshal(map[string]interface{}{
+		"services":    rendered.Services,
+		"middlewares": rendered.Middlewares,
+	})
+	if err != nil {
+		return models.App{}, err
+	}
+	if err := json.Unmarshal(data, &request); err != nil {
+		return models.App{}, err
+	}
+
+	app, err := parseApp(request)
+	if err != nil {
+		return app, err
+	}
+
+	services := make(map[string]bool, len(app.Services))
+	for _, service := range app.Services {
+		services[service.ID] = true
+	}
+	middlewares := make(map[string]bool, len(app.Middlewares))
+	for _, middleware := range app.Middlewares {
+		middlewares[middleware.ID] = true
+	}
+	prefix := func(components map[string]bool) func(id string) string {
+		return func(id string) string {
+			if components[id] {
+				return name + "-" + id
+			}
+			return id
+		}
+	}
+	serviceID, middlewareID := prefix(services), prefix(middlewares)
+
+	for i := range app.Routers {
+		app.Routers[i].ID = name + "-" + app.Routers[i].ID
+		app.Routers[i].RewriteRefs(serviceID, middlewareID)
+	}
+	for i := range app.Services {
+		app.Services[i].ID = serviceID(app.Services[i].ID)
+		app.Services[i].RewriteServiceRefs(serviceID)
+	}
+	for i := range app.Middlewares {
+		app.Middlewares[i].ID = middlewareID(app.Middlewares[i].ID)
+		app.Middlewares[i].RewriteRefs(middlewareID, serviceID)
+	}
+
+	return app, nil
+}
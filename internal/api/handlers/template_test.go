@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+	"github.com/sistemica/traefik-manager/internal/templates"
+)
+
+// TestTemplateHandler tests creating templates and instantiating them as apps
+func TestTemplateHandler(t *testing.T) {
+	e := echo.New()
+
+	fs, err := store.NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	templateStore, err := templates.NewStore(filepath.Join(t.TempDir(), "templates.json"))
+	if err != nil {
+		t.Fatalf("Failed to create template store: %v", err)
+	}
+	handler := NewTemplateHandler(fs, templateStore, nil)
+
+	request := func(handlerFunc echo.HandlerFunc, method, id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+		}
+		if err := handlerFunc(c); err != nil {
+			t.Fatalf("Handler returned error: %v", err)
+		}
+		return rec
+	}
+
+	// The shared service is not part of the template and is referenced as is
+	if err := fs.CreateService(&models.Service{ID: "shared", URL: "http://shared:8080"}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	publicAPI := `{
+		"id": "public-api",
+		"parameters": [
+			{"name": "host", "type": "hostname"},
+			{"name": "backendURL", "type": "url"},
+			{"name": "rps", "type": "integer", "default": 100, "minimum": 1}
+		],
+		"routers": [
+			{"id": "api", "rule": "Host(` + "`${host}`" + `)", "service": "api", "middlewares": ["ratelimit"]},
+			{"id": "status", "rule": "Host(` + "`${host}`" + `) && Path(` + "`/status`" + `)", "service": "shared"}
+		],
+		"services": [{"id": "api", "url": "${backendURL}"}],
+		"middlewares": [{"id": "ratelimit", "type": "rateLimit", "config": {"average": "${rps}"}}]
+	}`
+
+	t.Run("Create", func(t *testing.T) {
+		if rec := request(handler.Create, http.MethodPost, "", publicAPI); rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+		}
+		if rec := request(handler.Create, http.MethodPost, "", publicAPI); rec.Code != http.StatusConflict {
+			t.Fatalf("Expected status %d, got %d", http.StatusConflict, rec.Code)
+		}
+		invalid := strings.Replace(publicAPI, `"type": "url"`, `"type": "uri"`, 1)
+		if rec := request(handler.Create, http.MethodPost, "", invalid); rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Instantiate", func(t *testing.T) {
+		rec := request(handler.Instantiate, http.MethodPost, "public-api",
+			`{"name": "shop", "parameters": {"host": "shop.example.com", "backendURL": "http://shop:8080", "rps": 50}}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+		}
+
+		var app models.App
+		if err := json.Unmarshal(rec.Body.Bytes(), &app); err != nil {
+			t.Fatalf("Failed to parse app: %v", err)
+		}
+		if app.ID != "shop" || len(app.Routers) != 2 || len(app.Services) != 1 || len(app.Middlewares) != 1 {
+			t.Fatalf("Expected the rendered bundle, got %+v", app)
+		}
+
+		router, err := fs.GetRouter("shop-api")
+		if err != nil {
+			t.Fatalf("Expected router with generated ID, got %v", err)
+		}
+		if router.Rule != "Host(`shop.example.com`)" || router.Service.ID != "shop-api" || router.Middlewares[0].ID != "shop-ratelimit" {
+			t.Fatalf("Expected rendered router referencing the generated IDs, got %+v", router)
+		}
+		if status, _ := fs.GetRouter("shop-status"); status == nil || status.Service.ID != "shared" {
+			t.Fatalf("Expected the shared service to be referenced as is, got %+v", status)
+		}
+		middleware, _ := fs.GetMiddleware("shop-ratelimit")
+		if middleware == nil || !strings.Contains(fmt.Sprint(middleware.Config), "average:50") {
+			t.Fatalf("Expected rendered middleware, got %+v", middleware)
+		}
+	})
+
+	t.Run("Generated Name", func(t *testing.T) {
+		rec := request(handler.Instantiate, http.MethodPost, "public-api",
+			`{"parameters": {"host": "blog.example.com", "backendURL": "http://blog:8080"}}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+		}
+		var app models.App
+		json.Unmarshal(rec.Body.Bytes(), &app)
+		if !strings.HasPrefix(app.ID, "public-api-") || app.Routers[0].ID != app.ID+"-api" {
+			t.Fatalf("Expected generated app name, got %+v", app)
+		}
+	})
+
+	t.Run("Invalid Instantiations", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			id       string
+			body     string
+			expected int
+		}{
+			{"Unknown Template", "missing", `{"parameters": {}}`, http.StatusNotFound},
+			{"Missing Parameter", "public-api", `{"parameters": {"host": "x.example.com"}}`, http.StatusBadRequest},
+			{"Invalid Parameter", "public-api", `{"parameters": {"host": "x.example.com", "backendURL": "http://x", "rps": 0}}`, http.StatusBadRequest},
+			{"Unknown Environment", "public-api", `{"environment": "staging", "parameters": {"host": "x.example.com", "backendURL": "http://x"}}`, http.StatusBadRequest},
+			{"Existing App", "public-api", `{"name": "shop", "parameters": {"host": "x.example.com", "backendURL": "http://x"}}`, http.StatusConflict},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if rec := request(handler.Instantiate, http.MethodPost, tt.id, tt.body); rec.Code != tt.expected {
+					t.Fatalf("Expected status %d, got %d: %s", tt.expected, rec.Code, rec.Body.String())
+				}
+			})
+		}
+	})
+
+	t.Run("Store Errors", func(t *testing.T) {
+		parameters := `{"parameters": {"host": "x.example.com", "backendURL": "http://x"}}`
+
+		// The mock store fails to create apps, without the error being reported to the caller
+		failing := NewTemplateHandler(NewMockStore(), templateStore, nil)
+		rec := request(failing.Instantiate, http.MethodPost, "public-api", parameters)
+		if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), store.ErrInternalError.Error()) {
+			t.Fatalf("Expected status %d with a generic message, got %d: %s", http.StatusInternalServerError, rec.Code, rec.Body.String())
+		}
+
+		// A reference to a missing resource is the caller's error
+		if _, err := fs.DeleteServiceWithOptions("shared", store.DeleteOptions{Cascade: true}); err != nil {
+			t.Fatalf("Failed to delete service: %v", err)
+		}
+		if rec := request(handler.Instantiate, http.MethodPost, "public-api", parameters); rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusBadRequest, rec.Code, rec.Body.String())
+		}
+	})
+}
@@ -0,0 +1,224 @@
+// internal/api/handlers/webhook.go
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+	"github.com/sistemica/traefik-manager/internal/webhooks"
+)
+
+// webhookRequest is the body of webhook create and update requests
+type webhookRequest struct {
+	models.Webhook
+	// Secret signs the deliveries, generated on creation if empty
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookHandler handles webhook management requests
+type WebhookHandler struct {
+	Dispatcher   *webhooks.Dispatcher
+	Environments *store.Environments
+}
+
+// NewWebhookHandler creates a new WebhookHandler
+func NewWebhookHandler(dispatcher *webhooks.Dispatcher, environments *store.Environments) *WebhookHandler {
+	return &WebhookHandler{
+		Dispatcher:   dispatcher,
+		Environments: environments,
+	}
+}
+
+// List handles the GET /webhooks endpoint to list all webhooks
+func (h *WebhookHandler) List(c echo.Context) error {
+	logger.Debug().Msg("Listing webhooks")
+
+	return c.JSON(http.StatusOK, h.Dispatcher.Webhooks().List())
+}
+
+// Get handles the GET /webhooks/:id endpoint to get a specific webhook
+func (h *WebhookHandler) Get(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Getting webhook")
+
+	webhook, err := h.Dispatcher.Webhooks().Get(id)
+	if err != nil {
+		return h.storeError(c, err, id, "get")
+	}
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+// Create handles the POST /webhooks endpoint to register a new webhook.
+// The signing secret is only returned in this response.
+func (h *WebhookHandler) Create(c echo.Context) error {
+	logger.Debug().Msg("Creating webhook")
+
+	var req webhookRequest
+	if err := c.Bind(&req); err != nil {
+		logger.Warn().Err(err).Msg("Invalid webhook data")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid webhook data",
+		})
+	}
+
+	if msg := h.validate(&req.Webhook); msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": msg,
+		})
+	}
+
+	secret, err := h.Dispatcher.Webhooks().Create(&req.Webhook, req.Secret)
+	if err != nil {
+		logger.Error().Err(err).Str("url", req.URL).Msg("Failed to create webhook")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to create webhook",
+		})
+	}
+
+	logger.Info().Str("id", req.ID).Str("url", req.URL).Msg("Webhook created")
+
+	return c.JSON(http.StatusCreated, models.WebhookCreated{
+		Webhook: req.Webhook,
+		Secret:  secret,
+	})
+}
+
+// Update handles the PUT /webhooks/:id endpoint to change the URL and filters of a webhook.
+// The signing secret is kept.
+func (h *WebhookHandler) Update(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Updating webhook")
+
+	var webhook models.Webhook
+	if err := c.Bind(&webhook); err != nil {
+		logger.Warn().Err(err).Str("id", id).Msg("Invalid webhook data")
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid webhook data",
+		})
+	}
+
+	if msg := h.validate(&webhook); msg != "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": msg,
+		})
+	}
+
+	if err := h.Dispatcher.Webhooks().Update(id, &webhook); err != nil {
+		return h.storeError(c, err, id, "update")
+	}
+
+	logger.Info().Str("id", id).Str("url", webhook.URL).Msg("Webhook updated")
+
+	return c.JSON(http.StatusOK, webhook)
+}
+
+// Delete handles the DELETE /webhooks/:id endpoint to remove a webhook
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	id := c.Param("id")
+	logger.Debug().Str("id", id).Msg("Deleting webhook")
+
+	if err := h.Dispatcher.Webhooks().Delete(id); err != nil {
+		return h.storeError(c, err, id, "delete")
+	}
+	h.Dispatcher.Forget(id)
+
+	logger.Info().Str("id", id).Msg("Webhook deleted")
+
+	return c.JSON(http.StatusOK, models.ResourceResponse{
+		ID:      id,
+		Deleted: true,
+	})
+}
+
+// Deliveries handles the GET /webhooks/:id/deliveries endpoint to list the recent deliveries of a webhook.
+// ?status=dead returns the dead-letter list.
+func (h *WebhookHandler) Deliveries(c echo.Context) error {
+	id := c.Param("id")
+	status := c.QueryParam("status")
+	logger.Debug().Str("id", id).Str("status", status).Msg("Listing webhook deliveries")
+
+	switch status {
+	case "", models.DeliveryPending, models.DeliverySucceeded, models.DeliveryDead:
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "Invalid status, expected one of: pending, succeeded, dead",
+		})
+	}
+
+	if _, err := h.Dispatcher.Webhooks().Get(id); err != nil {
+		return h.storeError(c, err, id, "get")
+	}
+
+	return c.JSON(http.StatusOK, h.Dispatcher.Deliveries(id, status))
+}
+
+// Redeliver handles the POST /webhooks/:id/deliveries/:delivery/redeliver endpoint
+// to retry a delivery from the dead-letter list
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	id := c.Param("id")
+	deliveryID := c.Param("delivery")
+	logger.Debug().Str("id", id).Str("delivery", deliveryID).Msg("Redelivering webhook delivery")
+
+	delivery, err := h.Dispatcher.Redeliver(id, deliveryID)
+	if err != nil {
+		if store.IsNotFound(err) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "Dead delivery not found",
+			})
+		}
+		logger.Error().Err(err).Str("id", id).Str("delivery", deliveryID).Msg("Failed to redeliver webhook delivery")
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "Failed to redeliver webhook delivery",
+		})
+	}
+
+	return c.JSON(http.StatusAccepted, delivery)
+}
+
+// validate checks a webhook and returns an error message if it is invalid
+func (h *WebhookHandler) validate(webhook *models.Webhook) string {
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "Invalid webhook URL, expected http(s)://host/path"
+	}
+
+	for _, pattern := range webhook.Events {
+		if !webhooks.ValidPattern(pattern) {
+			return "Invalid event pattern: " + pattern
+		}
+	}
+
+	if webhook.Environment != "" && webhook.Environment != store.DefaultEnvironment {
+		if h.Environments == nil {
+			return "Unknown environment: " + webhook.Environment
+		}
+		if _, err := h.Environments.Get(webhook.Environment); err != nil {
+			return "Unknown environment: " + webhook.Environment
+		}
+	}
+
+	if strings.Contains(webhook.Namespace, store.NamespaceSeparator) {
+		return "Invalid namespace: " + webhook.Namespace
+	}
+
+	return ""
+}
+
+// storeError maps a webhook store error to a response
+func (h *WebhookHandler) storeError(c echo.Context, err error, id, action string) error {
+	if store.IsNotFound(err) {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "Webhook not found",
+		})
+	}
+	logger.Error().Err(err).Str("id", id).Msgf("Failed to %s webhook", action)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "Failed to " + action + " webhook",
+	})
+}
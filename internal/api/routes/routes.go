@@ -4,16 +4,41 @@ package routes
 import (
 	"github.com/labstack/echo/v4"
 	"github.com/sistemica/traefik-manager/internal/api/handlers"
+	"github.com/sistemica/traefik-manager/internal/apikeys"
+	"github.com/sistemica/traefik-manager/internal/audit"
 	"github.com/sistemica/traefik-manager/internal/config"
+	"github.com/sistemica/traefik-manager/internal/events"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	customMiddleware "github.com/sistemica/traefik-manager/internal/middleware"
+	"github.com/sistemica/traefik-manager/internal/pollers"
+	"github.com/sistemica/traefik-manager/internal/prober"
+	"github.com/sistemica/traefik-manager/internal/reconcile"
 	"github.com/sistemica/traefik-manager/internal/store"
+	"github.com/sistemica/traefik-manager/internal/templates"
+	"github.com/sistemica/traefik-manager/internal/webhooks"
 )
 
+// Dependencies holds the optional components the routes are wired to
+type Dependencies struct {
+	// Environments holds the stores of the named environments, nil if none are configured
+	Environments *store.Environments
+	// APIKeys holds the managed API keys, nil if authentication is disabled
+	APIKeys *apikeys.Store
+	// AuditLog holds the recorded API calls, nil if auditing is disabled
+	AuditLog *audit.Log
+	// Reconciler holds the state reported by Traefik's API, nil if reconciliation is disabled
+	Reconciler *reconcile.Reconciler
+	// Prober holds the backend health checked by the manager, nil if probing is disabled
+	Prober *prober.Prober
+	// Webhooks holds the registered webhooks and their deliveries, nil if webhooks are disabled
+	Webhooks *webhooks.Dispatcher
+	// Templates holds the parameterized app templates, nil if templates are disabled
+	Templates *templates.Store
+}
+
 // RegisterRoutes sets up all API routes
-func RegisterRoutes(e *echo.Echo, s store.Store, basePath string, cfg *config.Config) {
+func RegisterRoutes(e *echo.Echo, s store.Store, basePath string, cfg *config.Config, deps Dependencies) {
 	// Create handler instances
-	middlewareHandler := handlers.NewMiddlewareHandler(s)
-	routerHandler := handlers.NewRouterHandler(s)
-	serviceHandler := handlers.NewServiceHandler(s)
 	healthHandler := handlers.NewHealthHandler(s, "1.0.0")
 
 	// API group with base path
@@ -22,40 +47,222 @@ func RegisterRoutes(e *echo.Echo, s store.Store, basePath string, cfg *config.Co
 	// Health check - always public
 	api.GET("/health", healthHandler.Check)
 
+	// Prometheus metrics - public like the health check
+	if cfg.Metrics.Enabled {
+		metricsHandler := handlers.NewMetricsHandler(s, deps.Environments)
+		e.GET(cfg.Metrics.Path, metricsHandler.Serve)
+	}
+
+	// Traefik instances polling the provider endpoints
+	clients := pollers.NewTracker(cfg.Provider.ClientStaleAfter)
+	providerClientHandler := handlers.NewProviderClientHandler(clients)
+	api.GET("/provider/clients", providerClientHandler.List)
+
 	// Traefik provider endpoint - with custom auth
+	polling := providerPolling(s, cfg, clients)
+	registerProviderRoute(e, s, cfg.Provider.ProviderPath, cfg, deps, polling)
+
+	// Provider target endpoints - a target uses its own auth or falls back to the provider auth
+	if len(cfg.Provider.Targets) > 0 {
+		targetHandler := handlers.NewProviderTargetHandler(s, cfg.Provider.Targets, cfg.Provider.Auth)
+		targetHandler.ProviderPolling = polling
+		if deps.APIKeys != nil {
+			targetHandler.Keys = deps.APIKeys
+		}
+		e.GET(cfg.Provider.ProviderPath+"/:target", targetHandler.GetTargetConfig, providerMiddlewares(cfg)...)
+	}
+
+	// Routers, services, middlewares, apps and their graph of the default environment
+	registerResourceRoutes(api, s)
+	registerEventsRoute(api, s, cfg)
+
+	// Runtime state reported by the reconciled Traefik instances
+	if deps.Reconciler != nil {
+		statusHandler := handlers.NewStatusHandler(s, deps.Reconciler)
+		api.GET("/routers/:id/status", statusHandler.RouterStatus)
+		api.GET("/services/:id/status", statusHandler.ServiceStatus)
+		api.GET("/middlewares/:id/status", statusHandler.MiddlewareStatus)
+		api.GET("/reconcile", statusHandler.Report)
+	}
+
+	// Backend health checked by the manager
+	if deps.Prober != nil {
+		serviceHealthHandler := handlers.NewServiceHealthHandler(s, deps.Prober)
+		api.GET("/services/:id/health", serviceHealthHandler.Get)
+	}
+
+	// Named environments
+	if deps.Environments != nil {
+		providerPaths := map[string]string{
+			store.DefaultEnvironment: cfg.Provider.ProviderPath,
+		}
+
+		for _, env := range cfg.Environments {
+			envStore, err := deps.Environments.Get(env.Name)
+			if err != nil {
+				logger.Error().Err(err).Str("environment", env.Name).Msg("Environment store not registered")
+				continue
+			}
+
+			providerPaths[env.Name] = env.ProviderPath
+			registerProviderRoute(e, envStore, env.ProviderPath, cfg, deps, providerPolling(envStore, cfg, clients))
+			envGroup := api.Group("/environments/" + env.Name)
+			registerResourceRoutes(envGroup, envStore)
+			registerEventsRoute(envGroup, envStore, cfg)
+		}
+
+		environmentHandler := handlers.NewEnvironmentHandler(deps.Environments, providerPaths)
+		api.GET("/environments", environmentHandler.List)
+		api.POST("/environments/:from/promote/:to", environmentHandler.Promote)
+	}
+
+	// API key management - restricted to the admin role by the auth middleware
+	if deps.APIKeys != nil {
+		apiKeyHandler := handlers.NewAPIKeyHandler(deps.APIKeys)
+		apiKeys := api.Group("/apikeys")
+		apiKeys.GET("", apiKeyHandler.List)
+		apiKeys.POST("", apiKeyHandler.Create)
+		apiKeys.GET("/:name", apiKeyHandler.Get)
+		apiKeys.DELETE("/:name", apiKeyHandler.Delete)
+	}
+
+	// Audit log - restricted to the admin role by the auth middleware
+	if deps.AuditLog != nil {
+		auditHandler := handlers.NewAuditHandler(deps.AuditLog)
+		api.GET("/audit", auditHandler.Query)
+	}
+
+	// Webhook management - restricted to the admin role by the auth middleware
+	if deps.Webhooks != nil {
+		webhookHandler := handlers.NewWebhookHandler(deps.Webhooks, deps.Environments)
+		webhooks := api.Group("/webhooks")
+		webhooks.GET("", webhookHandler.List)
+		webhooks.POST("", webhookHandler.Create)
+		webhooks.GET("/:id", webhookHandler.Get)
+		webhooks.PUT("/:id", webhookHandler.Update)
+		webhooks.DELETE("/:id", webhookHandler.Delete)
+		webhooks.GET("/:id/deliveries", webhookHandler.Deliveries)
+		webhooks.POST("/:id/deliveries/:delivery/redeliver", webhookHandler.Redeliver)
+	}
+
+	// Templates instantiated as apps in the default or a named environment
+	if deps.Templates != nil {
+		templateHandler := handlers.NewTemplateHandler(s, deps.Templates, deps.Environments)
+		templates := api.Group("/templates")
+		templates.GET("", templateHandler.List)
+		templates.POST("", templateHandler.Create)
+		templates.GET("/:id", templateHandler.Get)
+		templates.PUT("/:id", templateHandler.Update)
+		templates.DELETE("/:id", templateHandler.Delete)
+		templates.POST("/:id/instantiate", templateHandler.Instantiate)
+	}
+}
+
+// registerProviderRoute registers a Traefik provider endpoint serving the given store
+func registerProviderRoute(e *echo.Echo, s store.Store, path string, cfg *config.Config, deps Dependencies, polling handlers.ProviderPolling) {
 	providerAuth := cfg.Provider.Auth
 	if providerAuth == nil && cfg.Auth.Enabled {
 		// If global auth is enabled but no specific provider auth,
 		// the provider endpoint is public (excluded from auth)
 		providerHandler := handlers.NewProviderHandler(s)
-		e.GET(cfg.Provider.ProviderPath, providerHandler.GetConfig)
+		providerHandler.ProviderPolling = polling
+		e.GET(path, providerHandler.GetConfig, providerMiddlewares(cfg)...)
 	} else {
 		// Either provider-specific auth or no auth at all
 		providerHandlerWithAuth := handlers.NewProviderHandlerWithAuth(s, providerAuth)
-		e.GET(cfg.Provider.ProviderPath, providerHandlerWithAuth.GetConfigWithAuth)
+		providerHandlerWithAuth.ProviderPolling = polling
+		if deps.APIKeys != nil {
+			providerHandlerWithAuth.Keys = deps.APIKeys
+		}
+		e.GET(path, providerHandlerWithAuth.GetConfigWithAuth, providerMiddlewares(cfg)...)
+	}
+}
+
+// providerPolling returns the polling settings of the provider endpoints serving a store.
+// Long polls are only held if the store reports its changes.
+func providerPolling(s store.Store, cfg *config.Config, clients *pollers.Tracker) handlers.ProviderPolling {
+	polling := handlers.ProviderPolling{
+		Clients:        clients,
+		InstanceHeader: cfg.Provider.InstanceHeader,
+		MaxWait:        cfg.Provider.MaxWait,
+	}
+	if notifier, ok := s.(store.ChangeNotifier); ok {
+		polling.Changes = events.NewSignal()
+		notifier.OnChange(polling.Changes.Notify)
+	}
+	return polling
+}
+
+// providerMiddlewares returns the route middlewares of the provider endpoints
+func providerMiddlewares(cfg *config.Config) []echo.MiddlewareFunc {
+	if cfg.Provider.MTLS == nil {
+		return nil
 	}
+	// Traefik must present a verified client certificate
+	return []echo.MiddlewareFunc{customMiddleware.RequireClientCert(cfg.Provider.MTLS.AllowedSubjects)}
+}
+
+// registerEventsRoute registers the change stream of a store if the store reports its changes
+func registerEventsRoute(g *echo.Group, s store.Store, cfg *config.Config) {
+	notifier, ok := s.(store.ChangeNotifier)
+	if !ok {
+		return
+	}
+
+	broker := events.NewBroker(cfg.Events.HistorySize, notifier.Version())
+	notifier.OnChange(broker.Publish)
+
+	eventsHandler := handlers.NewEventsHandler(broker)
+	g.GET("/events", eventsHandler.Stream)
+}
+
+// registerResourceRoutes registers the router, service, middleware and app endpoints for a store
+func registerResourceRoutes(g *echo.Group, s store.Store) {
+	middlewareHandler := handlers.NewMiddlewareHandler(s)
+	routerHandler := handlers.NewRouterHandler(s)
+	serviceHandler := handlers.NewServiceHandler(s)
+	appHandler := handlers.NewAppHandler(s)
+	graphHandler := handlers.NewGraphHandler(s)
 
 	// Middlewares
-	middlewares := api.Group("/middlewares")
+	middlewares := g.Group("/middlewares")
 	middlewares.GET("", middlewareHandler.List)
 	middlewares.POST("", middlewareHandler.Create)
+	middlewares.DELETE("", middlewareHandler.DeleteBySelector)
 	middlewares.GET("/:id", middlewareHandler.Get)
 	middlewares.PUT("/:id", middlewareHandler.Update)
+	middlewares.PATCH("/:id", middlewareHandler.Patch)
 	middlewares.DELETE("/:id", middlewareHandler.Delete)
+	middlewares.POST("/:id/rename", middlewareHandler.Rename)
 
 	// Routers
-	routers := api.Group("/routers")
+	routers := g.Group("/routers")
 	routers.GET("", routerHandler.List)
 	routers.POST("", routerHandler.Create)
+	routers.DELETE("", routerHandler.DeleteBySelector)
 	routers.GET("/:id", routerHandler.Get)
 	routers.PUT("/:id", routerHandler.Update)
+	routers.PATCH("/:id", routerHandler.Patch)
 	routers.DELETE("/:id", routerHandler.Delete)
 
 	// Services
-	services := api.Group("/services")
+	services := g.Group("/services")
 	services.GET("", serviceHandler.List)
 	services.POST("", serviceHandler.Create)
+	services.DELETE("", serviceHandler.DeleteBySelector)
 	services.GET("/:id", serviceHandler.Get)
 	services.PUT("/:id", serviceHandler.Update)
+	services.PATCH("/:id", serviceHandler.Patch)
 	services.DELETE("/:id", serviceHandler.Delete)
+	services.POST("/:id/rename", serviceHandler.Rename)
+
+	// Apps
+	apps := g.Group("/apps")
+	apps.GET("", appHandler.List)
+	apps.POST("", appHandler.Create)
+	apps.GET("/:id", appHandler.Get)
+	apps.DELETE("/:id", appHandler.Delete)
+
+	// Dependency graph
+	g.GET("/graph", graphHandler.Get)
 }
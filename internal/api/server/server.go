@@ -3,17 +3,27 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/sistemica/traefik-manager/internal/api/handlers"
 	"github.com/sistemica/traefik-manager/internal/api/routes"
+	"github.com/sistemica/traefik-manager/internal/apikeys"
+	"github.com/sistemica/traefik-manager/internal/audit"
 	"github.com/sistemica/traefik-manager/internal/config"
 	"github.com/sistemica/traefik-manager/internal/logger"
 	customMiddleware "github.com/sistemica/traefik-manager/internal/middleware"
+	"github.com/sistemica/traefik-manager/internal/prober"
+	"github.com/sistemica/traefik-manager/internal/reconcile"
 	"github.com/sistemica/traefik-manager/internal/store"
+	"github.com/sistemica/traefik-manager/internal/templates"
+	"github.com/sistemica/traefik-manager/internal/tlsconfig"
+	"github.com/sistemica/traefik-manager/internal/webhooks"
 )
 
 // Server represents the HTTP server
@@ -22,6 +32,16 @@ type Server struct {
 	config     *config.Config
 	store      store.Store
 	httpServer *http.Server
+
+	environments *store.Environments
+	apiKeys      *apikeys.Store
+	bearer       customMiddleware.BearerAuthenticator
+	auditLog     *audit.Log
+	tlsReloader  *tlsconfig.Reloader
+	reconciler   *reconcile.Reconciler
+	prober       *prober.Prober
+	webhooks     *webhooks.Dispatcher
+	templates    *templates.Store
 }
 
 // New creates a new server instance
@@ -36,6 +56,51 @@ func New(cfg *config.Config, store store.Store) *Server {
 	}
 }
 
+// SetEnvironments sets the named environments served in addition to the default store
+func (s *Server) SetEnvironments(environments *store.Environments) {
+	s.environments = environments
+}
+
+// SetAPIKeys sets the store of managed API keys accepted in addition to the configured keys
+func (s *Server) SetAPIKeys(keys *apikeys.Store) {
+	s.apiKeys = keys
+}
+
+// SetBearerAuthenticator sets the validator of bearer tokens accepted in addition to API keys
+func (s *Server) SetBearerAuthenticator(bearer customMiddleware.BearerAuthenticator) {
+	s.bearer = bearer
+}
+
+// SetAuditLog sets the log mutating API calls are recorded to
+func (s *Server) SetAuditLog(log *audit.Log) {
+	s.auditLog = log
+}
+
+// SetTLSReloader sets the certificates the server is served with when TLS is configured
+func (s *Server) SetTLSReloader(reloader *tlsconfig.Reloader) {
+	s.tlsReloader = reloader
+}
+
+// SetReconciler sets the reconciler whose Traefik state is served on the status endpoints
+func (s *Server) SetReconciler(reconciler *reconcile.Reconciler) {
+	s.reconciler = reconciler
+}
+
+// SetProber sets the prober whose backend health is served on the service health endpoint
+func (s *Server) SetProber(prober *prober.Prober) {
+	s.prober = prober
+}
+
+// SetWebhooks sets the dispatcher whose webhooks are managed on the webhook endpoints
+func (s *Server) SetWebhooks(dispatcher *webhooks.Dispatcher) {
+	s.webhooks = dispatcher
+}
+
+// SetTemplates sets the store of templates managed and instantiated on the template endpoints
+func (s *Server) SetTemplates(templateStore *templates.Store) {
+	s.templates = templateStore
+}
+
 // Setup configures the server
 func (s *Server) Setup() {
 	// Setup middleware
@@ -43,6 +108,10 @@ func (s *Server) Setup() {
 	s.echo.Use(customMiddleware.Logger())
 	s.echo.Use(middleware.Recover())
 	s.echo.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
+		// Event streams stay open until the client disconnects, long polls until the configuration changes
+		Skipper: func(c echo.Context) bool {
+			return strings.HasSuffix(c.Path(), "/events") || (s.isProviderPath(c.Path()) && c.QueryParam("wait") != "")
+		},
 		Timeout: 30 * time.Second,
 	}))
 
@@ -52,29 +121,62 @@ func (s *Server) Setup() {
 		AllowMethods:     s.config.Cors.AllowedMethods,
 		AllowHeaders:     s.config.Cors.AllowedHeaders,
 		AllowCredentials: s.config.Cors.AllowCredentials,
-		MaxAge:           int(s.config.Cors.MaxAge),
+		// Let browser clients read the pagination headers of list responses
+		ExposeHeaders: []string{handlers.HeaderTotalCount, handlers.HeaderNextCursor},
+		MaxAge:        int(s.config.Cors.MaxAge),
 	}))
 
+	// Audit mutating calls, including the ones rejected by authentication
+	if s.auditLog != nil {
+		s.echo.Use(customMiddleware.Audit(s.auditLog))
+	}
+
 	// Determine excluded paths based on config
 	excludedPaths := []string{"/health"}
+	if s.config.Metrics.Enabled {
+		excludedPaths = append(excludedPaths, s.config.Metrics.Path)
+	}
 
-	// If global auth is enabled, exclude provider path
+	// If global auth is enabled, exclude provider paths
 	if s.config.Auth.Enabled {
 		excludedPaths = append(excludedPaths, s.config.Provider.ProviderPath)
+		for _, env := range s.config.Environments {
+			excludedPaths = append(excludedPaths, env.ProviderPath)
+		}
 	}
 
 	// API authentication middleware (for all other endpoints)
 	if s.config.Auth.Enabled {
-		s.echo.Use(customMiddleware.Auth(customMiddleware.AuthOptions{
-			Enabled:      s.config.Auth.Enabled,
-			HeaderName:   s.config.Auth.HeaderName,
-			Key:          s.config.Auth.Key,
-			ExcludePaths: excludedPaths,
-		}))
+		authOptions := customMiddleware.AuthOptions{
+			Enabled:       s.config.Auth.Enabled,
+			HeaderName:    s.config.Auth.HeaderName,
+			Key:           s.config.Auth.Key,
+			KeyHashes:     s.config.Auth.KeyHashes,
+			ExcludePaths:  excludedPaths,
+			NamespaceKeys: s.config.Auth.NamespaceKeys,
+			AdminPaths:    []string{s.config.Server.BasePath + "/apikeys", s.config.Server.BasePath + "/audit", s.config.Server.BasePath + "/webhooks"},
+		}
+		if tlsConfig := s.config.Server.TLS; tlsConfig != nil {
+			authOptions.ClientCertRoles = tlsConfig.ClientRoles
+			authOptions.ClientCertDefaultRole = tlsConfig.ClientDefaultRole
+		}
+		if s.apiKeys != nil {
+			authOptions.Keys = s.apiKeys
+		}
+		authOptions.Bearer = s.bearer
+		s.echo.Use(customMiddleware.Auth(authOptions))
 	}
 
 	// Register routes with all configuration context
-	routes.RegisterRoutes(s.echo, s.store, s.config.Server.BasePath, s.config)
+	routes.RegisterRoutes(s.echo, s.store, s.config.Server.BasePath, s.config, routes.Dependencies{
+		Environments: s.environments,
+		APIKeys:      s.apiKeys,
+		AuditLog:     s.auditLog,
+		Reconciler:   s.reconciler,
+		Prober:       s.prober,
+		Webhooks:     s.webhooks,
+		Templates:    s.templates,
+	})
 
 	// Configure HTTP server
 	s.httpServer = &http.Server{
@@ -82,11 +184,40 @@ func (s *Server) Setup() {
 		ReadTimeout:  s.config.Server.ReadTimeout,
 		WriteTimeout: s.config.Server.WriteTimeout,
 	}
+
+	// Serve over TLS, verifying client certificates if a client CA is configured
+	if tlsConfig := s.config.Server.TLS; tlsConfig != nil && s.tlsReloader != nil {
+		// The version is validated when the configuration is loaded
+		minVersion, _ := tlsconfig.ParseVersion(tlsConfig.MinVersion)
+
+		clientAuth := tls.NoClientCert
+		if tlsConfig.ClientCAFile != "" {
+			clientAuth = tls.VerifyClientCertIfGiven
+			if tlsConfig.RequireClientCert {
+				clientAuth = tls.RequireAndVerifyClientCert
+			}
+		}
+
+		s.httpServer.TLSConfig = s.tlsReloader.TLSConfig(minVersion, clientAuth)
+	}
+}
+
+// isProviderPath returns true if the route is one of the provider endpoints
+func (s *Server) isProviderPath(route string) bool {
+	if route == s.config.Provider.ProviderPath || route == s.config.Provider.ProviderPath+"/:target" {
+		return true
+	}
+	for _, env := range s.config.Environments {
+		if route == env.ProviderPath {
+			return true
+		}
+	}
+	return false
 }
 
 // Start starts the server
 func (s *Server) Start() error {
-	logger.Info().Str("address", s.httpServer.Addr).Msg("Starting server")
+	logger.Info().Str("address", s.httpServer.Addr).Bool("tls", s.httpServer.TLSConfig != nil).Msg("Starting server")
 	return s.echo.StartServer(s.httpServer)
 }
 
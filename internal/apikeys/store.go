@@ -0,0 +1,208 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/middleware"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// keyPrefix is prepended to generated keys so they are easy to recognize
+const keyPrefix = "tm_"
+
+// storedKey is the persisted form of an API key, holding the hash instead of the key
+type storedKey struct {
+	models.APIKey
+	Hash string `json:"hash"`
+}
+
+// Store manages named API keys persisted in a JSON file.
+// Only SHA-256 hashes of the keys are stored.
+type Store struct {
+	mu       sync.RWMutex
+	filePath string
+	keys     map[string]storedKey // by name
+	byHash   map[string]string    // hash to name
+}
+
+// NewStore creates a new Store, loading existing keys from filePath
+func NewStore(filePath string) (*Store, error) {
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	s := &Store{
+		filePath: filePath,
+		keys:     make(map[string]storedKey),
+		byHash:   make(map[string]string),
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read API keys file: %w", err)
+	}
+
+	var keys []storedKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse API keys file: %w", err)
+	}
+	for _, key := range keys {
+		s.keys[key.Name] = key
+		s.byHash[key.Hash] = key.Name
+	}
+
+	return s, nil
+}
+
+// List returns all API keys sorted by name
+func (s *Store) List() []models.APIKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]models.APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key.APIKey)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+	return keys
+}
+
+// Get returns the API key with the given name
+func (s *Store) Get(name string) (*models.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[name]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	result := key.APIKey
+	return &result, nil
+}
+
+// Create generates a new key for the given API key description.
+// The plaintext key is returned and cannot be retrieved again.
+func (s *Store) Create(key *models.APIKey) (string, error) {
+	secret, err := generateKey()
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.keys[key.Name]; exists {
+		return "", store.ErrAlreadyExists
+	}
+
+	key.Prefix = secret[:len(keyPrefix)+6]
+	key.CreatedAt = time.Now().UTC()
+
+	stored := storedKey{APIKey: *key, Hash: hashKey(secret)}
+	s.keys[key.Name] = stored
+	s.byHash[stored.Hash] = key.Name
+
+	if err := s.save(); err != nil {
+		delete(s.keys, key.Name)
+		delete(s.byHash, stored.Hash)
+		return "", err
+	}
+
+	return secret, nil
+}
+
+// Delete removes the API key with the given name
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[name]
+	if !ok {
+		return store.ErrNotFound
+	}
+
+	delete(s.keys, name)
+	delete(s.byHash, key.Hash)
+
+	if err := s.save(); err != nil {
+		s.keys[name] = key
+		s.byHash[key.Hash] = name
+		return err
+	}
+	return nil
+}
+
+// AuthenticateKey resolves a plaintext key to the identity of its API key
+func (s *Store) AuthenticateKey(secret string) (*middleware.Identity, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	name, ok := s.byHash[hashKey(secret)]
+	if !ok {
+		return nil, false
+	}
+
+	key := s.keys[name]
+	return &middleware.Identity{
+		Name:      key.Name,
+		Namespace: key.Namespace,
+		Role:      key.Role,
+		Scopes:    key.Scopes,
+	}, true
+}
+
+// save writes all keys to the file, must be called with the lock held
+func (s *Store) save() error {
+	keys := make([]storedKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal API keys: %w", err)
+	}
+
+	tempFile := s.filePath + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write API keys file: %w", err)
+	}
+	if err := os.Rename(tempFile, s.filePath); err != nil {
+		return fmt.Errorf("failed to rename API keys file: %w", err)
+	}
+	return nil
+}
+
+// generateKey creates a new random API key
+func generateKey() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	return keyPrefix + hex.EncodeToString(buf), nil
+}
+
+// hashKey returns the hex encoded SHA-256 hash of a key
+func hashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,93 @@
+package apikeys
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+func TestStore(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "apikeys.json")
+
+	s, err := NewStore(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	var secret string
+
+	t.Run("Create Key", func(t *testing.T) {
+		key := &models.APIKey{
+			Name:   "ci",
+			Role:   models.RoleEditor,
+			Scopes: []models.APIKeyScope{{ResourceType: "routers", IDPattern: "ci-*"}},
+		}
+		secret, err = s.Create(key)
+		if err != nil {
+			t.Fatalf("Failed to create key: %v", err)
+		}
+		if !strings.HasPrefix(secret, keyPrefix) {
+			t.Fatalf("Expected key with prefix %s, got %s", keyPrefix, secret)
+		}
+		if !strings.HasPrefix(secret, key.Prefix) {
+			t.Fatalf("Expected prefix %s to match key", key.Prefix)
+		}
+
+		if _, err := s.Create(&models.APIKey{Name: "ci", Role: models.RoleReader}); err != store.ErrAlreadyExists {
+			t.Fatalf("Expected ErrAlreadyExists, got %v", err)
+		}
+	})
+
+	t.Run("Key Stored Hashed", func(t *testing.T) {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("Failed to read file: %v", err)
+		}
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("Expected plaintext key not to be stored")
+		}
+	})
+
+	t.Run("Authenticate Key", func(t *testing.T) {
+		identity, ok := s.AuthenticateKey(secret)
+		if !ok {
+			t.Fatalf("Expected key to authenticate")
+		}
+		if identity.Name != "ci" || identity.Role != models.RoleEditor || len(identity.Scopes) != 1 {
+			t.Fatalf("Unexpected identity: %+v", identity)
+		}
+
+		if _, ok := s.AuthenticateKey("tm_invalid"); ok {
+			t.Fatalf("Expected invalid key to be rejected")
+		}
+	})
+
+	t.Run("Reload Keys", func(t *testing.T) {
+		reloaded, err := NewStore(filePath)
+		if err != nil {
+			t.Fatalf("Failed to reload store: %v", err)
+		}
+		if _, ok := reloaded.AuthenticateKey(secret); !ok {
+			t.Fatalf("Expected key to authenticate after reload")
+		}
+		if len(reloaded.List()) != 1 {
+			t.Fatalf("Expected 1 key, got %d", len(reloaded.List()))
+		}
+	})
+
+	t.Run("Delete Key", func(t *testing.T) {
+		if err := s.Delete("ci"); err != nil {
+			t.Fatalf("Failed to delete key: %v", err)
+		}
+		if _, ok := s.AuthenticateKey(secret); ok {
+			t.Fatalf("Expected deleted key to be rejected")
+		}
+		if err := s.Delete("ci"); err != store.ErrNotFound {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,229 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// backupTimeFormat is appended to the file name of rotated audit files
+const backupTimeFormat = "20060102T150405.000000000"
+
+// Options configures rotation and retention of the audit log
+type Options struct {
+	// MaxSize is the size in bytes after which the file is rotated, 0 disables rotation
+	MaxSize int64
+	// MaxBackups is the number of rotated files to keep, 0 keeps all
+	MaxBackups int
+	// MaxAge is the duration rotated files are kept, 0 keeps them forever
+	MaxAge time.Duration
+}
+
+// Log is an append-only JSON-lines audit log with size based rotation
+type Log struct {
+	mu       sync.Mutex
+	filePath string
+	opts     Options
+	file     *os.File
+	size     int64
+}
+
+// NewLog opens the audit log at filePath, creating it if needed
+func NewLog(filePath string, opts Options) (*Log, error) {
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	l := &Log{
+		filePath: filePath,
+		opts:     opts,
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+
+	l.cleanup()
+	return l, nil
+}
+
+// Record appends an entry to the log
+func (l *Log) Record(entry models.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return fmt.Errorf("audit log is closed")
+	}
+
+	if l.opts.MaxSize > 0 && l.size > 0 && l.size+int64(len(data)) > l.opts.MaxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(data)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// Query returns the entries matching the query, newest first
+func (l *Log) Query(query models.AuditQuery) ([]models.AuditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	files := append(l.backups(), l.filePath)
+
+	entries := []models.AuditEntry{}
+	for _, path := range files {
+		if err := readEntries(path, query, &entries); err != nil {
+			return nil, err
+		}
+	}
+
+	// Files and lines are in chronological order
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	if query.Limit > 0 && len(entries) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+	return entries, nil
+}
+
+// Close closes the audit log
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// open opens the current file for appending, must be called with the lock held or during construction
+func (l *Log) open() error {
+	file, err := os.OpenFile(l.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// rotate moves the current file aside and starts a new one, must be called with the lock held
+func (l *Log) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	l.file = nil
+
+	backup := l.filePath + "." + time.Now().UTC().Format(backupTimeFormat)
+	if err := os.Rename(l.filePath, backup); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+
+	if err := l.open(); err != nil {
+		return err
+	}
+
+	l.cleanup()
+	return nil
+}
+
+// cleanup removes rotated files exceeding the retention settings
+func (l *Log) cleanup() {
+	backups := l.backups()
+
+	for i, path := range backups {
+		remove := l.opts.MaxBackups > 0 && i < len(backups)-l.opts.MaxBackups
+		if !remove && l.opts.MaxAge > 0 {
+			if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > l.opts.MaxAge {
+				remove = true
+			}
+		}
+
+		if remove {
+			if err := os.Remove(path); err != nil {
+				logger.Warn().Err(err).Str("path", path).Msg("Failed to remove rotated audit log")
+			}
+		}
+	}
+}
+
+// backups returns the rotated files, oldest first
+func (l *Log) backups() []string {
+	matches, err := filepath.Glob(l.filePath + ".*")
+	if err != nil {
+		return nil
+	}
+
+	backups := make([]string, 0, len(matches))
+	for _, path := range matches {
+		suffix := path[len(l.filePath)+1:]
+		if _, err := time.Parse(backupTimeFormat, suffix); err == nil {
+			backups = append(backups, path)
+		}
+	}
+	sort.Strings(backups)
+	return backups
+}
+
+// readEntries appends the matching entries of a file
+func readEntries(path string, query models.AuditQuery, entries *[]models.AuditEntry) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry models.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// Skip partially written lines
+			continue
+		}
+		if query.Matches(entry) {
+			*entries = append(*entries, entry)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+func TestLog(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	// Small size so every few entries trigger a rotation
+	log, err := NewLog(filePath, Options{MaxSize: 600, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("Failed to create audit log: %v", err)
+	}
+	defer log.Close()
+
+	start := time.Now().UTC()
+	for i := 0; i < 12; i++ {
+		entry := models.AuditEntry{
+			Time:         start.Add(time.Duration(i) * time.Second),
+			Actor:        "alice",
+			SourceIP:     "127.0.0.1",
+			Method:       "PUT",
+			Path:         "/api/v1/routers/web",
+			ResourceType: "routers",
+			ResourceID:   "web",
+			Status:       200,
+			Outcome:      models.AuditSuccess,
+		}
+		if i%3 == 0 {
+			entry.Actor = "bob"
+			entry.Status = 403
+			entry.Outcome = models.AuditDenied
+		}
+		if err := log.Record(entry); err != nil {
+			t.Fatalf("Failed to record entry: %v", err)
+		}
+	}
+
+	t.Run("Rotation And Retention", func(t *testing.T) {
+		if backups := log.backups(); len(backups) != 2 {
+			t.Fatalf("Expected 2 rotated files, got %d", len(backups))
+		}
+	})
+
+	t.Run("Query Newest First", func(t *testing.T) {
+		entries, err := log.Query(models.AuditQuery{})
+		if err != nil {
+			t.Fatalf("Failed to query: %v", err)
+		}
+		if len(entries) == 0 || len(entries) >= 12 {
+			t.Fatalf("Expected old entries to be dropped by retention, got %d", len(entries))
+		}
+		for i := 1; i < len(entries); i++ {
+			if entries[i].Time.After(entries[i-1].Time) {
+				t.Fatalf("Expected entries newest first")
+			}
+		}
+	})
+
+	t.Run("Query Filters", func(t *testing.T) {
+		entries, err := log.Query(models.AuditQuery{Outcome: models.AuditDenied, Limit: 1})
+		if err != nil {
+			t.Fatalf("Failed to query: %v", err)
+		}
+		if len(entries) != 1 || entries[0].Actor != "bob" {
+			t.Fatalf("Expected latest denied entry by bob, got %+v", entries)
+		}
+		if !entries[0].Time.Equal(start.Add(9 * time.Second)) {
+			t.Fatalf("Expected entry at %v, got %v", start.Add(9*time.Second), entries[0].Time)
+		}
+
+		entries, err = log.Query(models.AuditQuery{Since: start.Add(10 * time.Second)})
+		if err != nil {
+			t.Fatalf("Failed to query: %v", err)
+		}
+		if len(entries) != 2 {
+			t.Fatalf("Expected 2 entries since 10s, got %d", len(entries))
+		}
+	})
+
+	t.Run("Reopen Appends", func(t *testing.T) {
+		log.Close()
+		reopened, err := NewLog(filePath, Options{})
+		if err != nil {
+			t.Fatalf("Failed to reopen audit log: %v", err)
+		}
+		defer reopened.Close()
+
+		before, _ := reopened.Query(models.AuditQuery{})
+		if err := reopened.Record(models.AuditEntry{Time: time.Now().UTC(), Actor: "carol", Outcome: models.AuditSuccess}); err != nil {
+			t.Fatalf("Failed to record entry: %v", err)
+		}
+		after, _ := reopened.Query(models.AuditQuery{})
+		if len(after) != len(before)+1 || after[0].Actor != "carol" {
+			t.Fatalf("Expected appended entry first, got %d entries", len(after))
+		}
+	})
+}
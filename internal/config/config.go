@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -9,6 +10,9 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/sistemica/traefik-manager/internal/credentials"
+	"github.com/sistemica/traefik-manager/internal/labels"
+	"github.com/sistemica/traefik-manager/internal/tlsconfig"
 )
 
 // Config holds the application configuration
@@ -19,6 +23,20 @@ type Config struct {
 	Logger   Logger
 	Cors     Cors
 	Auth     Auth
+	Audit    Audit
+	Metrics  Metrics
+	// Reconciliation against Traefik's API, nil if no instances are configured
+	Reconcile *Reconcile
+	// Manager-side health probing of backend servers
+	HealthProbe HealthProbe
+	// Change event stream
+	Events Events
+	// Outgoing webhooks on configuration changes
+	Webhooks Webhooks
+	// Parameterized templates instantiated as apps
+	Templates Templates
+	// Named environments in addition to the default one
+	Environments []Environment
 }
 
 type Server struct {
@@ -29,6 +47,31 @@ type Server struct {
 	// Read and write timeouts
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	// TLS settings, nil to serve plain HTTP
+	TLS *TLS
+}
+
+type TLS struct {
+	// Certificate and private key files, reloaded when they change
+	CertFile string
+	KeyFile  string
+	// Minimum TLS version (1.0, 1.1, 1.2, 1.3)
+	MinVersion string
+	// CA file used to verify client certificates, empty to disable mTLS
+	ClientCAFile string
+	// Reject connections without a valid client certificate
+	RequireClientCert bool
+	// Maps client certificate common names to roles
+	ClientRoles map[string]string
+	// Role for verified client certificates without a mapped role, empty to ignore them
+	ClientDefaultRole string
+	// Interval in which the files are checked for changes
+	ReloadInterval time.Duration
+}
+
+type MTLS struct {
+	// Common names of the client certificates allowed, empty to allow any verified certificate
+	AllowedSubjects []string
 }
 
 type Storage struct {
@@ -43,6 +86,110 @@ type Provider struct {
 	ProviderPath string
 	// Auth settings specific to the provider endpoint
 	Auth *Auth
+	// Named provider targets, each served under ProviderPath/<name>
+	Targets []ProviderTarget
+	// Require a verified client certificate on the provider endpoints, nil if disabled
+	MTLS *MTLS
+	// Request header identifying the polling Traefik instance
+	InstanceHeader string
+	// Duration without a poll after which a Traefik instance is reported as stale
+	ClientStaleAfter time.Duration
+	// Maximum duration a long poll is held waiting for a change
+	MaxWait time.Duration
+}
+
+type ProviderTarget struct {
+	// Target name, used as the last path segment of the target endpoint
+	Name string
+	// Routers on any of these entrypoints are assigned to the target
+	EntryPoints []string
+	// Label selector routers must match to be assigned to the target
+	Selector string
+	// Auth settings for this target, falls back to the provider auth if nil
+	Auth *Auth
+}
+
+type Environment struct {
+	// Environment name, used in API and provider paths
+	Name string
+	// Path to the storage file of the environment
+	StorageFilePath string
+	// Provider endpoint path of the environment
+	ProviderPath string
+}
+
+type Audit struct {
+	// Record mutating API calls
+	Enabled bool
+	// Path to the JSON-lines audit file
+	FilePath string
+	// Size in megabytes after which the file is rotated, 0 disables rotation
+	MaxSizeMB int
+	// Number of rotated files to keep, 0 keeps all
+	MaxBackups int
+	// Duration rotated files are kept, 0 keeps them forever
+	MaxAge time.Duration
+}
+
+type Events struct {
+	// Number of change events kept per environment for resuming streams
+	HistorySize int
+}
+
+type Webhooks struct {
+	// Deliver configuration changes to registered webhooks
+	Enabled bool
+	// Path to the JSON file holding the webhooks and their secrets
+	FilePath string
+	// Number of attempts before a delivery is moved to the dead-letter list
+	MaxAttempts int
+	// Delay before the first retry, doubled for every further retry
+	InitialBackoff time.Duration
+	// Maximum delay between retries
+	MaxBackoff time.Duration
+	// Timeout of a single delivery attempt
+	Timeout time.Duration
+	// Number of recent deliveries kept per webhook
+	HistorySize int
+	// Number of dead deliveries kept per webhook
+	DeadLetterSize int
+}
+
+type Templates struct {
+	// Manage templates and instantiate them as apps
+	Enabled bool
+	// Path to the JSON file holding the templates
+	FilePath string
+}
+
+type HealthProbe struct {
+	// Check the servers of load balancer services with a health check
+	Enabled bool
+	// Number of checks kept per server
+	HistorySize int
+}
+
+type Reconcile struct {
+	// Traefik instances whose API is compared with the stored configuration
+	Instances []TraefikInstance
+	// Interval between fetches of the Traefik API
+	Interval time.Duration
+	// Timeout of a single fetch
+	Timeout time.Duration
+}
+
+type TraefikInstance struct {
+	// Instance name shown in the status
+	Name string
+	// Base URL of the Traefik API, e.g. http://traefik:8080
+	URL string
+}
+
+type Metrics struct {
+	// Serve Prometheus metrics
+	Enabled bool
+	// Path of the metrics endpoint, excluded from API authentication
+	Path string
 }
 
 type Logger struct {
@@ -76,6 +223,36 @@ type Auth struct {
 	HeaderName string
 	// API key value
 	Key string
+	// Hashed API keys valid in addition to Key, each optionally until a not-after time
+	KeyHashes []credentials.Credential
+	// Additional API keys bound to a namespace, mapping key to namespace
+	NamespaceKeys map[string]string
+	// File holding the managed API keys
+	KeysFilePath string
+	// OIDC bearer token authentication, nil if disabled
+	OIDC *OIDC
+}
+
+type OIDC struct {
+	// Expected token issuer
+	Issuer string
+	// Expected token audience
+	Audience string
+	// JWKS file path or http(s) URL
+	JWKS string
+	// Interval after which the JWKS is reloaded
+	JWKSRefreshInterval time.Duration
+	// Claim holding the caller name
+	NameClaim string
+	// Claim holding the roles or groups, dots select nested claims
+	RoleClaim string
+	// Claim holding the namespace the caller is bound to
+	NamespaceClaim string
+	// Maps claim values to roles, other values are ignored. Without mapping, values that are
+	// role names map to themselves.
+	RoleMapping map[string]string
+	// Role for tokens without a mapped role, empty to reject them
+	DefaultRole string
 }
 
 // LoadConfig loads the application configuration from environment variables
@@ -106,6 +283,42 @@ func LoadConfig(envFile string) (*Config, error) {
 	config.Server.ReadTimeout = getEnvAsDuration("SERVER_READ_TIMEOUT", 15*time.Second)
 	config.Server.WriteTimeout = getEnvAsDuration("SERVER_WRITE_TIMEOUT", 15*time.Second)
 
+	// TLS configuration
+	if getEnvAsBool("TLS_ENABLED", false) {
+		tlsConfig := &TLS{
+			CertFile:          getEnv("TLS_CERT_FILE", ""),
+			KeyFile:           getEnv("TLS_KEY_FILE", ""),
+			MinVersion:        getEnv("TLS_MIN_VERSION", "1.2"),
+			ClientCAFile:      getEnv("TLS_CLIENT_CA_FILE", ""),
+			RequireClientCert: getEnvAsBool("TLS_REQUIRE_CLIENT_CERT", false),
+			ClientDefaultRole: getEnv("TLS_CLIENT_DEFAULT_ROLE", ""),
+			ReloadInterval:    getEnvAsDuration("TLS_RELOAD_INTERVAL", 30*time.Second),
+		}
+
+		// Client role mapping, given as commonName:role pairs
+		for _, entry := range getEnvAsSlice("TLS_CLIENT_ROLE_MAPPING", nil) {
+			name, role, ok := strings.Cut(strings.TrimSpace(entry), ":")
+			if !ok || name == "" || role == "" {
+				return nil, fmt.Errorf("invalid TLS_CLIENT_ROLE_MAPPING entry, expected commonName:role")
+			}
+			if tlsConfig.ClientRoles == nil {
+				tlsConfig.ClientRoles = make(map[string]string)
+			}
+			tlsConfig.ClientRoles[name] = role
+		}
+
+		if _, err := tlsconfig.ParseVersion(tlsConfig.MinVersion); err != nil {
+			return nil, fmt.Errorf("invalid TLS_MIN_VERSION: %w", err)
+		}
+		if tlsConfig.CertFile == "" || tlsConfig.KeyFile == "" {
+			return nil, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE are required when TLS_ENABLED is true")
+		}
+		if tlsConfig.RequireClientCert && tlsConfig.ClientCAFile == "" {
+			return nil, fmt.Errorf("TLS_CLIENT_CA_FILE is required when TLS_REQUIRE_CLIENT_CERT is true")
+		}
+		config.Server.TLS = tlsConfig
+	}
+
 	// Storage configuration
 	// Use system temp directory by default
 	defaultStoragePath := filepath.Join(os.TempDir(), "traefik-manager.json")
@@ -120,17 +333,143 @@ func LoadConfig(envFile string) (*Config, error) {
 
 	// Provider configuration
 	config.Provider.ProviderPath = getEnv("PROVIDER_PATH", "/traefik/provider")
+	config.Provider.InstanceHeader = getEnv("PROVIDER_INSTANCE_HEADER", "X-Traefik-Instance")
+	config.Provider.ClientStaleAfter = getEnvAsDuration("PROVIDER_CLIENT_STALE_AFTER", time.Minute)
+	config.Provider.MaxWait = getEnvAsDuration("PROVIDER_MAX_WAIT", time.Minute)
 
 	// Provider-specific Auth
 	providerAuthEnabled := getEnvAsBool("PROVIDER_AUTH_ENABLED", false)
 	if providerAuthEnabled {
+		keyHashes, err := getEnvAsKeyHashes("PROVIDER_AUTH_")
+		if err != nil {
+			return nil, err
+		}
 		config.Provider.Auth = &Auth{
 			Enabled:    providerAuthEnabled,
 			HeaderName: getEnv("PROVIDER_AUTH_HEADER_NAME", "X-API-Key"),
 			Key:        getEnv("PROVIDER_AUTH_KEY", ""),
+			KeyHashes:  keyHashes,
+		}
+	}
+
+	// Provider mTLS
+	if getEnvAsBool("PROVIDER_MTLS_ENABLED", false) {
+		if config.Server.TLS == nil || config.Server.TLS.ClientCAFile == "" {
+			return nil, fmt.Errorf("PROVIDER_MTLS_ENABLED requires TLS_ENABLED and TLS_CLIENT_CA_FILE")
+		}
+		config.Provider.MTLS = &MTLS{
+			AllowedSubjects: getEnvAsSlice("PROVIDER_MTLS_ALLOWED_SUBJECTS", nil),
+		}
+	}
+
+	// Provider targets
+	for _, name := range getEnvAsSlice("PROVIDER_TARGETS", nil) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		prefix := "PROVIDER_TARGET_" + envName(name) + "_"
+		target := ProviderTarget{
+			Name:        name,
+			EntryPoints: getEnvAsSlice(prefix+"ENTRYPOINTS", nil),
+			Selector:    getEnv(prefix+"SELECTOR", ""),
+		}
+
+		if _, err := labels.Parse(target.Selector); err != nil {
+			return nil, fmt.Errorf("invalid selector for provider target %s: %w", name, err)
+		}
+
+		keyHashes, err := getEnvAsKeyHashes(prefix + "AUTH_")
+		if err != nil {
+			return nil, err
 		}
+		if key := getEnv(prefix+"AUTH_KEY", ""); key != "" || len(keyHashes) > 0 {
+			target.Auth = &Auth{
+				Enabled:    true,
+				HeaderName: getEnv(prefix+"AUTH_HEADER_NAME", getEnv("PROVIDER_AUTH_HEADER_NAME", "X-API-Key")),
+				Key:        key,
+				KeyHashes:  keyHashes,
+			}
+		}
+
+		config.Provider.Targets = append(config.Provider.Targets, target)
 	}
 
+	// Environments
+	storageExt := filepath.Ext(config.Storage.FilePath)
+	storageBase := strings.TrimSuffix(config.Storage.FilePath, storageExt)
+	for _, name := range getEnvAsSlice("ENVIRONMENTS", nil) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if name == "default" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid environment name: %s", name)
+		}
+
+		prefix := "ENVIRONMENT_" + envName(name) + "_"
+		config.Environments = append(config.Environments, Environment{
+			Name:            name,
+			StorageFilePath: getEnv(prefix+"STORAGE_FILE_PATH", storageBase+"-"+name+storageExt),
+			ProviderPath:    getEnv(prefix+"PROVIDER_PATH", config.Provider.ProviderPath+"/environments/"+name),
+		})
+	}
+
+	// Audit configuration
+	config.Audit.Enabled = getEnvAsBool("AUDIT_ENABLED", false)
+	config.Audit.FilePath = getEnv("AUDIT_FILE_PATH", storageBase+"-audit.jsonl")
+	config.Audit.MaxSizeMB = getEnvAsInt("AUDIT_MAX_SIZE_MB", 100)
+	config.Audit.MaxBackups = getEnvAsInt("AUDIT_MAX_BACKUPS", 10)
+	config.Audit.MaxAge = getEnvAsDuration("AUDIT_MAX_AGE", 90*24*time.Hour)
+
+	// Reconciliation against Traefik's rawdata API
+	for _, entry := range getEnvAsSlice("RECONCILE_TRAEFIK_INSTANCES", nil) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		instance := TraefikInstance{Name: entry, URL: entry}
+		if name, instanceURL, ok := strings.Cut(entry, "="); ok {
+			instance = TraefikInstance{Name: name, URL: instanceURL}
+		}
+		if parsed, err := url.Parse(instance.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid RECONCILE_TRAEFIK_INSTANCES entry %q, expected [name=]http(s)://host:port", entry)
+		}
+		if config.Reconcile == nil {
+			config.Reconcile = &Reconcile{
+				Interval: getEnvAsDuration("RECONCILE_INTERVAL", 30*time.Second),
+				Timeout:  getEnvAsDuration("RECONCILE_TIMEOUT", 5*time.Second),
+			}
+		}
+		config.Reconcile.Instances = append(config.Reconcile.Instances, instance)
+	}
+
+	// Change event stream
+	config.Events.HistorySize = getEnvAsInt("EVENTS_HISTORY_SIZE", 1000)
+
+	// Outgoing webhooks
+	config.Webhooks.Enabled = getEnvAsBool("WEBHOOKS_ENABLED", false)
+	config.Webhooks.FilePath = getEnv("WEBHOOKS_FILE_PATH", storageBase+"-webhooks"+storageExt)
+	config.Webhooks.MaxAttempts = getEnvAsInt("WEBHOOKS_MAX_ATTEMPTS", 8)
+	config.Webhooks.InitialBackoff = getEnvAsDuration("WEBHOOKS_INITIAL_BACKOFF", time.Second)
+	config.Webhooks.MaxBackoff = getEnvAsDuration("WEBHOOKS_MAX_BACKOFF", 5*time.Minute)
+	config.Webhooks.Timeout = getEnvAsDuration("WEBHOOKS_TIMEOUT", 10*time.Second)
+	config.Webhooks.HistorySize = getEnvAsInt("WEBHOOKS_HISTORY_SIZE", 100)
+	config.Webhooks.DeadLetterSize = getEnvAsInt("WEBHOOKS_DEAD_LETTER_SIZE", 1000)
+
+	// Templates
+	config.Templates.Enabled = getEnvAsBool("TEMPLATES_ENABLED", false)
+	config.Templates.FilePath = getEnv("TEMPLATES_FILE_PATH", storageBase+"-templates"+storageExt)
+
+	// Health probing of backend servers
+	config.HealthProbe.Enabled = getEnvAsBool("HEALTH_PROBE_ENABLED", false)
+	config.HealthProbe.HistorySize = getEnvAsInt("HEALTH_PROBE_HISTORY_SIZE", 20)
+
+	// Metrics configuration
+	config.Metrics.Enabled = getEnvAsBool("METRICS_ENABLED", true)
+	config.Metrics.Path = getEnv("METRICS_PATH", "/metrics")
+
 	// Logger configuration
 	config.Logger.Level = getEnv("LOG_LEVEL", "info")
 	config.Logger.Format = getEnv("LOG_FORMAT", "json")
@@ -139,7 +478,7 @@ func LoadConfig(envFile string) (*Config, error) {
 
 	// CORS configuration
 	config.Cors.AllowedOrigins = getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"*"})
-	config.Cors.AllowedMethods = getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
+	config.Cors.AllowedMethods = getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
 	config.Cors.AllowedHeaders = getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization"})
 	config.Cors.AllowCredentials = getEnvAsBool("CORS_ALLOW_CREDENTIALS", false)
 	config.Cors.MaxAge = getEnvAsInt("CORS_MAX_AGE", 300)
@@ -148,15 +487,67 @@ func LoadConfig(envFile string) (*Config, error) {
 	config.Auth.Enabled = getEnvAsBool("AUTH_ENABLED", false)
 	config.Auth.HeaderName = getEnv("AUTH_HEADER_NAME", "X-API-Key")
 	config.Auth.Key = getEnv("AUTH_KEY", "")
+	keyHashes, err := getEnvAsKeyHashes("AUTH_")
+	if err != nil {
+		return nil, err
+	}
+	config.Auth.KeyHashes = keyHashes
+	config.Auth.KeysFilePath = getEnv("AUTH_KEYS_FILE_PATH", storageBase+"-apikeys"+storageExt)
+
+	// Namespace-bound keys, given as namespace:key pairs
+	for _, entry := range getEnvAsSlice("AUTH_NAMESPACE_KEYS", nil) {
+		namespace, key, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || namespace == "" || key == "" || strings.Contains(namespace, "/") {
+			return nil, fmt.Errorf("invalid AUTH_NAMESPACE_KEYS entry, expected namespace:key")
+		}
+		if config.Auth.NamespaceKeys == nil {
+			config.Auth.NamespaceKeys = make(map[string]string)
+		}
+		config.Auth.NamespaceKeys[key] = namespace
+	}
+
+	// OIDC bearer token authentication
+	if getEnvAsBool("OIDC_ENABLED", false) {
+		oidc := &OIDC{
+			Issuer:              getEnv("OIDC_ISSUER", ""),
+			Audience:            getEnv("OIDC_AUDIENCE", ""),
+			JWKS:                getEnv("OIDC_JWKS", ""),
+			JWKSRefreshInterval: getEnvAsDuration("OIDC_JWKS_REFRESH_INTERVAL", time.Hour),
+			NameClaim:           getEnv("OIDC_NAME_CLAIM", "sub"),
+			RoleClaim:           getEnv("OIDC_ROLE_CLAIM", "roles"),
+			NamespaceClaim:      getEnv("OIDC_NAMESPACE_CLAIM", "namespace"),
+			DefaultRole:         getEnv("OIDC_DEFAULT_ROLE", ""),
+		}
+
+		// Role mapping, given as value:role pairs
+		for _, entry := range getEnvAsSlice("OIDC_ROLE_MAPPING", nil) {
+			value, role, ok := strings.Cut(strings.TrimSpace(entry), ":")
+			if !ok || value == "" || role == "" {
+				return nil, fmt.Errorf("invalid OIDC_ROLE_MAPPING entry, expected value:role")
+			}
+			if oidc.RoleMapping == nil {
+				oidc.RoleMapping = make(map[string]string)
+			}
+			oidc.RoleMapping[value] = role
+		}
+
+		if oidc.Issuer == "" || oidc.Audience == "" || oidc.JWKS == "" {
+			return nil, fmt.Errorf("OIDC_ISSUER, OIDC_AUDIENCE and OIDC_JWKS are required when OIDC_ENABLED is true")
+		}
+		if !config.Auth.Enabled {
+			return nil, fmt.Errorf("OIDC_ENABLED requires AUTH_ENABLED to be true")
+		}
+		config.Auth.OIDC = oidc
+	}
 
 	// Validate required configuration
-	if config.Auth.Enabled && config.Auth.Key == "" {
-		return nil, fmt.Errorf("AUTH_KEY is required when AUTH_ENABLED is true")
+	if config.Auth.Enabled && config.Auth.Key == "" && len(config.Auth.KeyHashes) == 0 && config.Auth.OIDC == nil {
+		return nil, fmt.Errorf("AUTH_KEY or AUTH_KEY_HASH is required when AUTH_ENABLED is true and OIDC is disabled")
 	}
 
 	// Validate provider auth if enabled
-	if config.Provider.Auth != nil && config.Provider.Auth.Enabled && config.Provider.Auth.Key == "" {
-		return nil, fmt.Errorf("PROVIDER_AUTH_KEY is required when PROVIDER_AUTH_ENABLED is true")
+	if config.Provider.Auth != nil && config.Provider.Auth.Enabled && config.Provider.Auth.Key == "" && len(config.Provider.Auth.KeyHashes) == 0 {
+		return nil, fmt.Errorf("PROVIDER_AUTH_KEY or PROVIDER_AUTH_KEY_HASH is required when PROVIDER_AUTH_ENABLED is true")
 	}
 
 	return config, nil
@@ -213,6 +604,36 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	return value
 }
 
+// Helper function to turn a resource name into an environment variable name segment
+func envName(name string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+// Helper function to get the hashed keys of <prefix>KEY_HASH and the
+// semicolon-separated <prefix>KEY_HASHES, each given as hash[|not-after]
+func getEnvAsKeyHashes(prefix string) ([]credentials.Credential, error) {
+	var entries []string
+	if hash := getEnv(prefix+"KEY_HASH", ""); hash != "" {
+		entries = append(entries, hash)
+	}
+	if hashes := getEnv(prefix+"KEY_HASHES", ""); hashes != "" {
+		entries = append(entries, strings.Split(hashes, ";")...)
+	}
+
+	var result []credentials.Credential
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		credential, err := credentials.Parse(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %sKEY_HASH entry: %w", prefix, err)
+		}
+		result = append(result, credential)
+	}
+	return result, nil
+}
+
 // Helper function to get an environment variable as string slice
 func getEnvAsSlice(key string, defaultValue []string) []string {
 	valueStr := getEnv(key, "")
@@ -183,7 +183,237 @@ func TestLoadConfig(t *testing.T) {
 		}
 	})
 
+	// Test provider targets configuration
+	t.Run("Provider Targets Config", func(t *testing.T) {
+		os.Setenv("PROVIDER_TARGETS", "edge,internal-lb")
+		os.Setenv("PROVIDER_TARGET_EDGE_ENTRYPOINTS", "web,websecure")
+		os.Setenv("PROVIDER_TARGET_EDGE_AUTH_KEY", "edge-key")
+		os.Setenv("PROVIDER_TARGET_INTERNAL_LB_SELECTOR", "fleet=internal")
+
+		defer func() {
+			os.Unsetenv("PROVIDER_TARGETS")
+			os.Unsetenv("PROVIDER_TARGET_EDGE_ENTRYPOINTS")
+			os.Unsetenv("PROVIDER_TARGET_EDGE_AUTH_KEY")
+			os.Unsetenv("PROVIDER_TARGET_INTERNAL_LB_SELECTOR")
+		}()
+
+		cfg, err := LoadConfig("")
+		if err != nil {
+			t.Fatalf("Failed to load config with provider targets: %v", err)
+		}
+
+		if len(cfg.Provider.Targets) != 2 {
+			t.Fatalf("Expected 2 provider targets, got %d", len(cfg.Provider.Targets))
+		}
+
+		edge := cfg.Provider.Targets[0]
+		if edge.Name != "edge" || len(edge.EntryPoints) != 2 || edge.EntryPoints[1] != "websecure" {
+			t.Errorf("Unexpected edge target configuration: %+v", edge)
+		}
+
+		if edge.Auth == nil || edge.Auth.Key != "edge-key" || edge.Auth.HeaderName != "X-API-Key" {
+			t.Errorf("Expected edge target auth with key 'edge-key', got %+v", edge.Auth)
+		}
+
+		internal := cfg.Provider.Targets[1]
+		if internal.Name != "internal-lb" || internal.Selector != "fleet=internal" || internal.Auth != nil {
+			t.Errorf("Unexpected internal-lb target configuration: %+v", internal)
+		}
+	})
+
+	// Test environments configuration
+	t.Run("Environments Config", func(t *testing.T) {
+		os.Setenv("STORAGE_FILE_PATH", filepath.Join(tempDir, "store.json"))
+		os.Setenv("ENVIRONMENTS", "staging,production")
+		os.Setenv("ENVIRONMENT_PRODUCTION_PROVIDER_PATH", "/prod/provider")
+
+		defer func() {
+			os.Unsetenv("STORAGE_FILE_PATH")
+			os.Unsetenv("ENVIRONMENTS")
+			os.Unsetenv("ENVIRONMENT_PRODUCTION_PROVIDER_PATH")
+		}()
+
+		cfg, err := LoadConfig("")
+		if err != nil {
+			t.Fatalf("Failed to load config with environments: %v", err)
+		}
+
+		if len(cfg.Environments) != 2 {
+			t.Fatalf("Expected 2 environments, got %d", len(cfg.Environments))
+		}
+
+		staging := cfg.Environments[0]
+		if staging.StorageFilePath != filepath.Join(tempDir, "store-staging.json") {
+			t.Errorf("Unexpected staging storage path '%s'", staging.StorageFilePath)
+		}
+		if staging.ProviderPath != "/traefik/provider/environments/staging" {
+			t.Errorf("Unexpected staging provider path '%s'", staging.ProviderPath)
+		}
+
+		if cfg.Environments[1].ProviderPath != "/prod/provider" {
+			t.Errorf("Expected production provider path '/prod/provider', got '%s'", cfg.Environments[1].ProviderPath)
+		}
+
+		// The default environment name is reserved
+		os.Setenv("ENVIRONMENTS", "default")
+		if _, err := LoadConfig(""); err == nil {
+			t.Errorf("Expected error for reserved environment name")
+		}
+	})
+
+	t.Run("OIDC Config", func(t *testing.T) {
+		os.Setenv("AUTH_ENABLED", "true")
+		os.Setenv("OIDC_ENABLED", "true")
+		os.Setenv("OIDC_ISSUER", "https://issuer.example.com")
+		os.Setenv("OIDC_AUDIENCE", "traefik-manager")
+		os.Setenv("OIDC_JWKS", "/etc/traefik-manager/jwks.json")
+		os.Setenv("OIDC_ROLE_MAPPING", "platform-admins:admin,developers:editor")
+
+		defer func() {
+			os.Unsetenv("AUTH_ENABLED")
+			os.Unsetenv("OIDC_ENABLED")
+			os.Unsetenv("OIDC_ISSUER")
+			os.Unsetenv("OIDC_AUDIENCE")
+			os.Unsetenv("OIDC_JWKS")
+			os.Unsetenv("OIDC_ROLE_MAPPING")
+		}()
+
+		// AUTH_KEY is optional when bearer tokens are accepted
+		cfg, err := LoadConfig("")
+		if err != nil {
+			t.Fatalf("Failed to load config with OIDC: %v", err)
+		}
+
+		if cfg.Auth.OIDC == nil {
+			t.Fatalf("Expected OIDC config to be set")
+		}
+		if cfg.Auth.OIDC.RoleClaim != "roles" || cfg.Auth.OIDC.NameClaim != "sub" {
+			t.Errorf("Unexpected default claims: %+v", cfg.Auth.OIDC)
+		}
+		if cfg.Auth.OIDC.RoleMapping["developers"] != "editor" {
+			t.Errorf("Expected developers to map to editor, got %v", cfg.Auth.OIDC.RoleMapping)
+		}
+
+		os.Unsetenv("OIDC_JWKS")
+		if _, err := LoadConfig(""); err == nil {
+			t.Errorf("Expected error when OIDC_JWKS is missing")
+		}
+	})
+
+	t.Run("Hashed Keys Config", func(t *testing.T) {
+		oldHash := "sha256:" + strings.Repeat("a", 64)
+		newHash := "sha256:" + strings.Repeat("b", 64)
+
+		os.Setenv("PROVIDER_AUTH_ENABLED", "true")
+		os.Setenv("PROVIDER_AUTH_KEY_HASH", newHash)
+		os.Setenv("PROVIDER_AUTH_KEY_HASHES", oldHash+"|2025-06-30T00:00:00Z")
+
+		defer func() {
+			os.Unsetenv("PROVIDER_AUTH_ENABLED")
+			os.Unsetenv("PROVIDER_AUTH_KEY_HASH")
+			os.Unsetenv("PROVIDER_AUTH_KEY_HASHES")
+		}()
+
+		// A plaintext PROVIDER_AUTH_KEY is not required when hashes are given
+		cfg, err := LoadConfig("")
+		if err != nil {
+			t.Fatalf("Failed to load config with hashed keys: %v", err)
+		}
+
+		hashes := cfg.Provider.Auth.KeyHashes
+		if len(hashes) != 2 {
+			t.Fatalf("Expected 2 key hashes, got %d", len(hashes))
+		}
+		if hashes[0].Hash != newHash || !hashes[0].NotAfter.IsZero() {
+			t.Errorf("Unexpected first key hash %+v", hashes[0])
+		}
+		if hashes[1].Hash != oldHash || hashes[1].NotAfter.IsZero() {
+			t.Errorf("Expected second key hash with not-after time, got %+v", hashes[1])
+		}
+
+		os.Setenv("PROVIDER_AUTH_KEY_HASH", "plaintext")
+		if _, err := LoadConfig(""); err == nil {
+			t.Errorf("Expected error for unsupported hash format")
+		}
+	})
+
+	t.Run("TLS Config", func(t *testing.T) {
+		os.Setenv("TLS_ENABLED", "true")
+		os.Setenv("TLS_CERT_FILE", "/certs/tls.crt")
+		os.Setenv("TLS_KEY_FILE", "/certs/tls.key")
+		os.Setenv("TLS_CLIENT_CA_FILE", "/certs/ca.crt")
+		os.Setenv("TLS_CLIENT_ROLE_MAPPING", "ci:editor")
+		os.Setenv("PROVIDER_MTLS_ENABLED", "true")
+		os.Setenv("PROVIDER_MTLS_ALLOWED_SUBJECTS", "traefik-edge")
+
+		defer func() {
+			os.Unsetenv("TLS_ENABLED")
+			os.Unsetenv("TLS_CERT_FILE")
+			os.Unsetenv("TLS_KEY_FILE")
+			os.Unsetenv("TLS_CLIENT_CA_FILE")
+			os.Unsetenv("TLS_CLIENT_ROLE_MAPPING")
+			os.Unsetenv("TLS_MIN_VERSION")
+			os.Unsetenv("PROVIDER_MTLS_ENABLED")
+			os.Unsetenv("PROVIDER_MTLS_ALLOWED_SUBJECTS")
+		}()
+
+		cfg, err := LoadConfig("")
+		if err != nil {
+			t.Fatalf("Failed to load config with TLS: %v", err)
+		}
+
+		if cfg.Server.TLS == nil || cfg.Server.TLS.MinVersion != "1.2" {
+			t.Fatalf("Expected TLS config with minimum version 1.2, got %+v", cfg.Server.TLS)
+		}
+		if cfg.Server.TLS.ClientRoles["ci"] != "editor" {
+			t.Errorf("Expected ci to map to editor, got %v", cfg.Server.TLS.ClientRoles)
+		}
+		if cfg.Provider.MTLS == nil || len(cfg.Provider.MTLS.AllowedSubjects) != 1 {
+			t.Errorf("Expected provider mTLS with one allowed subject, got %+v", cfg.Provider.MTLS)
+		}
+
+		os.Setenv("TLS_MIN_VERSION", "0.9")
+		if _, err := LoadConfig(""); err == nil {
+			t.Errorf("Expected error for unsupported TLS version")
+		}
+		os.Unsetenv("TLS_MIN_VERSION")
+
+		// Provider mTLS needs a client CA to verify Traefik's certificate
+		os.Unsetenv("TLS_CLIENT_CA_FILE")
+		if _, err := LoadConfig(""); err == nil {
+			t.Errorf("Expected error for provider mTLS without client CA")
+		}
+	})
+
 	// Test invalid configuration validation
+	t.Run("Reconcile Config", func(t *testing.T) {
+		os.Setenv("RECONCILE_TRAEFIK_INSTANCES", "edge-1=http://traefik-1:8080,http://traefik-2:8080")
+		defer os.Unsetenv("RECONCILE_TRAEFIK_INSTANCES")
+
+		cfg, err := LoadConfig("")
+		if err != nil {
+			t.Fatalf("Failed to load config with reconcile instances: %v", err)
+		}
+
+		if cfg.Reconcile == nil || len(cfg.Reconcile.Instances) != 2 {
+			t.Fatalf("Expected 2 reconcile instances, got %+v", cfg.Reconcile)
+		}
+		if cfg.Reconcile.Instances[0].Name != "edge-1" || cfg.Reconcile.Instances[0].URL != "http://traefik-1:8080" {
+			t.Errorf("Unexpected named instance: %+v", cfg.Reconcile.Instances[0])
+		}
+		if cfg.Reconcile.Instances[1].Name != "http://traefik-2:8080" {
+			t.Errorf("Expected unnamed instance to be named after its URL, got %q", cfg.Reconcile.Instances[1].Name)
+		}
+		if cfg.Reconcile.Interval != 30*time.Second {
+			t.Errorf("Expected default interval of 30s, got %v", cfg.Reconcile.Interval)
+		}
+
+		os.Setenv("RECONCILE_TRAEFIK_INSTANCES", "edge-1=traefik:8080")
+		if _, err := LoadConfig(""); err == nil {
+			t.Errorf("Expected error for an instance URL without scheme")
+		}
+	})
+
 	t.Run("Invalid Config Validation", func(t *testing.T) {
 		// Set invalid configuration (auth enabled but no key)
 		os.Setenv("AUTH_ENABLED", "true")
@@ -0,0 +1,207 @@
+// Package credentials verifies API keys against plaintext and hashed credentials
+// in constant time.
+package credentials
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hash prefixes of the supported formats
+const (
+	sha256Prefix   = "sha256:"
+	argon2idPrefix = "$argon2id$"
+)
+
+// Credential is a hashed API key that is valid until NotAfter, if set.
+//
+// Supported hash formats:
+//   - bcrypt: "$2a$...", "$2b$..." or "$2y$..."
+//   - argon2id: "$argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>" (unpadded base64)
+//   - SHA-256: "sha256:<hex digest>"
+type Credential struct {
+	Hash     string
+	NotAfter time.Time
+}
+
+// Parse parses a credential given as "<hash>" or "<hash>|<RFC 3339 not-after time>"
+func Parse(s string) (Credential, error) {
+	hash, notAfter, hasNotAfter := strings.Cut(strings.TrimSpace(s), "|")
+
+	credential := Credential{Hash: hash}
+	if hasNotAfter {
+		t, err := time.Parse(time.RFC3339, notAfter)
+		if err != nil {
+			return Credential{}, fmt.Errorf("invalid not-after time %q: %w", notAfter, err)
+		}
+		credential.NotAfter = t
+	}
+
+	if err := ValidateHash(hash); err != nil {
+		return Credential{}, err
+	}
+	return credential, nil
+}
+
+// ValidateHash returns an error if the hash is not in one of the supported formats
+func ValidateHash(hash string) error {
+	switch {
+	case strings.HasPrefix(hash, sha256Prefix):
+		digest, err := hex.DecodeString(strings.TrimPrefix(hash, sha256Prefix))
+		if err != nil || len(digest) != sha256.Size {
+			return fmt.Errorf("invalid SHA-256 hash")
+		}
+	case strings.HasPrefix(hash, argon2idPrefix):
+		if _, err := parseArgon2id(hash); err != nil {
+			return err
+		}
+	case strings.HasPrefix(hash, "$2"):
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return fmt.Errorf("invalid bcrypt hash: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported hash format, expected bcrypt, argon2id or sha256")
+	}
+	return nil
+}
+
+// Expired returns true if the credential is no longer valid at the given time
+func (c Credential) Expired(now time.Time) bool {
+	return !c.NotAfter.IsZero() && now.After(c.NotAfter)
+}
+
+// Matches returns true if the key matches the hash of the credential
+func (c Credential) Matches(key string) bool {
+	switch {
+	case strings.HasPrefix(c.Hash, sha256Prefix):
+		expected, err := hex.DecodeString(strings.TrimPrefix(c.Hash, sha256Prefix))
+		if err != nil {
+			return false
+		}
+		digest := sha256.Sum256([]byte(key))
+		return subtle.ConstantTimeCompare(digest[:], expected) == 1
+
+	case strings.HasPrefix(c.Hash, argon2idPrefix):
+		params, err := parseArgon2id(c.Hash)
+		if err != nil {
+			return false
+		}
+		digest := argon2.IDKey([]byte(key), params.salt, params.time, params.memory, params.threads, uint32(len(params.hash)))
+		return subtle.ConstantTimeCompare(digest, params.hash) == 1
+
+	default:
+		// bcrypt compares in constant time
+		return bcrypt.CompareHashAndPassword([]byte(c.Hash), []byte(key)) == nil
+	}
+}
+
+// Equal compares two plaintext keys in constant time
+func Equal(a, b string) bool {
+	// Compare digests so the length of the expected key doesn't leak
+	aDigest := sha256.Sum256([]byte(a))
+	bDigest := sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(aDigest[:], bDigest[:]) == 1
+}
+
+// Set is a group of simultaneously valid keys: an optional plaintext key and any number of hashed credentials.
+// Successful verifications of slow hashes are cached by the SHA-256 digest of the key.
+type Set struct {
+	key         string
+	credentials []Credential
+	verified    sync.Map // key digest to credential index
+	now         func() time.Time
+}
+
+// NewSet creates a Set from a plaintext key, which may be empty, and hashed credentials
+func NewSet(key string, credentials []Credential) *Set {
+	return &Set{
+		key:         key,
+		credentials: credentials,
+		now:         time.Now,
+	}
+}
+
+// Empty returns true if the set contains no keys
+func (s *Set) Empty() bool {
+	return s == nil || (s.key == "" && len(s.credentials) == 0)
+}
+
+// Verify returns true if the key matches one of the valid keys of the set
+func (s *Set) Verify(key string) bool {
+	if s == nil || key == "" {
+		return false
+	}
+
+	if s.key != "" && Equal(key, s.key) {
+		return true
+	}
+
+	now := s.now()
+	digest := sha256.Sum256([]byte(key))
+	cacheKey := string(digest[:])
+
+	if index, ok := s.verified.Load(cacheKey); ok {
+		if !s.credentials[index.(int)].Expired(now) {
+			return true
+		}
+		s.verified.Delete(cacheKey)
+		return false
+	}
+
+	for i, credential := range s.credentials {
+		if credential.Expired(now) {
+			continue
+		}
+		if credential.Matches(key) {
+			s.verified.Store(cacheKey, i)
+			return true
+		}
+	}
+	return false
+}
+
+// argon2idParams are the decoded parts of an argon2id hash
+type argon2idParams struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	hash    []byte
+}
+
+// parseArgon2id decodes a hash in the PHC string format
+func parseArgon2id(hash string) (*argon2idParams, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, fmt.Errorf("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, fmt.Errorf("unsupported argon2id version")
+	}
+
+	params := &argon2idParams{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	var err error
+	if params.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	if params.hash, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(params.hash) == 0 {
+		return nil, fmt.Errorf("invalid argon2id hash value")
+	}
+
+	return params, nil
+}
@@ -0,0 +1,86 @@
+package credentials
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestCredentials(t *testing.T) {
+	sha := sha256.Sum256([]byte("sha-key"))
+	shaHash := "sha256:" + hex.EncodeToString(sha[:])
+
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("bcrypt-key"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Failed to generate bcrypt hash: %v", err)
+	}
+
+	salt := []byte("0123456789abcdef")
+	argonHash := fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, 1024, 1, 1,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(argon2.IDKey([]byte("argon-key"), salt, 1, 1024, 1, 32)))
+
+	t.Run("Hash Formats", func(t *testing.T) {
+		for hash, key := range map[string]string{
+			shaHash:            "sha-key",
+			string(bcryptHash): "bcrypt-key",
+			argonHash:          "argon-key",
+		} {
+			credential, err := Parse(hash)
+			if err != nil {
+				t.Fatalf("Failed to parse %s: %v", hash, err)
+			}
+			if !credential.Matches(key) {
+				t.Errorf("Expected %s to match %s", key, hash)
+			}
+			if credential.Matches("wrong-key") {
+				t.Errorf("Expected wrong key not to match %s", hash)
+			}
+		}
+	})
+
+	t.Run("Invalid Hashes", func(t *testing.T) {
+		for _, hash := range []string{"plaintext", "sha256:abc", "$argon2id$v=19$m=1", "$2b$invalid", shaHash + "|tomorrow"} {
+			if _, err := Parse(hash); err == nil {
+				t.Errorf("Expected error for %q", hash)
+			}
+		}
+	})
+
+	t.Run("Rotation Overlap", func(t *testing.T) {
+		now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		oldCredential, err := Parse(shaHash + "|2025-06-30T00:00:00Z")
+		if err != nil {
+			t.Fatalf("Failed to parse credential: %v", err)
+		}
+		newCredential, _ := Parse(string(bcryptHash))
+
+		set := NewSet("plain-key", []Credential{oldCredential, newCredential})
+		set.now = func() time.Time { return now }
+
+		for _, key := range []string{"plain-key", "sha-key", "bcrypt-key", "bcrypt-key"} {
+			if !set.Verify(key) {
+				t.Errorf("Expected %s to be valid", key)
+			}
+		}
+		if set.Verify("other-key") || set.Verify("") {
+			t.Errorf("Expected unknown keys to be rejected")
+		}
+
+		// The old key stops working after its not-after time, even when cached
+		now = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+		if set.Verify("sha-key") {
+			t.Errorf("Expected expired key to be rejected")
+		}
+		if !set.Verify("bcrypt-key") {
+			t.Errorf("Expected new key to remain valid")
+		}
+	})
+}
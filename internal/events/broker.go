@@ -0,0 +1,112 @@
+// Package events distributes store change events to subscribers and keeps
+// a bounded history so subscribers can resume after a disconnect.
+package events
+
+import (
+	"sync"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// subscriberBuffer is the number of events buffered per subscriber before it is dropped
+const subscriberBuffer = 256
+
+// Broker fans out change events to subscribers
+type Broker struct {
+	mu          sync.Mutex
+	history     []models.ChangeEvent
+	historySize int
+	// version is the version of the most recent event
+	version     uint64
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription receives the events published after it was created
+type Subscription struct {
+	broker *Broker
+	events chan models.ChangeEvent
+}
+
+// NewBroker creates a Broker keeping up to historySize events, starting at the given store version
+func NewBroker(historySize int, version uint64) *Broker {
+	return &Broker{
+		historySize: historySize,
+		version:     version,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish records an event and sends it to all subscribers.
+// It never blocks: subscribers that don't keep up are dropped and have to resume.
+func (b *Broker) Publish(event models.ChangeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.version = event.Version
+	if b.historySize > 0 {
+		b.history = append(b.history, event)
+		if len(b.history) > b.historySize {
+			b.history = b.history[len(b.history)-b.historySize:]
+		}
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Subscribe creates a subscription. If resume is true, the recorded events after the given
+// version are returned as backlog. complete is false if events after the version are no
+// longer in the history, in which case the subscriber has to reload the current state.
+func (b *Broker) Subscribe(after uint64, resume bool) (sub *Subscription, backlog []models.ChangeEvent, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub = &Subscription{
+		broker: b,
+		events: make(chan models.ChangeEvent, subscriberBuffer),
+	}
+	b.subscribers[sub] = struct{}{}
+
+	if !resume || after == b.version {
+		return sub, nil, true
+	}
+
+	for _, event := range b.history {
+		if event.Version == after+1 {
+			complete = true
+		}
+		if event.Version > after {
+			backlog = append(backlog, event)
+		}
+	}
+	return sub, backlog, complete
+}
+
+// Version returns the version of the most recent event
+func (b *Broker) Version() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.version
+}
+
+// Events returns the channel of new events. It is closed when the subscriber was dropped.
+func (s *Subscription) Events() <-chan models.ChangeEvent {
+	return s.events
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	if _, ok := s.broker.subscribers[s]; ok {
+		delete(s.broker.subscribers, s)
+		close(s.events)
+	}
+}
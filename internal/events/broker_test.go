@@ -0,0 +1,69 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+func TestBroker(t *testing.T) {
+	broker := NewBroker(3, 10)
+
+	live, _, _ := broker.Subscribe(0, false)
+	defer live.Close()
+
+	for version := uint64(11); version <= 15; version++ {
+		broker.Publish(models.ChangeEvent{Version: version, Action: models.ChangeCreated, Type: "routers", ID: "r"})
+	}
+
+	t.Run("Live Subscriber", func(t *testing.T) {
+		for version := uint64(11); version <= 15; version++ {
+			event := <-live.Events()
+			if event.Version != version {
+				t.Fatalf("Expected version %d, got %d", version, event.Version)
+			}
+		}
+	})
+
+	tests := []struct {
+		name     string
+		after    uint64
+		backlog  int
+		complete bool
+	}{
+		{name: "Resume Within History", after: 13, backlog: 2, complete: true},
+		{name: "Resume At Oldest Retained", after: 12, backlog: 3, complete: true},
+		{name: "Resume Before History", after: 11, backlog: 3, complete: false},
+		{name: "Up To Date", after: 15, backlog: 0, complete: true},
+		{name: "Unknown Future Version", after: 99, backlog: 0, complete: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, backlog, complete := broker.Subscribe(tt.after, true)
+			defer sub.Close()
+
+			if len(backlog) != tt.backlog || complete != tt.complete {
+				t.Fatalf("Expected %d events and complete=%v, got %d events and complete=%v", tt.backlog, tt.complete, len(backlog), complete)
+			}
+		})
+	}
+
+	t.Run("Slow Subscriber Is Dropped", func(t *testing.T) {
+		slow, _, _ := broker.Subscribe(0, false)
+		for i := 0; i < subscriberBuffer+1; i++ {
+			broker.Publish(models.ChangeEvent{Version: uint64(100 + i)})
+		}
+
+		count := 0
+		for range slow.Events() {
+			count++
+		}
+		if count != subscriberBuffer {
+			t.Fatalf("Expected %d buffered events before the channel closed, got %d", subscriberBuffer, count)
+		}
+
+		// Closing a dropped subscription is safe
+		slow.Close()
+	})
+}
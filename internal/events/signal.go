@@ -0,0 +1,36 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// Signal wakes up everyone waiting for the next change
+type Signal struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// NewSignal creates a Signal
+func NewSignal() *Signal {
+	return &Signal{ch: make(chan struct{})}
+}
+
+// Notify wakes up the current waiters. It doesn't block, so it can be
+// registered with store.ChangeNotifier.OnChange.
+func (s *Signal) Notify(models.ChangeEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	close(s.ch)
+	s.ch = make(chan struct{})
+}
+
+// Wait returns a channel that is closed on the next change
+func (s *Signal) Wait() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ch
+}
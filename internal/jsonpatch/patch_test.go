@@ -0,0 +1,99 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// assertJSON compares two JSON documents structurally
+func assertJSON(t *testing.T, expected string, got []byte) {
+	t.Helper()
+
+	var want, have interface{}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("Invalid expected JSON: %v", err)
+	}
+	if err := json.Unmarshal(got, &have); err != nil {
+		t.Fatalf("Invalid result JSON: %v", err)
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+	}{
+		{"Replace Member", `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{"Add Member", `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{"Remove Member", `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{"Replace Array", `{"a":["b"]}`, `{"a":["c","d"]}`, `{"a":["c","d"]}`},
+		{"Nested Object", `{"a":{"b":"c","d":1}}`, `{"a":{"b":"x","d":null}}`, `{"a":{"b":"x"}}`},
+		{"Object Over Scalar", `{"a":"b"}`, `{"a":{"c":"d"}}`, `{"a":{"c":"d"}}`},
+		{"Non-Object Patch", `{"a":"b"}`, `["c"]`, `["c"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Failed to apply merge patch: %v", err)
+			}
+			assertJSON(t, tt.expected, result)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+		wantErr  bool
+	}{
+		{"Add Member", `{"a":1}`, `[{"op":"add","path":"/b","value":2}]`, `{"a":1,"b":2}`, false},
+		{"Insert Into Array", `{"a":[1,3]}`, `[{"op":"add","path":"/a/1","value":2}]`, `{"a":[1,2,3]}`, false},
+		{"Append To Array", `{"a":[1]}`, `[{"op":"add","path":"/a/-","value":2}]`, `{"a":[1,2]}`, false},
+		{"Remove Array Element", `{"a":[1,2,3]}`, `[{"op":"remove","path":"/a/1"}]`, `{"a":[1,3]}`, false},
+		{"Replace Nested", `{"a":{"b":[{"w":1}]}}`, `[{"op":"replace","path":"/a/b/0/w","value":5}]`, `{"a":{"b":[{"w":5}]}}`, false},
+		{"Move", `{"a":{"b":1},"c":{}}`, `[{"op":"move","from":"/a/b","path":"/c/d"}]`, `{"a":{},"c":{"d":1}}`, false},
+		{"Copy", `{"a":[1]}`, `[{"op":"copy","from":"/a","path":"/b"},{"op":"add","path":"/b/-","value":2}]`, `{"a":[1],"b":[1,2]}`, false},
+		{"Escaped Pointer", `{"a/b":1,"c~d":2}`, `[{"op":"remove","path":"/a~1b"},{"op":"remove","path":"/c~0d"}]`, `{}`, false},
+		{"Test Passes", `{"a":1.0}`, `[{"op":"test","path":"/a","value":1},{"op":"add","path":"/b","value":true}]`, `{"a":1.0,"b":true}`, false},
+		{"Replace Missing Member", `{"a":1}`, `[{"op":"replace","path":"/b","value":2}]`, "", true},
+		{"Remove Out Of Bounds", `{"a":[1]}`, `[{"op":"remove","path":"/a/1"}]`, "", true},
+		{"Move Into Child", `{"a":{"b":{}}}`, `[{"op":"move","from":"/a","path":"/a/b/c"}]`, "", true},
+		{"Unknown Operation", `{}`, `[{"op":"merge","path":"/a"}]`, "", true},
+		{"Invalid Pointer", `{}`, `[{"op":"add","path":"a","value":1}]`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got %s", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to apply patch: %v", err)
+			}
+			assertJSON(t, tt.expected, result)
+		})
+	}
+
+	t.Run("Test Fails", func(t *testing.T) {
+		_, err := Apply([]byte(`{"a":"b"}`), []byte(`[{"op":"test","path":"/a","value":"c"}]`))
+		if !errors.Is(err, ErrTestFailed) {
+			t.Fatalf("Expected ErrTestFailed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package labels
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operators supported in selector requirements
+const (
+	OpEquals    = "="
+	OpNotEquals = "!="
+	OpExists    = "exists"
+	OpNotExists = "!exists"
+)
+
+// Requirement is a single condition of a label selector
+type Requirement struct {
+	Key      string
+	Operator string
+	Value    string
+}
+
+// Selector is a set of requirements that must all match
+type Selector []Requirement
+
+// Parse parses a selector string such as "team=payments,env!=dev,canary,!legacy".
+// An empty string yields an empty selector that matches everything.
+func Parse(s string) (Selector, error) {
+	selector := Selector{}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return selector, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty requirement in selector %q", s)
+		}
+
+		var req Requirement
+		switch {
+		case strings.Contains(part, "!="):
+			kv := strings.SplitN(part, "!=", 2)
+			req = Requirement{Key: kv[0], Operator: OpNotEquals, Value: kv[1]}
+		case strings.Contains(part, "=="):
+			kv := strings.SplitN(part, "==", 2)
+			req = Requirement{Key: kv[0], Operator: OpEquals, Value: kv[1]}
+		case strings.Contains(part, "="):
+			kv := strings.SplitN(part, "=", 2)
+			req = Requirement{Key: kv[0], Operator: OpEquals, Value: kv[1]}
+		case strings.HasPrefix(part, "!"):
+			req = Requirement{Key: part[1:], Operator: OpNotExists}
+		default:
+			req = Requirement{Key: part, Operator: OpExists}
+		}
+
+		req.Key = strings.TrimSpace(req.Key)
+		req.Value = strings.TrimSpace(req.Value)
+		if req.Key == "" {
+			return nil, fmt.Errorf("missing label key in requirement %q", part)
+		}
+
+		selector = append(selector, req)
+	}
+
+	return selector, nil
+}
+
+// Empty returns true if the selector has no requirements
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+// Matches returns true if the given labels satisfy every requirement
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, req := range s {
+		value, ok := labels[req.Key]
+		switch req.Operator {
+		case OpEquals:
+			if !ok || value != req.Value {
+				return false
+			}
+		case OpNotEquals:
+			if ok && value == req.Value {
+				return false
+			}
+		case OpExists:
+			if !ok {
+				return false
+			}
+		case OpNotExists:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String returns the selector in its canonical string form
+func (s Selector) String() string {
+	parts := make([]string, 0, len(s))
+	for _, req := range s {
+		switch req.Operator {
+		case OpExists:
+			parts = append(parts, req.Key)
+		case OpNotExists:
+			parts = append(parts, "!"+req.Key)
+		default:
+			parts = append(parts, req.Key+req.Operator+req.Value)
+		}
+	}
+	return strings.Join(parts, ",")
+}
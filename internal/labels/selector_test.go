@@ -0,0 +1,49 @@
+package labels
+
+import "testing"
+
+func TestSelector(t *testing.T) {
+	labels := map[string]string{
+		"team":  "payments",
+		"env":   "prod",
+		"fleet": "edge",
+	}
+
+	tests := []struct {
+		selector string
+		matches  bool
+	}{
+		{"", true},
+		{"team=payments", true},
+		{"team==payments", true},
+		{"team=search", false},
+		{"env!=dev", true},
+		{"env!=prod", false},
+		{"fleet", true},
+		{"canary", false},
+		{"!canary", true},
+		{"!fleet", false},
+		{"team=payments,env!=dev,fleet", true},
+		{"team=payments,env=dev", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := Parse(tt.selector)
+			if err != nil {
+				t.Fatalf("Failed to parse selector %q: %v", tt.selector, err)
+			}
+
+			if got := selector.Matches(labels); got != tt.matches {
+				t.Fatalf("Expected %q to match=%v, got %v", tt.selector, tt.matches, got)
+			}
+		})
+	}
+
+	// Test invalid selectors
+	for _, invalid := range []string{"team=a,,env=b", "=value", "!"} {
+		if _, err := Parse(invalid); err == nil {
+			t.Fatalf("Expected error parsing %q", invalid)
+		}
+	}
+}
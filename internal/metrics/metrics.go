@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// Default is the registry served on the metrics endpoint
+var Default = NewRegistry()
+
+// Metrics exported by the application
+var (
+	// HTTPRequests counts API requests by method, route and status
+	HTTPRequests = NewCounterVec(Default, "traefik_manager_http_requests_total",
+		"Total number of HTTP requests by method, route and status.", "method", "route", "status")
+	// HTTPRequestDuration observes API request latencies by method, route and status
+	HTTPRequestDuration = NewHistogramVec(Default, "traefik_manager_http_request_duration_seconds",
+		"HTTP request latencies in seconds by method, route and status.", DefaultBuckets, "method", "route", "status")
+
+	// ProviderPolls counts successful provider configuration fetches by endpoint and client
+	ProviderPolls = NewCounterVec(Default, "traefik_manager_provider_polls_total",
+		"Total number of provider configuration polls by endpoint and client.", "endpoint", "client")
+	// ProviderLastPoll holds the time of the last provider poll by endpoint and client
+	ProviderLastPoll = NewGaugeVec(Default, "traefik_manager_provider_last_poll_timestamp_seconds",
+		"Unix time of the last provider configuration poll by endpoint and client.", "endpoint", "client")
+	// ProviderRenderDuration holds the duration of the last provider configuration render by endpoint
+	ProviderRenderDuration = NewGaugeVec(Default, "traefik_manager_provider_render_duration_seconds",
+		"Duration of the last provider configuration render in seconds by endpoint.", "endpoint")
+	// ProviderRenderSize holds the size of the last provider configuration by endpoint
+	ProviderRenderSize = NewGaugeVec(Default, "traefik_manager_provider_render_size_bytes",
+		"Size of the last rendered provider configuration in bytes by endpoint.", "endpoint")
+
+	// Resources holds the number of stored resources by environment and type, updated on every scrape
+	Resources = NewGaugeVec(Default, "traefik_manager_resources",
+		"Number of stored resources by environment and type.", "environment", "type")
+
+	// StoreSaveDuration observes the latency of writing a store to disk
+	StoreSaveDuration = NewHistogramVec(Default, "traefik_manager_store_save_duration_seconds",
+		"Store save latencies in seconds by store file.", DefaultBuckets, "store")
+	// StoreSaveFailures counts failed store saves
+	StoreSaveFailures = NewCounterVec(Default, "traefik_manager_store_save_failures_total",
+		"Total number of failed store saves by store file.", "store")
+	// StoreLastSave holds the time of the last successful store save
+	StoreLastSave = NewGaugeVec(Default, "traefik_manager_store_last_save_success_timestamp_seconds",
+		"Unix time of the last successful store save by store file.", "store")
+
+	// AuthFailures counts rejected requests by reason
+	AuthFailures = NewCounterVec(Default, "traefik_manager_auth_failures_total",
+		"Total number of rejected authentication attempts by reason.", "reason")
+)
+
+// Reasons of authentication failures
+const (
+	AuthMissingCredentials = "missing_credentials"
+	AuthInvalidCredentials = "invalid_credentials"
+	AuthForbidden          = "forbidden"
+)
+
+// ObserveRequest records a completed API request
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	HTTPRequests.Inc(method, route, code)
+	HTTPRequestDuration.Observe(duration.Seconds(), method, route, code)
+}
+
+// ObserveProviderPoll records a served provider configuration
+func ObserveProviderPoll(endpoint, client string, render time.Duration, size int) {
+	ProviderPolls.Inc(endpoint, client)
+	ProviderLastPoll.Set(float64(time.Now().UnixNano())/1e9, endpoint, client)
+	ProviderRenderDuration.Set(render.Seconds(), endpoint)
+	ProviderRenderSize.Set(float64(size), endpoint)
+}
+
+// ObserveStoreSave records a store save attempt
+func ObserveStoreSave(store string, duration time.Duration, err error) {
+	StoreSaveDuration.Observe(duration.Seconds(), store)
+	if err != nil {
+		StoreSaveFailures.Inc(store)
+		return
+	}
+	StoreLastSave.Set(float64(time.Now().UnixNano())/1e9, store)
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("Counter And Gauge", func(t *testing.T) {
+		r := NewRegistry()
+		counter := NewCounterVec(r, "test_requests_total", "Requests.", "route", "status")
+		gauge := NewGaugeVec(r, "test_size_bytes", "Size.")
+
+		counter.Inc("/b", "200")
+		counter.Inc("/a", "200")
+		counter.Add(2, "/a", "200")
+		gauge.Set(1.5)
+
+		var buf bytes.Buffer
+		if err := r.WriteText(&buf); err != nil {
+			t.Fatalf("Failed to write metrics: %v", err)
+		}
+
+		expected := `# HELP test_requests_total Requests.
+# TYPE test_requests_total counter
+test_requests_total{route="/a",status="200"} 3
+test_requests_total{route="/b",status="200"} 1
+# HELP test_size_bytes Size.
+# TYPE test_size_bytes gauge
+test_size_bytes 1.5
+`
+		if buf.String() != expected {
+			t.Fatalf("Unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+		}
+	})
+
+	t.Run("Histogram", func(t *testing.T) {
+		r := NewRegistry()
+		histogram := NewHistogramVec(r, "test_duration_seconds", "Duration.", []float64{0.1, 1}, "route")
+
+		histogram.Observe(0.05, "/a")
+		histogram.Observe(0.5, "/a")
+		histogram.Observe(2, "/a")
+
+		var buf bytes.Buffer
+		if err := r.WriteText(&buf); err != nil {
+			t.Fatalf("Failed to write metrics: %v", err)
+		}
+
+		for _, line := range []string{
+			`test_duration_seconds_bucket{route="/a",le="0.1"} 1`,
+			`test_duration_seconds_bucket{route="/a",le="1"} 2`,
+			`test_duration_seconds_bucket{route="/a",le="+Inf"} 3`,
+			`test_duration_seconds_sum{route="/a"} 2.55`,
+			`test_duration_seconds_count{route="/a"} 3`,
+		} {
+			if !strings.Contains(buf.String(), line+"\n") {
+				t.Errorf("Expected line %q in output:\n%s", line, buf.String())
+			}
+		}
+	})
+
+	t.Run("Label Escaping", func(t *testing.T) {
+		r := NewRegistry()
+		counter := NewCounterVec(r, "test_total", "Test.", "client")
+		counter.Inc("a\"b\\c\nd")
+
+		var buf bytes.Buffer
+		if err := r.WriteText(&buf); err != nil {
+			t.Fatalf("Failed to write metrics: %v", err)
+		}
+
+		if !strings.Contains(buf.String(), `test_total{client="a\"b\\c\nd"} 1`) {
+			t.Fatalf("Label value not escaped:\n%s", buf.String())
+		}
+	})
+}
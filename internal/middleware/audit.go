@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// AuditRecorder persists audit entries
+type AuditRecorder interface {
+	Record(entry models.AuditEntry) error
+}
+
+// Audit creates a middleware that records every mutating request.
+// It must run before Auth so rejected requests are recorded as well.
+func Audit(recorder AuditRecorder) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			switch req.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				return next(c)
+			}
+
+			// Determine the resource before the handler consumes the body
+			resourceType, resourceID := requestResource(c)
+
+			err := next(c)
+
+			entry := models.AuditEntry{
+				Time:         time.Now().UTC(),
+				SourceIP:     c.RealIP(),
+				RequestID:    c.Response().Header().Get(echo.HeaderXRequestID),
+				Method:       req.Method,
+				Path:         req.URL.Path,
+				ResourceType: resourceType,
+				ResourceID:   resourceID,
+				Namespace:    req.Header.Get("X-Namespace"),
+				Status:       c.Response().Status,
+			}
+
+			// Errors returned by handlers are only written by echo's error handler later on
+			if err != nil {
+				entry.Status = http.StatusInternalServerError
+				if he, ok := err.(*echo.HTTPError); ok {
+					entry.Status = he.Code
+				}
+			}
+
+			if identity := GetIdentity(c); identity != nil {
+				entry.Actor = identity.Name
+				if identity.Namespace != "" {
+					entry.Namespace = identity.Namespace
+				}
+			}
+
+			switch {
+			case entry.Status == http.StatusUnauthorized || entry.Status == http.StatusForbidden:
+				entry.Outcome = models.AuditDenied
+			case entry.Status >= 400:
+				entry.Outcome = models.AuditFailure
+			default:
+				entry.Outcome = models.AuditSuccess
+			}
+
+			if recordErr := recorder.Record(entry); recordErr != nil {
+				logger.Error().Err(recordErr).Str("path", entry.Path).Msg("Failed to record audit entry")
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// recordedEntries is an AuditRecorder keeping entries in memory
+type recordedEntries []models.AuditEntry
+
+func (r *recordedEntries) Record(entry models.AuditEntry) error {
+	*r = append(*r, entry)
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+	e := echo.New()
+
+	var entries recordedEntries
+	auditMiddleware := Audit(&entries)
+	authMiddleware := Auth(AuthOptions{
+		Enabled:    true,
+		HeaderName: "X-API-Key",
+		Key:        "test-key",
+	})
+
+	request := func(method, key, body string) {
+		req := httptest.NewRequest(method, "/api/v1/routers", strings.NewReader(body))
+		req.Header.Set("X-API-Key", key)
+		req.Header.Set(echo.HeaderXRealIP, "10.0.0.1")
+		rec := httptest.NewRecorder()
+		rec.Header().Set(echo.HeaderXRequestID, "req-1")
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/v1/routers")
+
+		handler := auditMiddleware(authMiddleware(func(c echo.Context) error {
+			return c.JSON(http.StatusCreated, map[string]string{"id": "web"})
+		}))
+		if err := handler(c); err != nil {
+			t.Fatalf("Middleware returned error: %v", err)
+		}
+	}
+
+	t.Run("Records Mutations", func(t *testing.T) {
+		request(http.MethodPost, "test-key", `{"id":"web"}`)
+
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(entries))
+		}
+		entry := entries[0]
+		if entry.Actor != "admin" || entry.SourceIP != "10.0.0.1" || entry.RequestID != "req-1" {
+			t.Fatalf("Unexpected entry: %+v", entry)
+		}
+		if entry.ResourceType != "routers" || entry.ResourceID != "web" {
+			t.Fatalf("Expected resource routers/web, got %s/%s", entry.ResourceType, entry.ResourceID)
+		}
+		if entry.Status != http.StatusCreated || entry.Outcome != models.AuditSuccess {
+			t.Fatalf("Expected successful outcome, got %d %s", entry.Status, entry.Outcome)
+		}
+	})
+
+	t.Run("Records Denied Requests", func(t *testing.T) {
+		entries = nil
+		request(http.MethodDelete, "wrong-key", "")
+
+		if len(entries) != 1 || entries[0].Outcome != models.AuditDenied || entries[0].Actor != "" {
+			t.Fatalf("Expected anonymous denied entry, got %+v", entries)
+		}
+	})
+
+	t.Run("Skips Reads", func(t *testing.T) {
+		entries = nil
+		request(http.MethodGet, "test-key", "")
+
+		if len(entries) != 0 {
+			t.Fatalf("Expected reads not to be recorded, got %d entries", len(entries))
+		}
+	})
+}
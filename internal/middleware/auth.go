@@ -6,7 +6,10 @@ import (
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/credentials"
 	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/metrics"
+	"github.com/sistemica/traefik-manager/internal/models"
 )
 
 // AuthOptions represents options for the authentication middleware
@@ -15,10 +18,26 @@ type AuthOptions struct {
 	HeaderName   string
 	Key          string
 	ExcludePaths []string
+	// KeyHashes are hashed keys valid in addition to Key, e.g. while rotating keys
+	KeyHashes []credentials.Credential
+	// NamespaceKeys maps additional API keys to the namespace they are bound to
+	NamespaceKeys map[string]string
+	// Keys resolves managed API keys, optional
+	Keys KeyAuthenticator
+	// Bearer validates "Authorization: Bearer" tokens, optional
+	Bearer BearerAuthenticator
+	// AdminPaths are only accessible to the admin role
+	AdminPaths []string
+	// ClientCertRoles maps common names of verified client certificates to roles
+	ClientCertRoles map[string]string
+	// ClientCertDefaultRole applies to verified client certificates without a mapped role
+	ClientCertDefaultRole string
 }
 
 // Auth creates a middleware for API key authentication
 func Auth(opts AuthOptions) echo.MiddlewareFunc {
+	adminKeys := credentials.NewSet(opts.Key, opts.KeyHashes)
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			// Skip auth if disabled
@@ -34,10 +53,33 @@ func Auth(opts AuthOptions) echo.MiddlewareFunc {
 				}
 			}
 
+			// Bearer tokens take precedence over API keys when configured
+			if token, ok := bearerToken(c.Request()); ok && opts.Bearer != nil {
+				identity, err := opts.Bearer.AuthenticateBearer(token)
+				if err != nil {
+					logger.Warn().Err(err).Str("path", path).Msg("Invalid bearer token")
+					metrics.AuthFailures.Inc(metrics.AuthInvalidCredentials)
+					return c.JSON(http.StatusUnauthorized, map[string]string{
+						"code":    "unauthorized",
+						"message": "Invalid bearer token",
+					})
+				}
+				return authorize(c, next, identity, opts)
+			}
+
 			// Check auth header
 			apiKey := c.Request().Header.Get(opts.HeaderName)
+
+			// Fall back to the client certificate of mTLS connections
+			if apiKey == "" {
+				if identity := certificateIdentity(c.Request(), opts); identity != nil {
+					return authorize(c, next, identity, opts)
+				}
+			}
+
 			if apiKey == "" {
 				logger.Warn().Str("path", path).Msg("Missing API key")
+				metrics.AuthFailures.Inc(metrics.AuthMissingCredentials)
 				return c.JSON(http.StatusUnauthorized, map[string]string{
 					"code":    "unauthorized",
 					"message": "API key missing",
@@ -45,15 +87,65 @@ func Auth(opts AuthOptions) echo.MiddlewareFunc {
 			}
 
 			// Validate key
-			if apiKey != opts.Key {
+			identity := authenticateKey(apiKey, adminKeys, opts)
+			if identity == nil {
 				logger.Warn().Str("path", path).Msg("Invalid API key")
+				metrics.AuthFailures.Inc(metrics.AuthInvalidCredentials)
 				return c.JSON(http.StatusUnauthorized, map[string]string{
 					"code":    "unauthorized",
 					"message": "Invalid API key",
 				})
 			}
 
-			return next(c)
+			return authorize(c, next, identity, opts)
 		}
 	}
 }
+
+// authorize stores the identity in the context and checks its role and scopes
+func authorize(c echo.Context, next echo.HandlerFunc, identity *Identity, opts AuthOptions) error {
+	SetIdentity(c, identity)
+
+	if !identity.Authorize(c, opts.AdminPaths) {
+		logger.Warn().Str("path", c.Request().URL.Path).Str("actor", identity.Name).Str("role", identity.Role).Msg("Caller not allowed to perform request")
+		metrics.AuthFailures.Inc(metrics.AuthForbidden)
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"code":    "forbidden",
+			"message": "Not allowed to perform this request",
+		})
+	}
+
+	return next(c)
+}
+
+// bearerToken extracts the token of an "Authorization: Bearer" header
+func bearerToken(req *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(req.Header.Get(echo.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// authenticateKey resolves an API key to an identity, returning nil for unknown keys.
+// Configured keys are compared in constant time.
+func authenticateKey(apiKey string, adminKeys *credentials.Set, opts AuthOptions) *Identity {
+	if adminKeys.Verify(apiKey) {
+		return &Identity{Name: "admin", Role: models.RoleAdmin}
+	}
+
+	// Namespace-bound keys only see the resources of their namespace
+	for key, namespace := range opts.NamespaceKeys {
+		if credentials.Equal(apiKey, key) {
+			return &Identity{Name: "namespace:" + namespace, Namespace: namespace, Role: models.RoleEditor}
+		}
+	}
+
+	if opts.Keys != nil {
+		if identity, ok := opts.Keys.AuthenticateKey(apiKey); ok {
+			return identity
+		}
+	}
+
+	return nil
+}
@@ -1,11 +1,15 @@
 package middleware
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/models"
 )
 
 func TestAuth(t *testing.T) {
@@ -210,4 +214,186 @@ func TestAuth(t *testing.T) {
 			t.Fatalf("Expected body 'success', got '%s'", rec.Body.String())
 		}
 	})
+	// Test namespace-bound API key
+	t.Run("Namespace API Key", func(t *testing.T) {
+		authMiddleware := Auth(AuthOptions{
+			Enabled:       true,
+			HeaderName:    "X-API-Key",
+			Key:           "test-key",
+			NamespaceKeys: map[string]string{"team-a-key": "team-a"},
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-API-Key", "team-a-key")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		var identity *Identity
+		handler := authMiddleware(func(c echo.Context) error {
+			identity = GetIdentity(c)
+			return c.String(http.StatusOK, "success")
+		})
+		if err := handler(c); err != nil {
+			t.Fatalf("Authentication middleware returned error with namespace key: %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		if identity == nil || identity.Namespace != "team-a" {
+			t.Fatalf("Expected identity bound to namespace 'team-a', got %+v", identity)
+		}
+	})
+
+	t.Run("Roles And Scopes", func(t *testing.T) {
+		keys := staticKeys{
+			"reader-key":          {Name: "reader", Role: models.RoleReader},
+			"editor-key":          {Name: "editor", Role: models.RoleEditor},
+			"provider-key":        {Name: "provider", Role: models.RoleProviderOnly},
+			"namespace-admin-key": {Name: "namespace-admin", Role: models.RoleAdmin, Namespace: "team-a"},
+			"scoped-admin-key": {Name: "scoped-admin", Role: models.RoleAdmin, Scopes: []models.APIKeyScope{
+				{ResourceType: "*", IDPattern: "team-a-*"},
+			}},
+			"scoped-key": {Name: "scoped", Role: models.RoleEditor, Scopes: []models.APIKeyScope{
+				{ResourceType: "routers", IDPattern: "team-a-*"},
+			}},
+		}
+		authMiddleware := Auth(AuthOptions{
+			Enabled:    true,
+			HeaderName: "X-API-Key",
+			Key:        "test-key",
+			Keys:       keys,
+			AdminPaths: []string{"/api/v1/apikeys"},
+		})
+
+		tests := []struct {
+			name     string
+			key      string
+			method   string
+			path     string
+			route    string
+			id       string
+			body     string
+			expected int
+		}{
+			{"Reader Reads", "reader-key", http.MethodGet, "/api/v1/routers", "/api/v1/routers", "", "", http.StatusOK},
+			{"Reader Writes", "reader-key", http.MethodDelete, "/api/v1/routers/a", "/api/v1/routers/:id", "a", "", http.StatusForbidden},
+			{"Editor Writes", "editor-key", http.MethodDelete, "/api/v1/routers/a", "/api/v1/routers/:id", "a", "", http.StatusOK},
+			{"Editor Manages Keys", "editor-key", http.MethodGet, "/api/v1/apikeys", "/api/v1/apikeys", "", "", http.StatusForbidden},
+			{"Admin Manages Keys", "test-key", http.MethodGet, "/api/v1/apikeys", "/api/v1/apikeys", "", "", http.StatusOK},
+			{"Namespace Admin Creates Key", "namespace-admin-key", http.MethodPost, "/api/v1/apikeys", "/api/v1/apikeys", "", `{"name":"root","role":"admin"}`, http.StatusForbidden},
+			{"Scoped Admin Creates Key", "scoped-admin-key", http.MethodPost, "/api/v1/apikeys", "/api/v1/apikeys", "", `{"name":"root","role":"admin"}`, http.StatusForbidden},
+			{"Namespace Admin Writes Resources", "namespace-admin-key", http.MethodDelete, "/api/v1/routers/a", "/api/v1/routers/:id", "a", "", http.StatusOK},
+			{"Provider Key On API", "provider-key", http.MethodGet, "/api/v1/routers", "/api/v1/routers", "", "", http.StatusForbidden},
+			{"Scoped Matching ID", "scoped-key", http.MethodPut, "/api/v1/routers/team-a-web", "/api/v1/routers/:id", "team-a-web", "", http.StatusOK},
+			{"Scoped Other ID", "scoped-key", http.MethodPut, "/api/v1/routers/team-b-web", "/api/v1/routers/:id", "team-b-web", "", http.StatusForbidden},
+			{"Scoped Other Type", "scoped-key", http.MethodGet, "/api/v1/services", "/api/v1/services", "", "", http.StatusForbidden},
+			{"Scoped Create Matching", "scoped-key", http.MethodPost, "/api/v1/routers", "/api/v1/routers", "", `{"id":"team-a-api"}`, http.StatusOK},
+			{"Scoped Create Other", "scoped-key", http.MethodPost, "/api/v1/routers", "/api/v1/routers", "", `{"id":"other"}`, http.StatusForbidden},
+			{"Scoped Reads Environments", "scoped-key", http.MethodGet, "/api/v1/environments", "/api/v1/environments", "", "", http.StatusOK},
+			{"Scoped Reads Apps", "scoped-key", http.MethodGet, "/api/v1/apps", "/api/v1/apps", "", "", http.StatusForbidden},
+			{"Scoped Reads App", "scoped-key", http.MethodGet, "/api/v1/apps/team-a-shop", "/api/v1/apps/:id", "team-a-shop", "", http.StatusForbidden},
+			{"Scoped Reads Graph", "scoped-key", http.MethodGet, "/api/v1/environments/staging/graph", "/api/v1/environments/staging/graph", "", "", http.StatusForbidden},
+			{"Unscoped Reads Graph", "reader-key", http.MethodGet, "/api/v1/graph", "/api/v1/graph", "", "", http.StatusOK},
+			{"Unknown Key", "unknown-key", http.MethodGet, "/api/v1/routers", "/api/v1/routers", "", "", http.StatusUnauthorized},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+				req.Header.Set("X-API-Key", tt.key)
+				rec := httptest.NewRecorder()
+				c := e.NewContext(req, rec)
+				c.SetPath(tt.route)
+				if tt.id != "" {
+					c.SetParamNames("id")
+					c.SetParamValues(tt.id)
+				}
+
+				var body string
+				handler := authMiddleware(func(c echo.Context) error {
+					data, _ := io.ReadAll(c.Request().Body)
+					body = string(data)
+					return c.String(http.StatusOK, "success")
+				})
+				if err := handler(c); err != nil {
+					t.Fatalf("Authentication middleware returned error: %v", err)
+				}
+
+				if rec.Code != tt.expected {
+					t.Fatalf("Expected status code %d, got %d", tt.expected, rec.Code)
+				}
+				if rec.Code == http.StatusOK && body != tt.body {
+					t.Fatalf("Expected request body to be preserved, got '%s'", body)
+				}
+			})
+		}
+	})
+
+	t.Run("Bearer Token", func(t *testing.T) {
+		authMiddleware := Auth(AuthOptions{
+			Enabled:    true,
+			HeaderName: "X-API-Key",
+			Key:        "test-key",
+			Bearer:     staticBearer{"valid-token": {Name: "oidc:alice", Role: models.RoleReader}},
+		})
+
+		tests := []struct {
+			name     string
+			header   string
+			method   string
+			expected int
+		}{
+			{"Valid Token", "Bearer valid-token", http.MethodGet, http.StatusOK},
+			{"Role Enforced", "Bearer valid-token", http.MethodDelete, http.StatusForbidden},
+			{"Invalid Token", "Bearer invalid-token", http.MethodGet, http.StatusUnauthorized},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := httptest.NewRequest(tt.method, "/api/v1/routers", nil)
+				req.Header.Set("Authorization", tt.header)
+				rec := httptest.NewRecorder()
+				c := e.NewContext(req, rec)
+
+				if err := authMiddleware(testHandler)(c); err != nil {
+					t.Fatalf("Authentication middleware returned error: %v", err)
+				}
+				if rec.Code != tt.expected {
+					t.Fatalf("Expected status code %d, got %d", tt.expected, rec.Code)
+				}
+			})
+		}
+
+		// API keys keep working next to bearer tokens
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/routers", nil)
+		req.Header.Set("X-API-Key", "test-key")
+		rec := httptest.NewRecorder()
+		if err := authMiddleware(testHandler)(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("Authentication middleware returned error: %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d with API key, got %d", http.StatusOK, rec.Code)
+		}
+	})
+}
+
+// staticKeys is a KeyAuthenticator backed by a fixed set of identities
+type staticKeys map[string]*Identity
+
+func (k staticKeys) AuthenticateKey(key string) (*Identity, bool) {
+	identity, ok := k[key]
+	return identity, ok
+}
+
+// staticBearer is a BearerAuthenticator backed by a fixed set of identities
+type staticBearer map[string]*Identity
+
+func (b staticBearer) AuthenticateBearer(token string) (*Identity, error) {
+	identity, ok := b[token]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return identity, nil
 }
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"crypto/x509"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/metrics"
+)
+
+// ClientCertificate returns the verified client certificate of a TLS request, or nil
+func ClientCertificate(req *http.Request) *x509.Certificate {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return req.TLS.VerifiedChains[0][0]
+}
+
+// RequireClientCert creates a middleware that only lets requests with a verified client certificate through.
+// If allowedSubjects is not empty, the common name of the certificate must be one of them.
+func RequireClientCert(allowedSubjects []string) echo.MiddlewareFunc {
+	allowed := make(map[string]bool, len(allowedSubjects))
+	for _, subject := range allowedSubjects {
+		allowed[subject] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			cert := ClientCertificate(c.Request())
+			if cert == nil {
+				logger.Warn().Str("path", c.Request().URL.Path).Msg("Missing client certificate")
+				metrics.AuthFailures.Inc(metrics.AuthMissingCredentials)
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Client certificate required",
+				})
+			}
+
+			if len(allowed) > 0 && !allowed[cert.Subject.CommonName] {
+				logger.Warn().Str("path", c.Request().URL.Path).Str("subject", cert.Subject.CommonName).Msg("Client certificate not allowed")
+				metrics.AuthFailures.Inc(metrics.AuthForbidden)
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "Client certificate not allowed",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// certificateIdentity maps the verified client certificate of a request to an identity,
+// returning nil if there is none or its subject has no role
+func certificateIdentity(req *http.Request, opts AuthOptions) *Identity {
+	cert := ClientCertificate(req)
+	if cert == nil {
+		return nil
+	}
+
+	name := cert.Subject.CommonName
+	role, ok := opts.ClientCertRoles[name]
+	if !ok {
+		role = opts.ClientCertDefaultRole
+	}
+	if role == "" {
+		return nil
+	}
+
+	return &Identity{Name: "cert:" + name, Role: role}
+}
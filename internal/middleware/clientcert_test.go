@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+func TestClientCertificates(t *testing.T) {
+	e := echo.New()
+
+	// newRequest creates a request presenting a verified client certificate with the given common name
+	newRequest := func(method, commonName string) *http.Request {
+		req := httptest.NewRequest(method, "/api/v1/routers", nil)
+		if commonName != "" {
+			cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		}
+		return req
+	}
+
+	okHandler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "success")
+	}
+
+	t.Run("Certificate Identity", func(t *testing.T) {
+		authMiddleware := Auth(AuthOptions{
+			Enabled:         true,
+			HeaderName:      "X-API-Key",
+			Key:             "test-key",
+			ClientCertRoles: map[string]string{"ci": models.RoleEditor, "dashboard": models.RoleReader},
+		})
+
+		tests := []struct {
+			name       string
+			method     string
+			commonName string
+			expected   int
+		}{
+			{"Mapped Editor", http.MethodDelete, "ci", http.StatusOK},
+			{"Mapped Reader Writes", http.MethodDelete, "dashboard", http.StatusForbidden},
+			{"Unmapped Subject", http.MethodGet, "unknown", http.StatusUnauthorized},
+			{"No Certificate", http.MethodGet, "", http.StatusUnauthorized},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				c := e.NewContext(newRequest(tt.method, tt.commonName), rec)
+
+				var identity *Identity
+				handler := authMiddleware(func(c echo.Context) error {
+					identity = GetIdentity(c)
+					return okHandler(c)
+				})
+				if err := handler(c); err != nil {
+					t.Fatalf("Authentication middleware returned error: %v", err)
+				}
+				if rec.Code != tt.expected {
+					t.Fatalf("Expected status code %d, got %d", tt.expected, rec.Code)
+				}
+				if rec.Code == http.StatusOK && identity.Name != "cert:"+tt.commonName {
+					t.Fatalf("Expected identity cert:%s, got %s", tt.commonName, identity.Name)
+				}
+			})
+		}
+	})
+
+	t.Run("Require Client Certificate", func(t *testing.T) {
+		requireCert := RequireClientCert([]string{"traefik-edge"})
+
+		for commonName, expected := range map[string]int{
+			"traefik-edge":  http.StatusOK,
+			"traefik-other": http.StatusForbidden,
+			"":              http.StatusUnauthorized,
+		} {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(newRequest(http.MethodGet, commonName), rec)
+			if err := requireCert(okHandler)(c); err != nil {
+				t.Fatalf("Middleware returned error: %v", err)
+			}
+			if rec.Code != expected {
+				t.Errorf("Expected status code %d for %q, got %d", expected, commonName, rec.Code)
+			}
+		}
+	})
+}
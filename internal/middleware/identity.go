@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// identityContextKey is the echo context key holding the authenticated identity
+const identityContextKey = "identity"
+
+// Identity describes the authenticated caller of a request
+type Identity struct {
+	// Name identifies the caller in logs
+	Name string
+	// Namespace limits the caller to the resources of one namespace, empty for unrestricted access
+	Namespace string
+	// Role determines which operations the caller may perform
+	Role string
+	// Scopes optionally limit the caller to some resource types and IDs
+	Scopes []models.APIKeyScope
+}
+
+// KeyAuthenticator resolves API keys to identities
+type KeyAuthenticator interface {
+	AuthenticateKey(key string) (*Identity, bool)
+}
+
+// BearerAuthenticator resolves bearer tokens to identities
+type BearerAuthenticator interface {
+	AuthenticateBearer(token string) (*Identity, error)
+}
+
+// resourceTypes are the path segments of the resource endpoints scopes apply to
+var resourceTypes = map[string]bool{
+	"routers":     true,
+	"services":    true,
+	"middlewares": true,
+}
+
+// crossResourcePaths are the path segments of endpoints returning resources of all types,
+// which scopes can't be applied to
+var crossResourcePaths = map[string]bool{
+	"apps":      true,
+	"graph":     true,
+	"events":    true,
+	"reconcile": true,
+}
+
+// Unrestricted returns true if the identity is neither bound to a namespace nor limited by scopes
+func (i *Identity) Unrestricted() bool {
+	return i.Namespace == "" && len(i.Scopes) == 0
+}
+
+// Authorize returns true if the identity may perform the request.
+// Paths starting with one of adminPaths are reserved for the admin role without restrictions,
+// as API keys, the audit log and webhooks span all namespaces and resources.
+func (i *Identity) Authorize(c echo.Context, adminPaths []string) bool {
+	req := c.Request()
+	readOnly := req.Method == http.MethodGet || req.Method == http.MethodHead
+
+	for _, adminPath := range adminPaths {
+		if strings.HasPrefix(req.URL.Path, adminPath) {
+			return i.Role == models.RoleAdmin && i.Unrestricted()
+		}
+	}
+
+	switch i.Role {
+	case models.RoleAdmin, models.RoleEditor:
+	case models.RoleReader:
+		if !readOnly {
+			return false
+		}
+	default:
+		// Provider-only and unknown roles have no API access
+		return false
+	}
+
+	if len(i.Scopes) == 0 {
+		return true
+	}
+
+	resourceType, id := requestResource(c)
+	if resourceType == "" {
+		// Scoped keys may only read non-resource endpoints, except those listing resources of all types
+		for _, segment := range strings.Split(c.Path(), "/") {
+			if crossResourcePaths[segment] {
+				return false
+			}
+		}
+		return readOnly
+	}
+
+	return i.scopesAllow(resourceType, id, readOnly)
+}
+
+// InScope returns true if the scopes of the identity allow changing the given resource.
+// The resource type is one of the resource endpoints, e.g. "routers".
+func (i *Identity) InScope(resourceType, id string) bool {
+	if len(i.Scopes) == 0 {
+		return true
+	}
+	return resourceTypes[resourceType] && id != "" && i.scopesAllow(resourceType, id, false)
+}
+
+// scopesAllow returns true if one of the scopes of the identity matches the resource
+func (i *Identity) scopesAllow(resourceType, id string, readOnly bool) bool {
+	for _, scope := range i.Scopes {
+		if scope.ResourceType != "*" && scope.ResourceType != resourceType {
+			continue
+		}
+		if scope.IDPattern == "" {
+			return true
+		}
+		if id == "" {
+			// Listing is allowed, creating requires an ID matching the pattern
+			if readOnly {
+				return true
+			}
+			continue
+		}
+		if matched, err := path.Match(scope.IDPattern, id); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// requestResource determines the resource type and ID a request operates on.
+// For creations the ID is read from the JSON body, which is restored afterwards.
+func requestResource(c echo.Context) (string, string) {
+	resourceType := ""
+	for _, segment := range strings.Split(c.Path(), "/") {
+		if resourceTypes[segment] {
+			resourceType = segment
+		}
+	}
+	if resourceType == "" {
+		return "", ""
+	}
+
+	id := c.Param("id")
+	req := c.Request()
+	if id == "" && req.Method == http.MethodPost && req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err == nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			var data struct {
+				ID string `json:"id"`
+			}
+			if json.Unmarshal(body, &data) == nil {
+				id = data.ID
+			}
+		}
+	}
+
+	return resourceType, id
+}
+
+// SetIdentity stores the authenticated identity in the request context
+func SetIdentity(c echo.Context, identity *Identity) {
+	c.Set(identityContextKey, identity)
+}
+
+// GetIdentity returns the authenticated identity of the request, or nil if the request is anonymous
+func GetIdentity(c echo.Context) *Identity {
+	identity, _ := c.Get(identityContextKey).(*Identity)
+	return identity
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/metrics"
 )
 
 // Logger creates a middleware that logs HTTP requests
@@ -60,8 +61,20 @@ func Logger() echo.MiddlewareFunc {
 				responseLoggerCtx = responseLoggerCtx.Str("request_id", requestID)
 			}
 
+			// Attribute the request to the authenticated caller
+			if identity := GetIdentity(c); identity != nil {
+				responseLoggerCtx = responseLoggerCtx.Str("actor", identity.Name)
+			}
+
 			responseLogger := responseLoggerCtx.Logger()
 
+			// Record the request by route template to keep the number of series bounded
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			metrics.ObserveRequest(req.Method, route, res.Status, duration)
+
 			// Log at appropriate level based on status code
 			logEvent := responseLogger.Info()
 			if err != nil {
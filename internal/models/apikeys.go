@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Roles that can be assigned to API keys
+const (
+	// RoleReader can read all resources
+	RoleReader = "reader"
+	// RoleEditor can read and modify resources
+	RoleEditor = "editor"
+	// RoleAdmin can do everything, including managing API keys
+	RoleAdmin = "admin"
+	// RoleProviderOnly can only fetch the Traefik provider configuration
+	RoleProviderOnly = "provider-only"
+)
+
+// ValidRole returns true if the role is one of the known API key roles
+func ValidRole(role string) bool {
+	switch role {
+	case RoleReader, RoleEditor, RoleAdmin, RoleProviderOnly:
+		return true
+	}
+	return false
+}
+
+// APIKeyScope restricts an API key to resources of one type, optionally matching an ID pattern
+type APIKeyScope struct {
+	// ResourceType is "routers", "services", "middlewares" or "*"
+	ResourceType string `json:"resourceType"`
+	// IDPattern is a glob pattern (e.g. "team-a-*") the resource ID must match
+	IDPattern string `json:"idPattern,omitempty"`
+}
+
+// APIKey describes a named API key. The key itself is only returned once on creation.
+type APIKey struct {
+	Name      string        `json:"name"`
+	Role      string        `json:"role"`
+	Namespace string        `json:"namespace,omitempty"`
+	Scopes    []APIKeyScope `json:"scopes,omitempty"`
+	Prefix    string        `json:"prefix,omitempty"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+// APIKeyCreated is returned when an API key is created and contains the plaintext key
+type APIKeyCreated struct {
+	APIKey
+	Key string `json:"key"`
+}
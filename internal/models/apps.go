@@ -0,0 +1,28 @@
+package models
+
+// App bundles routers with the services and middlewares they use under one name.
+// An app is created and deleted as a whole.
+type App struct {
+	ID          string       `json:"id"`
+	Description string       `json:"description,omitempty"`
+	Routers     []Router     `json:"routers"`
+	Services    []Service    `json:"services"`
+	Middlewares []Middleware `json:"middlewares"`
+	ResourceMeta
+}
+
+// AppComponents lists the IDs of the routers, services and middlewares of an app
+type AppComponents struct {
+	Routers     []string `json:"routers"`
+	Services    []string `json:"services"`
+	Middlewares []string `json:"middlewares"`
+}
+
+// AppDeleteResponse represents the response of deleting an app
+type AppDeleteResponse struct {
+	ID string `json:"id"`
+	// Deleted lists the components deleted with the app
+	Deleted AppComponents `json:"deleted"`
+	// Kept lists the components shared with other apps or still used by other resources
+	Kept AppComponents `json:"kept"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Audit outcomes
+const (
+	// AuditSuccess is recorded for requests that completed with a 2xx or 3xx status
+	AuditSuccess = "success"
+	// AuditDenied is recorded for requests rejected by authentication or authorization
+	AuditDenied = "denied"
+	// AuditFailure is recorded for all other failed requests
+	AuditFailure = "failure"
+)
+
+// AuditEntry records a single mutating API call
+type AuditEntry struct {
+	Time         time.Time `json:"time"`
+	Actor        string    `json:"actor,omitempty"`
+	SourceIP     string    `json:"sourceIp"`
+	RequestID    string    `json:"requestId,omitempty"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	ResourceType string    `json:"resourceType,omitempty"`
+	ResourceID   string    `json:"resourceId,omitempty"`
+	Namespace    string    `json:"namespace,omitempty"`
+	Status       int       `json:"status"`
+	Outcome      string    `json:"outcome"`
+}
+
+// AuditQuery filters audit entries, empty fields match everything
+type AuditQuery struct {
+	Actor        string
+	ResourceType string
+	ResourceID   string
+	Method       string
+	Outcome      string
+	Since        time.Time
+	Until        time.Time
+	// Limit caps the number of returned entries, newest first
+	Limit int
+}
+
+// Matches returns true if the entry matches the query
+func (q AuditQuery) Matches(entry AuditEntry) bool {
+	if q.Actor != "" && entry.Actor != q.Actor {
+		return false
+	}
+	if q.ResourceType != "" && entry.ResourceType != q.ResourceType {
+		return false
+	}
+	if q.ResourceID != "" && entry.ResourceID != q.ResourceID {
+		return false
+	}
+	if q.Method != "" && entry.Method != q.Method {
+		return false
+	}
+	if q.Outcome != "" && entry.Outcome != q.Outcome {
+		return false
+	}
+	if !q.Since.IsZero() && entry.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && entry.Time.After(q.Until) {
+		return false
+	}
+	return true
+}
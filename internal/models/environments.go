@@ -0,0 +1,45 @@
+package models
+
+// Promotion actions describing what happens to a resource in the target environment
+const (
+	PromotionCreate    = "create"
+	PromotionUpdate    = "update"
+	PromotionUnchanged = "unchanged"
+	PromotionConflict  = "conflict"
+)
+
+// Environment describes a named environment
+type Environment struct {
+	Name         string `json:"name"`
+	ProviderPath string `json:"providerPath"`
+}
+
+// PromotionRequest selects the resources to copy from one environment to another.
+// Services and middlewares referenced by the selected resources are always included.
+type PromotionRequest struct {
+	All         bool     `json:"all,omitempty"`
+	Routers     []string `json:"routers,omitempty"`
+	Services    []string `json:"services,omitempty"`
+	Middlewares []string `json:"middlewares,omitempty"`
+	DryRun      bool     `json:"dryRun,omitempty"`
+	Overwrite   bool     `json:"overwrite,omitempty"`
+}
+
+// PromotionChange describes the effect of a promotion on a single resource
+type PromotionChange struct {
+	ResourceType string      `json:"resourceType"`
+	ID           string      `json:"id"`
+	Action       string      `json:"action"`
+	Current      interface{} `json:"current,omitempty"`
+	Promoted     interface{} `json:"promoted,omitempty"`
+}
+
+// PromotionResult is the diff preview or outcome of a promotion
+type PromotionResult struct {
+	From      string            `json:"from"`
+	To        string            `json:"to"`
+	DryRun    bool              `json:"dryRun"`
+	Applied   bool              `json:"applied"`
+	Changes   []PromotionChange `json:"changes"`
+	Conflicts []PromotionChange `json:"conflicts,omitempty"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Change actions
+const (
+	ChangeCreated = "created"
+	ChangeUpdated = "updated"
+	ChangeDeleted = "deleted"
+)
+
+// ChangeEvent describes a change of a stored resource
+type ChangeEvent struct {
+	// Version is the store version after the change, increasing with every change
+	Version uint64    `json:"version"`
+	Action  string    `json:"action"`
+	Type    string    `json:"type"`
+	ID      string    `json:"id"`
+	Actor   string    `json:"actor,omitempty"`
+	Time    time.Time `json:"time"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // ResourceResponse represents a standard response for resource operations
 type ResourceResponse struct {
 	ID      string `json:"id"`
@@ -8,6 +10,59 @@ type ResourceResponse struct {
 	Deleted bool   `json:"deleted"`
 }
 
+// ResourceRef identifies a router, service, middleware or app
+type ResourceRef struct {
+	ResourceType string `json:"resourceType"` // "router", "service", "middleware", "app"
+	ID           string `json:"id"`
+}
+
+// Graph is the dependency graph of routers, services and middlewares.
+// Its edges point from a resource to the resource it references.
+type Graph struct {
+	Nodes []ResourceRef `json:"nodes"`
+	Edges []GraphEdge   `json:"edges"`
+}
+
+// GraphEdge is a reference of one resource to another
+type GraphEdge struct {
+	From  ResourceRef `json:"from"`
+	To    ResourceRef `json:"to"`
+	Field string      `json:"field"` // the field of From holding the reference
+}
+
+// CascadeDeleteResponse represents the response of a delete with cascade or dry run,
+// listing the deleted resources in the order they were deleted, dependents first
+type CascadeDeleteResponse struct {
+	ID      string        `json:"id"`
+	DryRun  bool          `json:"dryRun"`
+	Deleted []ResourceRef `json:"deleted"`
+}
+
+// RenameRequest represents a request to change the ID of a service or middleware
+type RenameRequest struct {
+	ID string `json:"id"`
+}
+
+// RenameResponse represents the response of a rename, listing the resources
+// whose references to the renamed resource were rewritten
+type RenameResponse struct {
+	ID         string        `json:"id"`
+	PreviousID string        `json:"previousId"`
+	Updated    []ResourceRef `json:"updated"`
+}
+
+// BulkDeleteResponse represents the response of deleting the resources matching a label selector
+type BulkDeleteResponse struct {
+	Deleted []string            `json:"deleted"`
+	Failed  []BulkDeleteFailure `json:"failed,omitempty"`
+}
+
+// BulkDeleteFailure describes a resource that matched but could not be deleted
+type BulkDeleteFailure struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
 // ErrorResponse represents a standard error response
 type ErrorResponse struct {
 	Error   string `json:"error"`
@@ -25,6 +80,9 @@ type Router struct {
 	Priority      int            `json:"priority,omitempty"`
 	TLS           *RouterTLS     `json:"tls,omitempty"`
 	Observability *Observability `json:"observability,omitempty"`
+	Labels        Labels         `json:"labels,omitempty"`
+	Annotations   Annotations    `json:"annotations,omitempty"`
+	ResourceMeta
 }
 
 // RouterTLS represents TLS configuration for a router
@@ -56,6 +114,10 @@ type Service struct {
 	Weighted     *WeightedService     `json:"weighted,omitempty"`
 	Mirroring    *MirroringService    `json:"mirroring,omitempty"`
 	Failover     *FailoverService     `json:"failover,omitempty"`
+	Shared       bool                 `json:"shared,omitempty"`
+	Labels       Labels               `json:"labels,omitempty"`
+	Annotations  Annotations          `json:"annotations,omitempty"`
+	ResourceMeta
 }
 
 // LoadBalancerService represents a load balancer service configuration
@@ -145,14 +207,37 @@ type StickyCookie struct {
 	Path     string `json:"path,omitempty"`
 }
 
+// Labels represents free-form key/value metadata attached to a resource.
+// Labels can be matched by selectors and are not part of the provider configuration.
+type Labels map[string]string
+
+// Annotations represents free-form key/value notes attached to a resource, such as
+// an owner's contact or a ticket. Unlike labels they can't be selected on.
+type Annotations map[string]string
+
+// ResourceMeta holds the metadata the store maintains for routers, services and middlewares.
+// It is returned by the API, but values sent by clients are ignored.
+type ResourceMeta struct {
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	CreatedBy string     `json:"createdBy,omitempty"`
+	UpdatedBy string     `json:"updatedBy,omitempty"`
+	// ResourceVersion is the store version of the last change of the resource
+	ResourceVersion uint64 `json:"resourceVersion,omitempty"`
+}
+
 // Duration represents a time duration string that can be unmarshaled from JSON
 type Duration string
 
 // Middleware represents a Traefik middleware configuration
 type Middleware struct {
-	ID     string           `json:"id"`
-	Type   string           `json:"type"`
-	Config MiddlewareConfig `json:"config"`
+	ID          string           `json:"id"`
+	Type        string           `json:"type"`
+	Config      MiddlewareConfig `json:"config"`
+	Shared      bool             `json:"shared,omitempty"`
+	Labels      Labels           `json:"labels,omitempty"`
+	Annotations Annotations      `json:"annotations,omitempty"`
+	ResourceMeta
 }
 
 // DynamicConfig represents a dynamic configuration for Traefik
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// ProviderClient is a Traefik instance polling a provider endpoint
+type ProviderClient struct {
+	// Endpoint is the provider path the client polls
+	Endpoint string `json:"endpoint"`
+	// Instance is the value of the instance header, if the client sends one
+	Instance   string    `json:"instance,omitempty"`
+	RemoteAddr string    `json:"remoteAddr"`
+	UserAgent  string    `json:"userAgent,omitempty"`
+	FirstPoll  time.Time `json:"firstPoll"`
+	LastPoll   time.Time `json:"lastPoll"`
+	Polls      int64     `json:"polls"`
+	// ConfigHash is the hash of the configuration served on the last poll
+	ConfigHash string `json:"configHash"`
+	// SecondsSinceLastPoll is the time since the last poll when the list was requested
+	SecondsSinceLastPoll float64 `json:"secondsSinceLastPoll"`
+	// Stale is true if the client hasn't polled within the staleness threshold
+	Stale bool `json:"stale"`
+	// UpToDate is true if the client received the configuration most recently served on its endpoint
+	UpToDate bool `json:"upToDate"`
+}
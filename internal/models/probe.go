@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Backend server states reported by the health prober
+const (
+	ServerUp      = "up"
+	ServerDown    = "down"
+	ServerUnknown = "unknown"
+)
+
+// HealthProbe is the result of a single health check of a backend server
+type HealthProbe struct {
+	Time       time.Time `json:"time"`
+	Up         bool      `json:"up"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	// LatencyMs is the duration of the check in milliseconds
+	LatencyMs int64 `json:"latencyMs"`
+}
+
+// ServerHealth is the health of a backend server of a load balancer service
+type ServerHealth struct {
+	URL string `json:"url"`
+	// State is up, down or unknown if the server hasn't been checked yet
+	State      string    `json:"state"`
+	LastCheck  time.Time `json:"lastCheck,omitempty"`
+	LastChange time.Time `json:"lastChange,omitempty"`
+	// History holds the most recent checks, oldest first
+	History []HealthProbe `json:"history"`
+}
+
+// ServiceHealth is the health of the backend servers of a service
+type ServiceHealth struct {
+	ServiceID string `json:"serviceId"`
+	// Probed is false if the service has no load balancer health check to probe with
+	Probed  bool           `json:"probed"`
+	Servers []ServerHealth `json:"servers"`
+}
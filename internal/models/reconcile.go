@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Traefik runtime statuses reported by the rawdata API
+const (
+	TraefikStatusEnabled  = "enabled"
+	TraefikStatusDisabled = "disabled"
+	TraefikStatusWarning  = "warning"
+)
+
+// InstanceStatus is the state of a resource as reported by one Traefik instance
+type InstanceStatus struct {
+	Instance string `json:"instance"`
+	// Found is false if the instance doesn't know the resource, e.g. because it hasn't polled since it was created
+	Found bool `json:"found"`
+	// Status is Traefik's runtime status: enabled, disabled or warning
+	Status string `json:"status,omitempty"`
+	// Errors are the errors Traefik reported for the resource
+	Errors []string `json:"errors,omitempty"`
+	// Rejected is true if Traefik disabled the resource
+	Rejected bool `json:"rejected"`
+	// FetchError is set if the instance's API could not be read on the last attempt
+	FetchError string `json:"fetchError,omitempty"`
+	// CheckedAt is the time of the last successful fetch from the instance
+	CheckedAt time.Time `json:"checkedAt,omitempty"`
+}
+
+// ResourceStatus is the state of a resource across all reconciled Traefik instances
+type ResourceStatus struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// TraefikName is the name of the resource in Traefik, including the provider suffix
+	TraefikName string `json:"traefikName"`
+	// Rejected is true if any instance rejected the resource
+	Rejected  bool             `json:"rejected"`
+	Instances []InstanceStatus `json:"instances"`
+}
+
+// ReconcileInstance is the fetch state of a reconciled Traefik instance
+type ReconcileInstance struct {
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	CheckedAt time.Time `json:"checkedAt,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// ReconcileReport lists the reconciled instances and the resources rejected by any of them
+type ReconcileReport struct {
+	Instances []ReconcileInstance `json:"instances"`
+	Rejected  []ResourceStatus    `json:"rejected"`
+}
@@ -0,0 +1,280 @@
+package models
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Reference is a reference of a resource to a service or middleware
+type Reference struct {
+	ResourceType string // "service" or "middleware"
+	ID           string
+	Field        string // the field holding the reference, e.g. "weighted.services"
+}
+
+// References returns the service and middlewares referenced by the router
+func (r Router) References() []Reference {
+	refs := []Reference{}
+	if r.Service.ID != "" {
+		refs = append(refs, Reference{ResourceType: "service", ID: r.Service.ID, Field: "service"})
+	}
+	for _, mw := range r.Middlewares {
+		refs = append(refs, Reference{ResourceType: "middleware", ID: mw.ID, Field: "middlewares"})
+	}
+	return refs
+}
+
+// References returns the services referenced by the service
+func (s Service) References() []Reference {
+	refs := []Reference{}
+	add := func(field string, ref Service) {
+		if ref.ID != "" {
+			refs = append(refs, Reference{ResourceType: "service", ID: ref.ID, Field: field})
+		}
+	}
+
+	if s.Weighted != nil {
+		for _, item := range s.Weighted.Services {
+			add("weighted.services", item.Name)
+		}
+	}
+	if s.Mirroring != nil {
+		add("mirroring.service", s.Mirroring.Service)
+		for _, mirror := range s.Mirroring.Mirrors {
+			add("mirroring.mirrors", mirror.Name)
+		}
+	}
+	if s.Failover != nil {
+		add("failover.service", s.Failover.Service)
+		add("failover.fallback", s.Failover.Fallback)
+	}
+	return refs
+}
+
+// References returns the middlewares referenced by a chain middleware
+// and the service referenced by an errors middleware
+func (m Middleware) References() []Reference {
+	refs := []Reference{}
+	configMap, ok := m.Config.(map[string]interface{})
+	if !ok {
+		return refs
+	}
+
+	switch m.Type {
+	case "chain":
+		items, _ := configMap["middlewares"].([]interface{})
+		for _, item := range items {
+			if id := refID(item); id != "" {
+				refs = append(refs, Reference{ResourceType: "middleware", ID: id, Field: "config.middlewares"})
+			}
+		}
+	case "errors":
+		if id := refID(configMap["service"]); id != "" {
+			refs = append(refs, Reference{ResourceType: "service", ID: id, Field: "config.service"})
+		}
+	}
+	return refs
+}
+
+// Sort sorts the nodes of the graph by type and ID, and its edges by their nodes and field
+func (g *Graph) Sort() {
+	less := func(a, b ResourceRef) int {
+		return cmp.Or(cmp.Compare(a.ResourceType, b.ResourceType), cmp.Compare(a.ID, b.ID))
+	}
+	slices.SortFunc(g.Nodes, less)
+	slices.SortFunc(g.Edges, func(a, b GraphEdge) int {
+		return cmp.Or(less(a.From, b.From), less(a.To, b.To), cmp.Compare(a.Field, b.Field))
+	})
+}
+
+// OrderServices sorts services so that referenced services come before the services referencing them
+func OrderServices(services []Service) []Service {
+	byID := make(map[string]Service, len(services))
+	for _, svc := range services {
+		byID[svc.ID] = svc
+	}
+
+	ordered := make([]Service, 0, len(services))
+	visited := make(map[string]bool, len(services))
+
+	var visit func(id string)
+	visit = func(id string) {
+		svc, ok := byID[id]
+		if !ok || visited[id] {
+			return
+		}
+		visited[id] = true
+		for _, ref := range svc.ServiceRefs() {
+			visit(ref)
+		}
+		ordered = append(ordered, svc)
+	}
+
+	for _, svc := range services {
+		visit(svc.ID)
+	}
+	return ordered
+}
+
+// OrderMiddlewares sorts middlewares so that chained middlewares come before the chains using them
+func OrderMiddlewares(middlewares []Middleware) []Middleware {
+	byID := make(map[string]Middleware, len(middlewares))
+	for _, mw := range middlewares {
+		byID[mw.ID] = mw
+	}
+
+	ordered := make([]Middleware, 0, len(middlewares))
+	visited := make(map[string]bool, len(middlewares))
+
+	var visit func(id string)
+	visit = func(id string) {
+		mw, ok := byID[id]
+		if !ok || visited[id] {
+			return
+		}
+		visited[id] = true
+		for _, ref := range mw.MiddlewareRefs() {
+			visit(ref)
+		}
+		ordered = append(ordered, mw)
+	}
+
+	for _, mw := range middlewares {
+		visit(mw.ID)
+	}
+	return ordered
+}
+
+// ServiceRefs returns the IDs of all services referenced by this service
+// (weighted children, mirroring main service and mirrors, failover service and fallback)
+func (s Service) ServiceRefs() []string {
+	return refIDs(s.References(), "service")
+}
+
+// MiddlewareRefs returns the IDs of all middlewares referenced by a chain middleware
+func (m Middleware) MiddlewareRefs() []string {
+	return refIDs(m.References(), "middleware")
+}
+
+// ServiceRefs returns the IDs of all services referenced by an errors middleware
+func (m Middleware) ServiceRefs() []string {
+	return refIDs(m.References(), "service")
+}
+
+// refIDs returns the IDs of the references to resources of the given type
+func refIDs(refs []Reference, resourceType string) []string {
+	ids := []string{}
+	for _, ref := range refs {
+		if ref.ResourceType == resourceType {
+			ids = append(ids, ref.ID)
+		}
+	}
+	return ids
+}
+
+// refID extracts a resource ID from a reference that is either a plain string or an object with an "id" field
+func refID(value interface{}) string {
+	if id, ok := value.(string); ok {
+		return id
+	}
+	if refMap, ok := value.(map[string]interface{}); ok {
+		if id, ok := refMap["id"].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// RewriteRefs replaces the service and middleware references of the router.
+// The middlewares slice is copied so the original router is left untouched.
+func (r *Router) RewriteRefs(serviceFn, middlewareFn func(id string) string) {
+	if r.Service.ID != "" {
+		r.Service.ID = serviceFn(r.Service.ID)
+	}
+
+	if r.Middlewares != nil {
+		middlewares := make([]Middleware, len(r.Middlewares))
+		for i, mw := range r.Middlewares {
+			middlewares[i] = Middleware{ID: middlewareFn(mw.ID)}
+		}
+		r.Middlewares = middlewares
+	}
+}
+
+// RewriteServiceRefs replaces the nested service references of the service.
+// Nested configurations are copied so the original service is left untouched.
+func (s *Service) RewriteServiceRefs(fn func(id string) string) {
+	rewrite := func(ref Service) Service {
+		if ref.ID == "" {
+			return ref
+		}
+		return Service{ID: fn(ref.ID)}
+	}
+
+	if s.Weighted != nil {
+		weighted := *s.Weighted
+		weighted.Services = make([]WeightedServiceItem, len(s.Weighted.Services))
+		for i, item := range s.Weighted.Services {
+			weighted.Services[i] = WeightedServiceItem{Name: rewrite(item.Name), Weight: item.Weight}
+		}
+		s.Weighted = &weighted
+	}
+
+	if s.Mirroring != nil {
+		mirroring := *s.Mirroring
+		mirroring.Service = rewrite(s.Mirroring.Service)
+		mirroring.Mirrors = make([]MirrorServiceItem, len(s.Mirroring.Mirrors))
+		for i, mirror := range s.Mirroring.Mirrors {
+			mirroring.Mirrors[i] = MirrorServiceItem{Name: rewrite(mirror.Name), Percent: mirror.Percent}
+		}
+		s.Mirroring = &mirroring
+	}
+
+	if s.Failover != nil {
+		failover := *s.Failover
+		failover.Service = rewrite(s.Failover.Service)
+		failover.Fallback = rewrite(s.Failover.Fallback)
+		s.Failover = &failover
+	}
+}
+
+// RewriteRefs replaces the middleware references of a chain middleware and the
+// service reference of an errors middleware. The configuration map is copied
+// so the original middleware is left untouched.
+func (m *Middleware) RewriteRefs(middlewareFn, serviceFn func(id string) string) {
+	if m.Type != "chain" && m.Type != "errors" {
+		return
+	}
+
+	configMap, ok := m.Config.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	config := make(map[string]interface{}, len(configMap))
+	for key, value := range configMap {
+		config[key] = value
+	}
+
+	if m.Type == "chain" {
+		if items, ok := configMap["middlewares"].([]interface{}); ok {
+			rewritten := make([]interface{}, len(items))
+			for i, item := range items {
+				if id := refID(item); id != "" {
+					rewritten[i] = middlewareFn(id)
+				} else {
+					rewritten[i] = item
+				}
+			}
+			config["middlewares"] = rewritten
+		}
+	}
+
+	if m.Type == "errors" {
+		if id := refID(configMap["service"]); id != "" {
+			config["service"] = serviceFn(id)
+		}
+	}
+
+	m.Config = config
+}
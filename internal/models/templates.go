@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Types of template parameters
+const (
+	ParameterString   = "string"
+	ParameterInteger  = "integer"
+	ParameterNumber   = "number"
+	ParameterBoolean  = "boolean"
+	ParameterURL      = "url"
+	ParameterHostname = "hostname"
+)
+
+// Template is a parameterized bundle of routers, services and middlewares, instantiated as an app.
+// String values of its components may contain ${name} placeholders of its parameters.
+// Components take the same form as in POST /apps.
+type Template struct {
+	ID          string                   `json:"id"`
+	Description string                   `json:"description,omitempty"`
+	Parameters  []TemplateParameter      `json:"parameters"`
+	Routers     []map[string]interface{} `json:"routers"`
+	Services    []map[string]interface{} `json:"services,omitempty"`
+	Middlewares []map[string]interface{} `json:"middlewares,omitempty"`
+	CreatedAt   time.Time                `json:"createdAt"`
+	UpdatedAt   time.Time                `json:"updatedAt"`
+}
+
+// TemplateParameter describes a typed parameter of a template
+type TemplateParameter struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	// Default is used when no value is given. Parameters without default are required.
+	Default interface{} `json:"default,omitempty"`
+	// Pattern is a regular expression that string, url and hostname values must match
+	Pattern string `json:"pattern,omitempty"`
+	// Minimum and Maximum bound integer and number values
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+}
+
+// TemplateInstantiation is the body of a template instantiation request
+type TemplateInstantiation struct {
+	// Name of the created app, prefixing the IDs of its components. Generated if empty.
+	Name string `json:"name,omitempty"`
+	// Environment the app is created in, the default environment if empty
+	Environment string                 `json:"environment,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
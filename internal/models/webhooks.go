@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// States of a webhook delivery
+const (
+	// DeliveryPending is waiting for its first or next attempt
+	DeliveryPending = "pending"
+	// DeliverySucceeded was acknowledged with a 2xx response
+	DeliverySucceeded = "succeeded"
+	// DeliveryDead failed all attempts and was moved to the dead-letter list
+	DeliveryDead = "dead"
+)
+
+// Webhook is an URL notified of the configuration changes matching its filters
+type Webhook struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Events are patterns of the delivered events, e.g. "router.*" or "*.deleted". Empty matches all.
+	Events []string `json:"events,omitempty"`
+	// Environment limits deliveries to one environment, empty matches all
+	Environment string `json:"environment,omitempty"`
+	// Namespace limits deliveries to resources in one namespace, empty matches all
+	Namespace string `json:"namespace,omitempty"`
+	// Description is a free-form note, e.g. the channel the webhook posts to
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// WebhookCreated is returned when a webhook is created and contains its signing secret
+type WebhookCreated struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookPayload is the JSON body posted to a webhook
+type WebhookPayload struct {
+	DeliveryID  string      `json:"deliveryId"`
+	WebhookID   string      `json:"webhookId"`
+	Event       string      `json:"event"`
+	Environment string      `json:"environment"`
+	Change      ChangeEvent `json:"change"`
+	// Text summarizes the change, which lets chat webhooks such as Slack's display it as is
+	Text string `json:"text"`
+}
+
+// WebhookDelivery is the state of a payload sent to a webhook
+type WebhookDelivery struct {
+	ID          string      `json:"id"`
+	WebhookID   string      `json:"webhookId"`
+	Event       string      `json:"event"`
+	Environment string      `json:"environment"`
+	Change      ChangeEvent `json:"change"`
+	Status      string      `json:"status"`
+	Attempts    int         `json:"attempts"`
+	// StatusCode is the response status of the last attempt, 0 if no response was received
+	StatusCode  int        `json:"statusCode,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	LastAttempt *time.Time `json:"lastAttempt,omitempty"`
+	NextAttempt *time.Time `json:"nextAttempt,omitempty"`
+}
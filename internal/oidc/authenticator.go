@@ -0,0 +1,125 @@
+package oidc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sistemica/traefik-manager/internal/config"
+	"github.com/sistemica/traefik-manager/internal/middleware"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// rolePriority orders roles from least to most privileged
+var rolePriority = map[string]int{
+	models.RoleProviderOnly: 1,
+	models.RoleReader:       2,
+	models.RoleEditor:       3,
+	models.RoleAdmin:        4,
+}
+
+// Authenticator maps verified bearer tokens to identities
+type Authenticator struct {
+	Verifier *Verifier
+	Config   config.OIDC
+}
+
+// NewAuthenticator creates an Authenticator from the OIDC configuration
+func NewAuthenticator(cfg config.OIDC) (*Authenticator, error) {
+	for value, role := range cfg.RoleMapping {
+		if !models.ValidRole(role) {
+			return nil, fmt.Errorf("invalid role %q mapped from %q", role, value)
+		}
+	}
+	if cfg.DefaultRole != "" && !models.ValidRole(cfg.DefaultRole) {
+		return nil, fmt.Errorf("invalid default role %q", cfg.DefaultRole)
+	}
+
+	keys, err := NewKeySet(cfg.JWKS, cfg.JWKSRefreshInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Authenticator{
+		Verifier: NewVerifier(cfg.Issuer, cfg.Audience, keys),
+		Config:   cfg,
+	}, nil
+}
+
+// AuthenticateBearer verifies a bearer token and maps its claims to an identity
+func (a *Authenticator) AuthenticateBearer(token string) (*middleware.Identity, error) {
+	claims, err := a.Verifier.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	name, _ := claims.lookup(a.Config.NameClaim).(string)
+	if name == "" {
+		return nil, fmt.Errorf("%w: missing %s claim", ErrInvalidClaims, a.Config.NameClaim)
+	}
+
+	role := a.role(claims)
+	if role == "" {
+		return nil, fmt.Errorf("%w: token grants no role", ErrInvalidClaims)
+	}
+
+	namespace, _ := claims.lookup(a.Config.NamespaceClaim).(string)
+	if strings.Contains(namespace, "/") {
+		return nil, fmt.Errorf("%w: invalid namespace %q", ErrInvalidClaims, namespace)
+	}
+
+	return &middleware.Identity{
+		Name:      "oidc:" + name,
+		Namespace: namespace,
+		Role:      role,
+	}, nil
+}
+
+// role returns the most privileged role granted by the role claim. With a role mapping only
+// mapped values grant roles, without one values that are role names map to themselves.
+func (a *Authenticator) role(claims Claims) string {
+	var values []string
+	switch value := claims.lookup(a.Config.RoleClaim).(type) {
+	case string:
+		values = strings.Fields(value)
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	best := ""
+	for _, value := range values {
+		role, ok := a.Config.RoleMapping[value]
+		if !ok && len(a.Config.RoleMapping) == 0 {
+			role = value
+		}
+		if rolePriority[role] > rolePriority[best] {
+			best = role
+		}
+	}
+
+	if best == "" {
+		return a.Config.DefaultRole
+	}
+	return best
+}
+
+// lookup returns a claim by name, dots select nested claims (e.g. "realm_access.roles")
+// unless a claim with the full name exists
+func (c Claims) lookup(name string) interface{} {
+	if value, ok := c[name]; ok {
+		return value
+	}
+
+	var current interface{} = map[string]interface{}(c)
+	for _, part := range strings.Split(name, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[part]
+	}
+	return current
+}
@@ -0,0 +1,218 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/config"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// testKey is a signing key together with its key ID
+type testKey struct {
+	kid string
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newRSAKey(t *testing.T, kid string) testKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	return testKey{kid: kid, rsa: key}
+}
+
+func newECKey(t *testing.T, kid string) testKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate EC key: %v", err)
+	}
+	return testKey{kid: kid, ec: key}
+}
+
+// jwk returns the public JWK of the key
+func (k testKey) jwk() map[string]string {
+	encode := func(i *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(i.Bytes())
+	}
+	if k.rsa != nil {
+		return map[string]string{
+			"kty": "RSA",
+			"kid": k.kid,
+			"use": "sig",
+			"n":   encode(k.rsa.N),
+			"e":   encode(big.NewInt(int64(k.rsa.E))),
+		}
+	}
+	return map[string]string{
+		"kty": "EC",
+		"kid": k.kid,
+		"crv": "P-256",
+		"x":   encode(k.ec.X),
+		"y":   encode(k.ec.Y),
+	}
+}
+
+// sign creates a signed JWT with the given claims
+func (k testKey) sign(t *testing.T, claims map[string]interface{}) string {
+	alg := "RS256"
+	if k.ec != nil {
+		alg = "ES256"
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": k.kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	if k.rsa != nil {
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+	} else {
+		r, s, err := ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// writeJWKS writes the public keys to a JWKS file
+func writeJWKS(t *testing.T, path string, keys ...testKey) {
+	jwks := map[string][]map[string]string{"keys": {}}
+	for _, key := range keys {
+		jwks["keys"] = append(jwks["keys"], key.jwk())
+	}
+	data, _ := json.Marshal(jwks)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Failed to write JWKS: %v", err)
+	}
+}
+
+func TestAuthenticator(t *testing.T) {
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	rsaKey := newRSAKey(t, "rsa-1")
+	ecKey := newECKey(t, "ec-1")
+	writeJWKS(t, jwksPath, rsaKey, ecKey)
+
+	authenticator, err := NewAuthenticator(config.OIDC{
+		Issuer:              "https://issuer.example.com",
+		Audience:            "traefik-manager",
+		JWKS:                jwksPath,
+		JWKSRefreshInterval: time.Hour,
+		NameClaim:           "sub",
+		RoleClaim:           "realm_access.roles",
+		NamespaceClaim:      "namespace",
+		RoleMapping:         map[string]string{"platform-admins": models.RoleAdmin, "developers": models.RoleEditor},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create authenticator: %v", err)
+	}
+
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss":          "https://issuer.example.com",
+			"aud":          []string{"other", "traefik-manager"},
+			"sub":          "alice",
+			"exp":          time.Now().Add(time.Hour).Unix(),
+			"realm_access": map[string]interface{}{"roles": []string{"reader", "platform-admins"}},
+		}
+		for k, v := range overrides {
+			c[k] = v
+		}
+		return c
+	}
+
+	t.Run("Valid RSA Token", func(t *testing.T) {
+		identity, err := authenticator.AuthenticateBearer(rsaKey.sign(t, claims(nil)))
+		if err != nil {
+			t.Fatalf("Expected token to be valid: %v", err)
+		}
+		if identity.Name != "oidc:alice" || identity.Role != models.RoleAdmin || identity.Namespace != "" {
+			t.Fatalf("Unexpected identity: %+v", identity)
+		}
+	})
+
+	t.Run("Valid EC Token With Namespace", func(t *testing.T) {
+		token := ecKey.sign(t, claims(map[string]interface{}{
+			"namespace":    "team-a",
+			"realm_access": map[string]interface{}{"roles": []string{"developers"}},
+		}))
+		identity, err := authenticator.AuthenticateBearer(token)
+		if err != nil {
+			t.Fatalf("Expected token to be valid: %v", err)
+		}
+		if identity.Role != models.RoleEditor || identity.Namespace != "team-a" {
+			t.Fatalf("Unexpected identity: %+v", identity)
+		}
+	})
+
+	t.Run("Invalid Tokens", func(t *testing.T) {
+		unknownKey := newRSAKey(t, "unknown")
+		tokens := map[string]string{
+			"Expired":         rsaKey.sign(t, claims(map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()})),
+			"Wrong Issuer":    rsaKey.sign(t, claims(map[string]interface{}{"iss": "https://evil.example.com"})),
+			"Wrong Audience":  rsaKey.sign(t, claims(map[string]interface{}{"aud": "other"})),
+			"No Role":         rsaKey.sign(t, claims(map[string]interface{}{"realm_access": map[string]interface{}{}})),
+			"Unmapped Role":   rsaKey.sign(t, claims(map[string]interface{}{"realm_access": map[string]interface{}{"roles": []string{"admin"}}})),
+			"Unknown Key":     unknownKey.sign(t, claims(nil)),
+			"Malformed":       "not-a-token",
+			"Tampered Claims": rsaKey.sign(t, claims(nil))[:10] + "x" + rsaKey.sign(t, claims(nil))[11:],
+		}
+		for name, token := range tokens {
+			if _, err := authenticator.AuthenticateBearer(token); err == nil {
+				t.Errorf("Expected %s token to be rejected", name)
+			}
+		}
+	})
+
+	t.Run("Role Names Without Mapping", func(t *testing.T) {
+		unmapped := *authenticator
+		unmapped.Config.RoleMapping = nil
+
+		identity, err := unmapped.AuthenticateBearer(rsaKey.sign(t, claims(map[string]interface{}{
+			"realm_access": map[string]interface{}{"roles": []string{"editor", "developers"}},
+		})))
+		if err != nil {
+			t.Fatalf("Expected token to be valid: %v", err)
+		}
+		if identity.Role != models.RoleEditor {
+			t.Fatalf("Expected the role name to map to itself, got %+v", identity)
+		}
+	})
+
+	t.Run("Key Rotation", func(t *testing.T) {
+		rotatedKey := newECKey(t, "ec-2")
+		writeJWKS(t, jwksPath, rotatedKey)
+
+		// Allow the unknown key ID to trigger a reload
+		authenticator.Verifier.Keys.attemptedAt = time.Time{}
+
+		if _, err := authenticator.AuthenticateBearer(rotatedKey.sign(t, claims(nil))); err != nil {
+			t.Fatalf("Expected token signed with rotated key to be valid: %v", err)
+		}
+		if _, err := authenticator.AuthenticateBearer(rsaKey.sign(t, claims(nil))); err == nil {
+			t.Fatalf("Expected token signed with removed key to be rejected")
+		}
+	})
+}
@@ -0,0 +1,255 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/logger"
+)
+
+// ErrUnknownKey is returned when a token is signed with a key that is not in the key set
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// minRefreshInterval limits reloads triggered by tokens with unknown key IDs
+const minRefreshInterval = 30 * time.Second
+
+// jsonWebKey is a single key of a JWKS document
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// KeySet holds the public keys of a JWKS loaded from a file or URL.
+// Keys are reloaded after the refresh interval and when a token references an unknown key,
+// so rotated keys are picked up without a restart.
+type KeySet struct {
+	mu              sync.RWMutex
+	source          string
+	refreshInterval time.Duration
+	client          *http.Client
+	keys            map[string]crypto.PublicKey
+	loadedAt        time.Time
+	attemptedAt     time.Time
+}
+
+// NewKeySet creates a KeySet for a JWKS file path or http(s) URL.
+// Files must be readable at startup; URLs that can't be fetched yet are retried on first use.
+func NewKeySet(source string, refreshInterval time.Duration) (*KeySet, error) {
+	k := &KeySet{
+		source:          source,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		keys:            make(map[string]crypto.PublicKey),
+	}
+
+	if err := k.refresh(); err != nil {
+		if !k.isURL() {
+			return nil, err
+		}
+		logger.Warn().Err(err).Str("jwks", source).Msg("Failed to fetch JWKS, retrying on first use")
+	}
+
+	return k, nil
+}
+
+// Key returns the public key with the given key ID.
+// An empty key ID is accepted if the set contains a single key.
+func (k *KeySet) Key(kid string) (crypto.PublicKey, error) {
+	k.mu.RLock()
+	stale := time.Since(k.loadedAt) > k.refreshInterval
+	key, ok := k.lookup(kid)
+	canRetry := time.Since(k.attemptedAt) > minRefreshInterval
+	k.mu.RUnlock()
+
+	if ok && !stale {
+		return key, nil
+	}
+
+	if stale || canRetry {
+		if err := k.refresh(); err != nil {
+			// Keep serving the previously loaded keys
+			logger.Warn().Err(err).Str("jwks", k.source).Msg("Failed to reload JWKS")
+		}
+
+		k.mu.RLock()
+		key, ok = k.lookup(kid)
+		k.mu.RUnlock()
+	}
+
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// lookup finds a key by ID, must be called with the lock held
+func (k *KeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+// refresh reloads the keys from the source
+func (k *KeySet) refresh() error {
+	k.mu.Lock()
+	k.attemptedAt = time.Now()
+	k.mu.Unlock()
+
+	data, err := k.read()
+	if err != nil {
+		return err
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.loadedAt = time.Now()
+	k.mu.Unlock()
+
+	logger.Debug().Str("jwks", k.source).Int("keys", len(keys)).Msg("JWKS loaded")
+	return nil
+}
+
+// read returns the raw JWKS document
+func (k *KeySet) read() ([]byte, error) {
+	if !k.isURL() {
+		data, err := os.ReadFile(k.source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+		return data, nil
+	}
+
+	resp, err := k.client.Get(k.source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS response: %w", err)
+	}
+	return data, nil
+}
+
+// isURL returns true if the source is an http(s) URL
+func (k *KeySet) isURL() bool {
+	return strings.HasPrefix(k.source, "http://") || strings.HasPrefix(k.source, "https://")
+}
+
+// parseJWKS parses the signing keys of a JWKS document, skipping unsupported keys
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			logger.Warn().Err(err).Str("kid", jwk.Kid).Msg("Skipping JWKS key")
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+// publicKey decodes the public key of a JWK
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		if _, err := key.ECDH(); err != nil {
+			return nil, fmt.Errorf("invalid point on curve %s: %w", jwk.Crv, err)
+		}
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %s", jwk.Kty)
+}
+
+// decodeBigInt decodes a base64url encoded big-endian integer
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty value")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,201 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Errors returned when validating tokens
+var (
+	// ErrMalformedToken is returned when a token is not a well-formed JWT
+	ErrMalformedToken = errors.New("malformed token")
+
+	// ErrInvalidSignature is returned when the token signature doesn't verify
+	ErrInvalidSignature = errors.New("invalid token signature")
+
+	// ErrInvalidClaims is returned when the issuer, audience or validity of a token doesn't match
+	ErrInvalidClaims = errors.New("invalid token claims")
+)
+
+// clockSkew is the tolerance applied to the expiry and not-before times
+const clockSkew = time.Minute
+
+// Claims are the decoded claims of a verified token
+type Claims map[string]interface{}
+
+// Verifier validates JWTs signed with keys of a JWKS
+type Verifier struct {
+	Issuer   string
+	Audience string
+	Keys     *KeySet
+
+	// now returns the current time, replaced in tests
+	now func() time.Time
+}
+
+// NewVerifier creates a new Verifier for the given issuer and audience
+func NewVerifier(issuer, audience string, keys *KeySet) *Verifier {
+	return &Verifier{
+		Issuer:   issuer,
+		Audience: audience,
+		Keys:     keys,
+		now:      time.Now,
+	}
+}
+
+// Verify checks the signature, issuer, audience and validity period of a token
+// and returns its claims
+func (v *Verifier) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	key, err := v.Keys.Key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// validateClaims checks the registered claims of a token
+func (v *Verifier) validateClaims(claims Claims) error {
+	if iss, _ := claims["iss"].(string); iss != v.Issuer {
+		return fmt.Errorf("%w: unexpected issuer %q", ErrInvalidClaims, iss)
+	}
+
+	if !containsAudience(claims["aud"], v.Audience) {
+		return fmt.Errorf("%w: audience does not match", ErrInvalidClaims)
+	}
+
+	now := v.now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("%w: missing expiry", ErrInvalidClaims)
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(clockSkew)) {
+		return fmt.Errorf("%w: token expired", ErrInvalidClaims)
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return fmt.Errorf("%w: token not yet valid", ErrInvalidClaims)
+	}
+
+	return nil
+}
+
+// containsAudience returns true if the aud claim, a string or list of strings, contains the audience
+func containsAudience(aud interface{}, audience string) bool {
+	switch value := aud.(type) {
+	case string:
+		return value == audience
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifySignature verifies a JWS signature for the supported asymmetric algorithms
+func verifySignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidSignature, alg)
+	}
+
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS", "PS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: key type does not match algorithm", ErrInvalidSignature)
+		}
+		var err error
+		if alg[0] == 'R' {
+			err = rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature)
+		} else {
+			err = rsa.VerifyPSS(rsaKey, hash, digest, signature, nil)
+		}
+		if err != nil {
+			return ErrInvalidSignature
+		}
+
+	case "ES":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: key type does not match algorithm", ErrInvalidSignature)
+		}
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return ErrInvalidSignature
+		}
+	}
+
+	return nil
+}
+
+// decodeSegment decodes a base64url encoded JSON token segment
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformedToken
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrMalformedToken
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Package pollers keeps track of the Traefik instances polling the provider endpoints.
+package pollers
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// maxClients bounds the number of tracked clients, the least recently seen are evicted first
+const maxClients = 1000
+
+// Poll describes a single provider configuration fetch
+type Poll struct {
+	Endpoint   string
+	Instance   string
+	RemoteAddr string
+	UserAgent  string
+	ConfigHash string
+}
+
+// Tracker records provider polls in memory
+type Tracker struct {
+	mu         sync.Mutex
+	staleAfter time.Duration
+	clients    map[string]*models.ProviderClient
+	// latest holds the hash most recently served per endpoint
+	latest map[string]string
+	now    func() time.Time
+}
+
+// NewTracker creates a Tracker that reports clients as stale after staleAfter without a poll
+func NewTracker(staleAfter time.Duration) *Tracker {
+	return &Tracker{
+		staleAfter: staleAfter,
+		clients:    make(map[string]*models.ProviderClient),
+		latest:     make(map[string]string),
+		now:        time.Now,
+	}
+}
+
+// Record records a poll. Clients are identified by their instance header,
+// falling back to their remote address.
+func (t *Tracker) Record(poll Poll) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	identity := poll.Instance
+	if identity == "" {
+		identity = poll.RemoteAddr
+	}
+	key := poll.Endpoint + "\x00" + identity
+
+	client, ok := t.clients[key]
+	if !ok {
+		if len(t.clients) >= maxClients {
+			t.evictOldest()
+		}
+		client = &models.ProviderClient{
+			Endpoint:  poll.Endpoint,
+			Instance:  poll.Instance,
+			FirstPoll: now,
+		}
+		t.clients[key] = client
+	}
+
+	client.RemoteAddr = poll.RemoteAddr
+	client.UserAgent = poll.UserAgent
+	client.LastPoll = now
+	client.Polls++
+	client.ConfigHash = poll.ConfigHash
+
+	t.latest[poll.Endpoint] = poll.ConfigHash
+}
+
+// List returns all clients ordered by endpoint and instance or remote address,
+// with their staleness computed at the time of the call
+func (t *Tracker) List() []models.ProviderClient {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	clients := make([]models.ProviderClient, 0, len(t.clients))
+	for _, client := range t.clients {
+		entry := *client
+		since := now.Sub(entry.LastPoll)
+		entry.SecondsSinceLastPoll = since.Seconds()
+		entry.Stale = t.staleAfter > 0 && since > t.staleAfter
+		entry.UpToDate = entry.ConfigHash == t.latest[entry.Endpoint]
+		clients = append(clients, entry)
+	}
+
+	sort.Slice(clients, func(i, j int) bool {
+		if clients[i].Endpoint != clients[j].Endpoint {
+			return clients[i].Endpoint < clients[j].Endpoint
+		}
+		if clients[i].Instance != clients[j].Instance {
+			return clients[i].Instance < clients[j].Instance
+		}
+		return clients[i].RemoteAddr < clients[j].RemoteAddr
+	})
+	return clients
+}
+
+// evictOldest removes the least recently seen client, must be called with the lock held
+func (t *Tracker) evictOldest() {
+	var oldestKey string
+	var oldest time.Time
+	for key, client := range t.clients {
+		if oldestKey == "" || client.LastPoll.Before(oldest) {
+			oldestKey, oldest = key, client.LastPoll
+		}
+	}
+	delete(t.clients, oldestKey)
+}
@@ -0,0 +1,55 @@
+package pollers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(time.Minute)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Record(Poll{Endpoint: "/traefik/provider", Instance: "edge-1", RemoteAddr: "10.0.0.1", UserAgent: "Go-http-client/1.1", ConfigHash: "a"})
+	tracker.Record(Poll{Endpoint: "/traefik/provider", RemoteAddr: "10.0.0.2", ConfigHash: "a"})
+
+	now = now.Add(2 * time.Minute)
+	tracker.Record(Poll{Endpoint: "/traefik/provider", Instance: "edge-1", RemoteAddr: "10.0.0.3", ConfigHash: "b"})
+
+	t.Run("List", func(t *testing.T) {
+		clients := tracker.List()
+		if len(clients) != 2 {
+			t.Fatalf("Expected 2 clients, got %d", len(clients))
+		}
+
+		// Clients without an instance header sort first
+		byIP, edge := clients[0], clients[1]
+
+		if edge.Instance != "edge-1" || edge.Polls != 2 || edge.RemoteAddr != "10.0.0.3" {
+			t.Fatalf("Unexpected instance client: %+v", edge)
+		}
+		if edge.Stale || !edge.UpToDate {
+			t.Fatalf("Expected edge-1 to be current, got stale=%v upToDate=%v", edge.Stale, edge.UpToDate)
+		}
+
+		if byIP.RemoteAddr != "10.0.0.2" || byIP.Polls != 1 {
+			t.Fatalf("Unexpected remote address client: %+v", byIP)
+		}
+		if !byIP.Stale || byIP.UpToDate {
+			t.Fatalf("Expected 10.0.0.2 to be stale and outdated, got stale=%v upToDate=%v", byIP.Stale, byIP.UpToDate)
+		}
+		if byIP.SecondsSinceLastPoll != 120 {
+			t.Fatalf("Expected 120 seconds since last poll, got %v", byIP.SecondsSinceLastPoll)
+		}
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		tracker := NewTracker(time.Minute)
+		for i := 0; i < maxClients+1; i++ {
+			tracker.Record(Poll{Endpoint: "/traefik/provider", Instance: time.Duration(i).String(), ConfigHash: "a"})
+		}
+		if n := len(tracker.List()); n != maxClients {
+			t.Fatalf("Expected %d clients, got %d", maxClients, n)
+		}
+	})
+}
@@ -0,0 +1,336 @@
+// Package prober actively checks the backend servers of load balancer services
+// using the services' health check settings.
+package prober
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// Defaults matching Traefik's health check defaults
+const (
+	defaultInterval = 30 * time.Second
+	defaultTimeout  = 5 * time.Second
+)
+
+// Options configures the prober
+type Options struct {
+	// HistorySize is the number of checks kept per server
+	HistorySize int
+	// SyncInterval is the interval at which services are read from the store and due checks are started
+	SyncInterval time.Duration
+}
+
+// target is a backend server of a service together with its check settings
+type target struct {
+	serviceID   string
+	serverURL   string
+	healthCheck models.HealthCheck
+}
+
+// serverState holds the check results of a backend server
+type serverState struct {
+	target
+	inFlight   bool
+	lastCheck  time.Time
+	lastChange time.Time
+	history    []models.HealthProbe
+}
+
+// Prober periodically checks the backend servers of the services in a store
+type Prober struct {
+	store store.Store
+	opts  Options
+
+	mu sync.Mutex
+	// servers holds the state per service ID and server URL
+	servers map[string]map[string]*serverState
+	now     func() time.Time
+}
+
+// NewProber creates a Prober for the services of the store
+func NewProber(s store.Store, opts Options) *Prober {
+	if opts.HistorySize < 1 {
+		opts.HistorySize = 1
+	}
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = time.Second
+	}
+	return &Prober{
+		store:   s,
+		opts:    opts,
+		servers: make(map[string]map[string]*serverState),
+		now:     time.Now,
+	}
+}
+
+// Run syncs the services and starts due checks every sync interval until done is closed
+func (p *Prober) Run(done <-chan struct{}) {
+	ticker := time.NewTicker(p.opts.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Sync(); err != nil {
+			logger.Error().Err(err).Msg("Failed to sync health probe targets")
+		}
+		for _, state := range p.due() {
+			go p.check(state)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+	}
+}
+
+// Sync reads the services from the store, adding new servers and dropping removed ones
+func (p *Prober) Sync() error {
+	services, err := p.store.ListServices()
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+
+	targets := make(map[string]map[string]target)
+	for _, service := range services {
+		lb := service.LoadBalancer
+		if lb == nil || lb.HealthCheck == nil || lb.HealthCheck.Mode == "grpc" {
+			continue
+		}
+		targets[service.ID] = make(map[string]target, len(lb.Servers))
+		for _, server := range lb.Servers {
+			targets[service.ID][server.URL] = target{
+				serviceID:   service.ID,
+				serverURL:   server.URL,
+				healthCheck: *lb.HealthCheck,
+			}
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for serviceID, servers := range p.servers {
+		for serverURL := range servers {
+			if _, ok := targets[serviceID][serverURL]; !ok {
+				delete(servers, serverURL)
+			}
+		}
+		if _, ok := targets[serviceID]; !ok {
+			delete(p.servers, serviceID)
+		}
+	}
+
+	for serviceID, servers := range targets {
+		if p.servers[serviceID] == nil {
+			p.servers[serviceID] = make(map[string]*serverState, len(servers))
+		}
+		for serverURL, t := range servers {
+			if state, ok := p.servers[serviceID][serverURL]; ok {
+				// Pick up changed check settings
+				state.target = t
+				continue
+			}
+			p.servers[serviceID][serverURL] = &serverState{target: t}
+		}
+	}
+	return nil
+}
+
+// Health returns the health of the servers of a service.
+// The service is reported as not probed if it has no load balancer health check.
+func (p *Prober) Health(serviceID string) models.ServiceHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	health := models.ServiceHealth{
+		ServiceID: serviceID,
+		Servers:   []models.ServerHealth{},
+	}
+
+	servers, ok := p.servers[serviceID]
+	if !ok {
+		return health
+	}
+	health.Probed = true
+
+	for serverURL, state := range servers {
+		server := models.ServerHealth{
+			URL:        serverURL,
+			State:      models.ServerUnknown,
+			LastCheck:  state.lastCheck,
+			LastChange: state.lastChange,
+			History:    append([]models.HealthProbe{}, state.history...),
+		}
+		if n := len(state.history); n > 0 {
+			server.State = models.ServerDown
+			if state.history[n-1].Up {
+				server.State = models.ServerUp
+			}
+		}
+		health.Servers = append(health.Servers, server)
+	}
+
+	sort.Slice(health.Servers, func(i, j int) bool {
+		return health.Servers[i].URL < health.Servers[j].URL
+	})
+	return health
+}
+
+// due returns the servers whose next check is due and marks them as in flight
+func (p *Prober) due() []*serverState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var due []*serverState
+	for _, servers := range p.servers {
+		for _, state := range servers {
+			if state.inFlight {
+				continue
+			}
+			interval := parseDuration(state.healthCheck.Interval, defaultInterval)
+			if !state.lastCheck.IsZero() && now.Sub(state.lastCheck) < interval {
+				continue
+			}
+			state.inFlight = true
+			due = append(due, state)
+		}
+	}
+	return due
+}
+
+// check probes a server and records the result
+func (p *Prober) check(state *serverState) {
+	p.mu.Lock()
+	t := state.target
+	p.mu.Unlock()
+
+	probe := Check(t.serverURL, t.healthCheck)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	state.inFlight = false
+	state.lastCheck = p.now()
+	probe.Time = state.lastCheck
+
+	if n := len(state.history); n == 0 || state.history[n-1].Up != probe.Up {
+		state.lastChange = probe.Time
+		if n > 0 {
+			logger.Info().Str("service", t.serviceID).Str("server", t.serverURL).Bool("up", probe.Up).Str("error", probe.Error).Msg("Backend server health changed")
+		}
+	}
+
+	state.history = append(state.history, probe)
+	if len(state.history) > p.opts.HistorySize {
+		state.history = state.history[len(state.history)-p.opts.HistorySize:]
+	}
+}
+
+// Check performs a single health check of a server with the given settings.
+// Without an expected status, any 2xx or 3xx response is healthy like in Traefik.
+func Check(serverURL string, hc models.HealthCheck) models.HealthProbe {
+	start := time.Now()
+	probe := models.HealthProbe{Time: start}
+
+	checkURL, err := checkURL(serverURL, hc)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+
+	method := hc.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), parseDuration(hc.Timeout, defaultTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, checkURL, nil)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	for name, value := range hc.Headers {
+		req.Header.Set(name, value)
+	}
+	if hc.Hostname != "" {
+		req.Host = hc.Hostname
+	}
+
+	client := &http.Client{}
+	if !hc.FollowRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	resp, err := client.Do(req)
+	probe.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	resp.Body.Close()
+
+	probe.StatusCode = resp.StatusCode
+	if hc.Status != 0 {
+		probe.Up = resp.StatusCode == hc.Status
+	} else {
+		probe.Up = resp.StatusCode >= 200 && resp.StatusCode < 400
+	}
+	if !probe.Up {
+		probe.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+	return probe
+}
+
+// checkURL applies the scheme, port and path of the health check to the server URL
+func checkURL(serverURL string, hc models.HealthCheck) (string, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid server URL %q", serverURL)
+	}
+
+	if hc.Scheme != "" {
+		u.Scheme = hc.Scheme
+	}
+	if hc.Port > 0 {
+		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(hc.Port))
+	}
+	if hc.Path != "" {
+		path, query, _ := strings.Cut(hc.Path, "?")
+		u.Path = path
+		u.RawQuery = query
+	}
+	return u.String(), nil
+}
+
+// parseDuration parses a duration like "10s", treating plain numbers as seconds
+func parseDuration(d models.Duration, defaultValue time.Duration) time.Duration {
+	if d == "" {
+		return defaultValue
+	}
+	if seconds, err := strconv.Atoi(string(d)); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if duration, err := time.ParseDuration(string(d)); err == nil && duration > 0 {
+		return duration
+	}
+	return defaultValue
+}
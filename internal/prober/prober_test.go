@@ -0,0 +1,179 @@
+package prober
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+func TestCheck(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			if r.Method != http.MethodHead || r.Header.Get("X-Probe") != "1" || r.Host != "app.example.com" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case "/redirect":
+			http.Redirect(w, r, "/healthz", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer backend.Close()
+
+	tests := []struct {
+		name        string
+		serverURL   string
+		healthCheck models.HealthCheck
+		up          bool
+	}{
+		{
+			name:      "Configured Method Headers And Hostname",
+			serverURL: backend.URL,
+			healthCheck: models.HealthCheck{
+				Path:     "/healthz",
+				Method:   http.MethodHead,
+				Hostname: "app.example.com",
+				Headers:  map[string]string{"X-Probe": "1"},
+			},
+			up: true,
+		},
+		{
+			name:        "Unhealthy Status",
+			serverURL:   backend.URL,
+			healthCheck: models.HealthCheck{Path: "/down"},
+			up:          false,
+		},
+		{
+			name:        "Expected Status",
+			serverURL:   backend.URL,
+			healthCheck: models.HealthCheck{Path: "/down", Status: http.StatusServiceUnavailable},
+			up:          true,
+		},
+		{
+			name:        "Redirect Not Followed Counts As 3xx",
+			serverURL:   backend.URL,
+			healthCheck: models.HealthCheck{Path: "/redirect"},
+			up:          true,
+		},
+		{
+			name:        "Unreachable Server",
+			serverURL:   "http://127.0.0.1:1",
+			healthCheck: models.HealthCheck{Path: "/healthz", Timeout: "1s"},
+			up:          false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := Check(tt.serverURL, tt.healthCheck)
+			if probe.Up != tt.up {
+				t.Fatalf("Expected up=%v, got %+v", tt.up, probe)
+			}
+			if !probe.Up && probe.Error == "" {
+				t.Fatalf("Expected an error for a failed check")
+			}
+		})
+	}
+}
+
+func TestProber(t *testing.T) {
+	healthy := true
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer backend.Close()
+
+	s, err := store.NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.CreateService(&models.Service{
+		ID: "app",
+		LoadBalancer: &models.LoadBalancerService{
+			Servers:     []models.Server{{URL: backend.URL}},
+			HealthCheck: &models.HealthCheck{Path: "/health", Interval: "10s"},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	if err := s.CreateService(&models.Service{ID: "plain", URL: backend.URL}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := NewProber(s, Options{HistorySize: 2})
+	p.now = func() time.Time { return now }
+
+	// runDue runs the checks that are due synchronously
+	runDue := func() int {
+		if err := p.Sync(); err != nil {
+			t.Fatalf("Failed to sync: %v", err)
+		}
+		due := p.due()
+		for _, state := range due {
+			p.check(state)
+		}
+		return len(due)
+	}
+
+	t.Run("Unknown Before First Check", func(t *testing.T) {
+		if err := p.Sync(); err != nil {
+			t.Fatalf("Failed to sync: %v", err)
+		}
+		health := p.Health("app")
+		if !health.Probed || len(health.Servers) != 1 || health.Servers[0].State != models.ServerUnknown {
+			t.Fatalf("Unexpected health: %+v", health)
+		}
+		if p.Health("plain").Probed {
+			t.Fatalf("Expected service without health check not to be probed")
+		}
+	})
+
+	t.Run("Interval And History", func(t *testing.T) {
+		if n := runDue(); n != 1 {
+			t.Fatalf("Expected 1 due check, got %d", n)
+		}
+		if n := runDue(); n != 0 {
+			t.Fatalf("Expected no due check within the interval, got %d", n)
+		}
+
+		healthy = false
+		now = now.Add(10 * time.Second)
+		runDue()
+		now = now.Add(10 * time.Second)
+		runDue()
+
+		server := p.Health("app").Servers[0]
+		if server.State != models.ServerDown {
+			t.Fatalf("Expected server to be down, got %s", server.State)
+		}
+		if len(server.History) != 2 {
+			t.Fatalf("Expected history to be capped at 2, got %d", len(server.History))
+		}
+		if !server.LastChange.Equal(now.Add(-10 * time.Second)) {
+			t.Fatalf("Expected last change at the first failed check, got %v", server.LastChange)
+		}
+	})
+
+	t.Run("Removed Service", func(t *testing.T) {
+		if err := s.DeleteService("app"); err != nil {
+			t.Fatalf("Failed to delete service: %v", err)
+		}
+		runDue()
+		if p.Health("app").Probed {
+			t.Fatalf("Expected deleted service to be dropped")
+		}
+	})
+}
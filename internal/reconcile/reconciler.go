@@ -0,0 +1,196 @@
+// Package reconcile compares the stored configuration with the runtime state
+// reported by Traefik's rawdata API.
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/config"
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// ProviderSuffix is appended by Traefik to the names of resources from the HTTP provider
+const ProviderSuffix = "@http"
+
+// rawdataPath is the Traefik API endpoint returning the runtime configuration
+const rawdataPath = "/api/rawdata"
+
+// Resource types of the rawdata API
+const (
+	TypeRouters     = "routers"
+	TypeServices    = "services"
+	TypeMiddlewares = "middlewares"
+)
+
+// runtimeInfo holds the fields shared by routers, services and middlewares in the rawdata API
+type runtimeInfo struct {
+	Status string   `json:"status"`
+	Error  []string `json:"error"`
+}
+
+// rawdata is the subset of the rawdata response the reconciler uses
+type rawdata struct {
+	Routers     map[string]runtimeInfo `json:"routers"`
+	Services    map[string]runtimeInfo `json:"services"`
+	Middlewares map[string]runtimeInfo `json:"middlewares"`
+}
+
+// instanceState is the last fetched state of a Traefik instance
+type instanceState struct {
+	config.TraefikInstance
+	data      *rawdata
+	checkedAt time.Time
+	err       error
+}
+
+// Reconciler periodically fetches the rawdata of Traefik instances
+type Reconciler struct {
+	client    *http.Client
+	mu        sync.RWMutex
+	instances []*instanceState
+}
+
+// NewReconciler creates a Reconciler for the given instances, using timeout for each fetch
+func NewReconciler(instances []config.TraefikInstance, timeout time.Duration) *Reconciler {
+	r := &Reconciler{
+		client: &http.Client{Timeout: timeout},
+	}
+	for _, instance := range instances {
+		r.instances = append(r.instances, &instanceState{TraefikInstance: instance})
+	}
+	return r
+}
+
+// Reconcile fetches the rawdata of all instances. Instances that cannot be read
+// keep their last known state together with the error.
+func (r *Reconciler) Reconcile(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, instance := range r.instances {
+		wg.Add(1)
+		go func(instance *instanceState) {
+			defer wg.Done()
+
+			data, err := r.fetch(ctx, instance.URL)
+
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			instance.err = err
+			if err != nil {
+				logger.Warn().Err(err).Str("instance", instance.Name).Msg("Failed to fetch Traefik rawdata")
+				return
+			}
+			instance.data = data
+			instance.checkedAt = time.Now()
+		}(instance)
+	}
+	wg.Wait()
+}
+
+// Run reconciles every interval until done is closed
+func (r *Reconciler) Run(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.Reconcile(context.Background())
+
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+	}
+}
+
+// Status returns the state of a resource on every instance.
+// name is the resource name in Traefik without the provider suffix.
+func (r *Reconciler) Status(resourceType, name string) []models.InstanceStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]models.InstanceStatus, 0, len(r.instances))
+	for _, instance := range r.instances {
+		status := models.InstanceStatus{
+			Instance:  instance.Name,
+			CheckedAt: instance.checkedAt,
+		}
+		if instance.err != nil {
+			status.FetchError = instance.err.Error()
+		}
+
+		if instance.data != nil {
+			if info, ok := instance.data.resources(resourceType)[name+ProviderSuffix]; ok {
+				status.Found = true
+				status.Status = info.Status
+				status.Errors = info.Error
+				status.Rejected = info.Status == models.TraefikStatusDisabled
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Instances returns the fetch state of all instances
+func (r *Reconciler) Instances() []models.ReconcileInstance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]models.ReconcileInstance, 0, len(r.instances))
+	for _, instance := range r.instances {
+		entry := models.ReconcileInstance{
+			Name:      instance.Name,
+			URL:       instance.URL,
+			CheckedAt: instance.checkedAt,
+		}
+		if instance.err != nil {
+			entry.Error = instance.err.Error()
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// fetch reads the rawdata of an instance
+func (r *Reconciler) fetch(ctx context.Context, baseURL string) (*rawdata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+rawdataPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rawdata: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected rawdata status %d", resp.StatusCode)
+	}
+
+	var data rawdata
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode rawdata: %w", err)
+	}
+	return &data, nil
+}
+
+// resources returns the runtime infos of a resource type
+func (d *rawdata) resources(resourceType string) map[string]runtimeInfo {
+	switch resourceType {
+	case TypeRouters:
+		return d.Routers
+	case TypeServices:
+		return d.Services
+	case TypeMiddlewares:
+		return d.Middlewares
+	}
+	return nil
+}
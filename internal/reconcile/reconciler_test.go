@@ -0,0 +1,99 @@
+package reconcile
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/config"
+)
+
+const testRawdata = `{
+	"routers": {
+		"web@http": {"entryPoints": ["web"], "service": "app", "rule": "Host(` + "`example.com`" + `)", "status": "enabled"},
+		"broken@http": {"entryPoints": ["web"], "service": "missing", "rule": "Host(` + "`broken.com`" + `)", "status": "disabled", "error": ["the service \"missing@http\" does not exist"]},
+		"dashboard@internal": {"status": "enabled"}
+	},
+	"services": {
+		"app@http": {"loadBalancer": {"servers": [{"url": "http://app:80"}]}, "status": "enabled"}
+	},
+	"middlewares": {
+		"team-a-auth@http": {"basicAuth": {}, "status": "warning", "error": ["no users"]}
+	}
+}`
+
+func TestReconciler(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/rawdata" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(testRawdata))
+	}))
+	defer healthy.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer failing.Close()
+
+	reconciler := NewReconciler([]config.TraefikInstance{
+		{Name: "edge-1", URL: healthy.URL},
+		{Name: "edge-2", URL: failing.URL + "/"},
+	}, time.Second)
+	reconciler.Reconcile(context.Background())
+
+	t.Run("Enabled Router", func(t *testing.T) {
+		statuses := reconciler.Status(TypeRouters, "web")
+		if len(statuses) != 2 {
+			t.Fatalf("Expected 2 instance statuses, got %d", len(statuses))
+		}
+		if !statuses[0].Found || statuses[0].Status != "enabled" || statuses[0].Rejected {
+			t.Fatalf("Unexpected status on edge-1: %+v", statuses[0])
+		}
+		if statuses[0].CheckedAt.IsZero() {
+			t.Fatalf("Expected check time on edge-1")
+		}
+		if statuses[1].Found || statuses[1].FetchError == "" {
+			t.Fatalf("Expected fetch error on edge-2, got %+v", statuses[1])
+		}
+	})
+
+	t.Run("Rejected Router", func(t *testing.T) {
+		status := reconciler.Status(TypeRouters, "broken")[0]
+		if !status.Rejected || len(status.Errors) != 1 {
+			t.Fatalf("Expected rejected router with an error, got %+v", status)
+		}
+	})
+
+	t.Run("Warning Is Not Rejected", func(t *testing.T) {
+		status := reconciler.Status(TypeMiddlewares, "team-a-auth")[0]
+		if status.Rejected || status.Status != "warning" || len(status.Errors) != 1 {
+			t.Fatalf("Unexpected middleware status: %+v", status)
+		}
+	})
+
+	t.Run("Other Providers Are Ignored", func(t *testing.T) {
+		if status := reconciler.Status(TypeRouters, "dashboard")[0]; status.Found {
+			t.Fatalf("Expected @internal router to be ignored, got %+v", status)
+		}
+	})
+
+	t.Run("Keeps Last State On Failure", func(t *testing.T) {
+		healthy.Close()
+		reconciler.Reconcile(context.Background())
+
+		status := reconciler.Status(TypeServices, "app")[0]
+		if !status.Found || status.FetchError == "" {
+			t.Fatalf("Expected last known state with fetch error, got %+v", status)
+		}
+
+		instances := reconciler.Instances()
+		if instances[0].Error == "" || instances[0].CheckedAt.IsZero() {
+			t.Fatalf("Unexpected instance state: %+v", instances[0])
+		}
+	})
+}
@@ -0,0 +1,276 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// appData is the stored form of an app, referencing its components by ID
+type appData struct {
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+	models.AppComponents
+	models.ResourceMeta
+}
+
+// ListApps returns all apps
+func (s *FileStore) ListApps() ([]models.App, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	apps := make([]models.App, 0, len(s.data.Apps))
+	for _, record := range s.data.Apps {
+		apps = append(apps, s.resolveApp(record))
+	}
+	return apps, nil
+}
+
+// GetApp returns an app with its components by ID
+func (s *FileStore) GetApp(id string) (*models.App, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.data.Apps[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	app := s.resolveApp(record)
+	return &app, nil
+}
+
+// CreateApp creates an app and all its components, or nothing if one of them can't be created
+func (s *FileStore) CreateApp(app *models.App) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.createApp(app)
+}
+
+// DeleteApp deletes an app and the components that are neither shared with another app nor used by other resources
+func (s *FileStore) DeleteApp(id string) (*models.AppDeleteResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deleteApp(id)
+}
+
+// resolveApp returns an app with its components. Components deleted on their own are skipped.
+// Must be called with the lock held.
+func (s *FileStore) resolveApp(record appData) models.App {
+	app := models.App{
+		ID:           record.ID,
+		Description:  record.Description,
+		Routers:      []models.Router{},
+		Services:     []models.Service{},
+		Middlewares:  []models.Middleware{},
+		ResourceMeta: record.ResourceMeta,
+	}
+	for _, id := range record.Routers {
+		if router, ok := s.data.Routers[id]; ok {
+			app.Routers = append(app.Routers, router)
+		}
+	}
+	for _, id := range record.Services {
+		if service, ok := s.data.Services[id]; ok {
+			app.Services = append(app.Services, service)
+		}
+	}
+	for _, id := range record.Middlewares {
+		if middleware, ok := s.data.Middlewares[id]; ok {
+			app.Middlewares = append(app.Middlewares, middleware)
+		}
+	}
+	return app
+}
+
+// createApp is an internal non-locking version of CreateApp.
+// Services and middlewares of other apps referenced by the app become components shared with it.
+func (s *FileStore) createApp(app *models.App) error {
+	if _, ok := s.data.Apps[app.ID]; ok {
+		return ErrAlreadyExists
+	}
+
+	return s.transaction(func() error {
+		record := appData{
+			ID:          app.ID,
+			Description: app.Description,
+			AppComponents: models.AppComponents{
+				Routers:     []string{},
+				Services:    []string{},
+				Middlewares: []string{},
+			},
+		}
+
+		// Referenced resources are created before the resources referencing them
+		app.Services = models.OrderServices(app.Services)
+		for i := range app.Services {
+			service := &app.Services[i]
+			if err := s.createService(service); err != nil {
+				return fmt.Errorf("service %s: %w", service.ID, err)
+			}
+			record.Services = append(record.Services, service.ID)
+		}
+		app.Middlewares = models.OrderMiddlewares(app.Middlewares)
+		for i := range app.Middlewares {
+			middleware := &app.Middlewares[i]
+			if err := s.createMiddleware(middleware); err != nil {
+				return fmt.Errorf("middleware %s: %w", middleware.ID, err)
+			}
+			record.Middlewares = append(record.Middlewares, middleware.ID)
+		}
+		for i := range app.Routers {
+			router := &app.Routers[i]
+			if err := s.createRouter(router); err != nil {
+				return fmt.Errorf("router %s: %w", router.ID, err)
+			}
+			record.Routers = append(record.Routers, router.ID)
+		}
+
+		serviceRefs, middlewareRefs := appRefs(app)
+		for _, ref := range serviceRefs {
+			if s.appComponent(ResourceServices, ref, "") && !containsID(record.Services, ref) {
+				record.Services = append(record.Services, ref)
+			}
+		}
+		for _, ref := range middlewareRefs {
+			if s.appComponent(ResourceMiddlewares, ref, "") && !containsID(record.Middlewares, ref) {
+				record.Middlewares = append(record.Middlewares, ref)
+			}
+		}
+
+		s.stamp(&record.ResourceMeta, nil)
+		app.ResourceMeta = record.ResourceMeta
+		s.data.Apps[app.ID] = record
+		s.notify(models.ChangeCreated, ResourceApps, app.ID)
+		s.triggerSave()
+		return nil
+	})
+}
+
+// deleteApp is an internal non-locking version of DeleteApp.
+// Routers are deleted first, so that the services and middlewares they used are no longer in use.
+func (s *FileStore) deleteApp(id string) (*models.AppDeleteResponse, error) {
+	record, ok := s.data.Apps[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	response := &models.AppDeleteResponse{ID: id}
+	err := s.transaction(func() error {
+		var err error
+		response.Deleted.Routers, response.Kept.Routers, err = s.deleteAppComponents(id, ResourceRouters, record.Routers, s.deleteRouter)
+		if err != nil {
+			return err
+		}
+		response.Deleted.Middlewares, response.Kept.Middlewares, err = s.deleteAppComponents(id, ResourceMiddlewares, record.Middlewares, s.deleteMiddleware)
+		if err != nil {
+			return err
+		}
+		response.Deleted.Services, response.Kept.Services, err = s.deleteAppComponents(id, ResourceServices, record.Services, s.deleteService)
+		if err != nil {
+			return err
+		}
+
+		delete(s.data.Apps, id)
+		s.notify(models.ChangeDeleted, ResourceApps, id)
+		s.triggerSave()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// deleteAppComponents deletes the components of an app that are not shared with another app.
+// Components in use are retried after the others, as they may only be used by components
+// deleted in the meantime, and are kept if they remain in use. Components that no longer
+// exist are skipped. Must be called with the lock held.
+func (s *FileStore) deleteAppComponents(appID, resourceType string, ids []string, remove func(id string) error) (deleted, kept []string, err error) {
+	deleted, kept = []string{}, []string{}
+
+	var pending []string
+	for _, id := range ids {
+		if s.appComponent(resourceType, id, appID) {
+			kept = append(kept, id)
+		} else {
+			pending = append(pending, id)
+		}
+	}
+
+	for len(pending) > 0 {
+		var inUse []string
+		for _, id := range pending {
+			switch err := remove(id); {
+			case err == nil:
+				deleted = append(deleted, id)
+			case IsResourceInUse(err):
+				inUse = append(inUse, id)
+			case !IsNotFound(err):
+				return nil, nil, fmt.Errorf("%s %s: %w", strings.TrimSuffix(resourceType, "s"), id, err)
+			}
+		}
+		if len(inUse) == len(pending) {
+			kept = append(kept, inUse...)
+			break
+		}
+		pending = inUse
+	}
+
+	return deleted, kept, nil
+}
+
+// appComponent returns true if the resource is a component of an app other than the excluded one.
+// Must be called with the lock held.
+func (s *FileStore) appComponent(resourceType, id, excludedApp string) bool {
+	for appID, record := range s.data.Apps {
+		if appID == excludedApp {
+			continue
+		}
+		var ids []string
+		switch resourceType {
+		case ResourceRouters:
+			ids = record.Routers
+		case ResourceServices:
+			ids = record.Services
+		case ResourceMiddlewares:
+			ids = record.Middlewares
+		}
+		if containsID(ids, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// appRefs returns the IDs of the services and middlewares referenced by the components of an app
+func appRefs(app *models.App) (serviceRefs, middlewareRefs []string) {
+	for _, router := range app.Routers {
+		if router.Service.ID != "" {
+			serviceRefs = append(serviceRefs, router.Service.ID)
+		}
+		for _, mw := range router.Middlewares {
+			middlewareRefs = append(middlewareRefs, mw.ID)
+		}
+	}
+	for _, service := range app.Services {
+		serviceRefs = append(serviceRefs, service.ServiceRefs()...)
+	}
+	for _, middleware := range app.Middlewares {
+		middlewareRefs = append(middlewareRefs, middleware.MiddlewareRefs()...)
+		serviceRefs = append(serviceRefs, middleware.ServiceRefs()...)
+	}
+	return serviceRefs, middlewareRefs
+}
+
+// containsID returns true if the ID is in the list
+func containsID(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,177 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+func TestApps(t *testing.T) {
+	fs, err := NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	defer fs.Close()
+
+	var events []models.ChangeEvent
+	fs.OnChange(func(event models.ChangeEvent) {
+		events = append(events, event)
+	})
+
+	if err := fs.CreateService(&models.Service{ID: "standalone", URL: "http://standalone:8080"}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	shop := &models.App{
+		ID: "shop",
+		Routers: []models.Router{
+			{ID: "shop", Rule: "Host(`shop.example.com`)", Service: models.Service{ID: "shop"}, Middlewares: []models.Middleware{{ID: "auth"}}},
+			{ID: "shop-legacy", Rule: "Host(`old.example.com`)", Service: models.Service{ID: "standalone"}},
+		},
+		Services:    []models.Service{{ID: "shop", URL: "http://shop:8080"}},
+		Middlewares: []models.Middleware{{ID: "auth", Type: "basicAuth"}},
+	}
+	if err := fs.WithActor("alice").CreateApp(shop); err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+
+	t.Run("Get Returns The Bundle", func(t *testing.T) {
+		app, err := fs.GetApp("shop")
+		if err != nil {
+			t.Fatalf("Failed to get app: %v", err)
+		}
+		if len(app.Routers) != 2 || len(app.Services) != 1 || len(app.Middlewares) != 1 {
+			t.Fatalf("Expected the created components only, got %+v", app)
+		}
+		if app.CreatedBy != "alice" || app.Routers[0].CreatedBy != "alice" {
+			t.Fatalf("Expected app and components created by alice, got %+v", app)
+		}
+		// The app's change event comes after those of its components
+		if len(events) != 6 || events[5].Type != ResourceApps || events[5].ID != "shop" {
+			t.Fatalf("Expected 5 component events and an app event, got %+v", events)
+		}
+	})
+
+	t.Run("Create Is Atomic", func(t *testing.T) {
+		version := fs.Version()
+		count := len(events)
+
+		// The second router references a missing middleware
+		err := fs.CreateApp(&models.App{
+			ID: "broken",
+			Routers: []models.Router{
+				{ID: "broken", Rule: "Host(`broken.example.com`)", Service: models.Service{ID: "broken"}},
+				{ID: "broken-2", Rule: "Host(`broken.example.com`)", Service: models.Service{ID: "broken"}, Middlewares: []models.Middleware{{ID: "missing"}}},
+			},
+			Services: []models.Service{{ID: "broken", URL: "http://broken:8080"}},
+		})
+		if err == nil {
+			t.Fatal("Expected the app to be rejected")
+		}
+
+		if _, err := fs.GetService("broken"); !IsNotFound(err) {
+			t.Fatalf("Expected the service to be rolled back, got %v", err)
+		}
+		if _, err := fs.GetRouter("broken"); !IsNotFound(err) {
+			t.Fatalf("Expected the router to be rolled back, got %v", err)
+		}
+		if fs.Version() != version || len(events) != count {
+			t.Fatalf("Expected no change to be reported, got version %d and events %+v", fs.Version(), events[count:])
+		}
+
+		if err := fs.CreateApp(&models.App{ID: "shop", Routers: shop.Routers}); !IsAlreadyExists(err) {
+			t.Fatalf("Expected already exists, got %v", err)
+		}
+	})
+
+	t.Run("Delete Keeps Shared Components", func(t *testing.T) {
+		// admin uses the auth middleware of shop, so it is shared by both apps
+		if err := fs.CreateApp(&models.App{
+			ID:       "admin",
+			Routers:  []models.Router{{ID: "admin", Rule: "Host(`admin.example.com`)", Service: models.Service{ID: "admin"}, Middlewares: []models.Middleware{{ID: "auth"}}}},
+			Services: []models.Service{{ID: "admin", URL: "http://admin:8080"}},
+		}); err != nil {
+			t.Fatalf("Failed to create app: %v", err)
+		}
+		admin, _ := fs.GetApp("admin")
+		if len(admin.Middlewares) != 1 || admin.Middlewares[0].ID != "auth" {
+			t.Fatalf("Expected auth to be a component of admin, got %+v", admin.Middlewares)
+		}
+
+		response, err := fs.DeleteApp("shop")
+		if err != nil {
+			t.Fatalf("Failed to delete app: %v", err)
+		}
+		if fmt.Sprint(response.Deleted) != "{[shop shop-legacy] [shop] []}" || fmt.Sprint(response.Kept) != "{[] [] [auth]}" {
+			t.Fatalf("Unexpected delete response %+v", response)
+		}
+		// Services that are not part of the app are left alone
+		if _, err := fs.GetService("standalone"); err != nil {
+			t.Fatalf("Expected standalone service to be kept, got %v", err)
+		}
+
+		response, err = fs.DeleteApp("admin")
+		if err != nil {
+			t.Fatalf("Failed to delete app: %v", err)
+		}
+		if fmt.Sprint(response.Deleted) != "{[admin] [admin] [auth]}" {
+			t.Fatalf("Expected the last app to delete auth, got %+v", response)
+		}
+		if apps, _ := fs.ListApps(); len(apps) != 0 {
+			t.Fatalf("Expected no apps, got %+v", apps)
+		}
+	})
+
+	t.Run("Delete Keeps Components In Use", func(t *testing.T) {
+		if err := fs.CreateApp(&models.App{
+			ID:       "api",
+			Routers:  []models.Router{{ID: "api", Rule: "Host(`api.example.com`)", Service: models.Service{ID: "api"}}},
+			Services: []models.Service{{ID: "api", URL: "http://api:8080"}},
+		}); err != nil {
+			t.Fatalf("Failed to create app: %v", err)
+		}
+		if err := fs.CreateRouter(&models.Router{ID: "api-v2", Rule: "Host(`v2.example.com`)", Service: models.Service{ID: "api"}}); err != nil {
+			t.Fatalf("Failed to create router: %v", err)
+		}
+
+		response, err := fs.DeleteApp("api")
+		if err != nil {
+			t.Fatalf("Failed to delete app: %v", err)
+		}
+		if fmt.Sprint(response.Kept.Services) != "[api]" {
+			t.Fatalf("Expected the service used by api-v2 to be kept, got %+v", response)
+		}
+	})
+
+	t.Run("Namespaced Apps", func(t *testing.T) {
+		team := fs.Namespace("team-a")
+		if err := team.CreateApp(&models.App{
+			ID:       "web",
+			Routers:  []models.Router{{ID: "web", Rule: "Host(`web.example.com`)", Service: models.Service{ID: "web"}}},
+			Services: []models.Service{{ID: "web", URL: "http://web:8080"}},
+		}); err != nil {
+			t.Fatalf("Failed to create app: %v", err)
+		}
+
+		app, err := team.GetApp("web")
+		if err != nil {
+			t.Fatalf("Failed to get app: %v", err)
+		}
+		if app.Routers[0].ID != "web" || app.Routers[0].Service.ID != "web" {
+			t.Fatalf("Expected local IDs, got %+v", app.Routers[0])
+		}
+		if _, err := fs.GetRouter("team-a/web"); err != nil {
+			t.Fatalf("Expected router to be stored in the namespace, got %v", err)
+		}
+		if _, err := fs.Namespace("team-b").GetApp("web"); !IsNotFound(err) {
+			t.Fatalf("Expected app to be hidden from other namespaces, got %v", err)
+		}
+
+		response, err := team.DeleteApp("web")
+		if err != nil || fmt.Sprint(response.Deleted.Routers) != "[web]" {
+			t.Fatalf("Expected local IDs in the response, got %+v, %v", response, err)
+		}
+	})
+}
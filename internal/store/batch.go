@@ -0,0 +1,68 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// Batch holds routers, services and middlewares written at once by ApplyBatch
+type Batch struct {
+	Routers     []models.Router
+	Services    []models.Service
+	Middlewares []models.Middleware
+	// Updates lists the resources replacing existing ones, all others are created
+	Updates map[models.ResourceRef]bool
+}
+
+// ApplyBatch creates and updates the resources of a batch in one transaction:
+// either all of them are written, or none is
+func (s *FileStore) ApplyBatch(batch *Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.applyBatch(batch)
+}
+
+// applyBatch is an internal non-locking version of ApplyBatch.
+// Referenced resources are written before the resources referencing them.
+func (s *FileStore) applyBatch(batch *Batch) error {
+	return s.transaction(func() error {
+		for _, service := range models.OrderServices(batch.Services) {
+			var err error
+			if batch.Updates[models.ResourceRef{ResourceType: typeService, ID: service.ID}] {
+				err = s.updateService(service.ID, &service)
+			} else {
+				err = s.createService(&service)
+			}
+			if err != nil {
+				return fmt.Errorf("service %s: %w", service.ID, err)
+			}
+		}
+
+		for _, middleware := range models.OrderMiddlewares(batch.Middlewares) {
+			var err error
+			if batch.Updates[models.ResourceRef{ResourceType: typeMiddleware, ID: middleware.ID}] {
+				err = s.updateMiddleware(middleware.ID, &middleware)
+			} else {
+				err = s.createMiddleware(&middleware)
+			}
+			if err != nil {
+				return fmt.Errorf("middleware %s: %w", middleware.ID, err)
+			}
+		}
+
+		for _, router := range batch.Routers {
+			var err error
+			if batch.Updates[models.ResourceRef{ResourceType: typeRouter, ID: router.ID}] {
+				err = s.updateRouter(router.ID, &router)
+			} else {
+				err = s.createRouter(&router)
+			}
+			if err != nil {
+				return fmt.Errorf("router %s: %w", router.ID, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,271 @@
+package store
+
+import (
+	"maps"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// Resource types reported in change events
+const (
+	ResourceRouters     = "routers"
+	ResourceServices    = "services"
+	ResourceMiddlewares = "middlewares"
+	ResourceApps        = "apps"
+)
+
+// ChangeNotifier is implemented by stores reporting the changes of their resources
+type ChangeNotifier interface {
+	// OnChange registers a function called for every change
+	OnChange(fn func(models.ChangeEvent))
+	// Version returns the current store version
+	Version() uint64
+}
+
+// OnChange registers a function called for every change of a resource.
+// It is called with the store lock held and must not block or use the store.
+func (s *FileStore) OnChange(fn func(models.ChangeEvent)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = append(s.onChange, fn)
+}
+
+// Version returns the current store version
+func (s *FileStore) Version() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data.Version
+}
+
+// notify increments the store version and reports a change, or queues it until the
+// transaction in progress succeeds. Must be called with the lock held.
+func (s *FileStore) notify(action, resourceType, id string) {
+	s.data.Version++
+
+	event := models.ChangeEvent{
+		Version: s.data.Version,
+		Action:  action,
+		Type:    resourceType,
+		ID:      id,
+		Actor:   s.actor,
+		Time:    time.Now().UTC(),
+	}
+	if s.pending != nil {
+		s.pending = append(s.pending, event)
+		return
+	}
+	for _, fn := range s.onChange {
+		fn(event)
+	}
+}
+
+// transaction runs fn as one change of the store. If fn fails, the data is restored and
+// the changes of fn are not reported, otherwise they are reported once fn returns.
+// Must be called with the lock held and can't be nested.
+func (s *FileStore) transaction(fn func() error) error {
+	snapshot := s.data
+	snapshot.Middlewares = maps.Clone(s.data.Middlewares)
+	snapshot.Routers = maps.Clone(s.data.Routers)
+	snapshot.Services = maps.Clone(s.data.Services)
+	snapshot.Apps = maps.Clone(s.data.Apps)
+
+	s.pending = []models.ChangeEvent{}
+	defer func() {
+		s.pending = nil
+	}()
+
+	if err := fn(); err != nil {
+		s.data = snapshot
+		return err
+	}
+
+	for _, event := range s.pending {
+		for _, fn := range s.onChange {
+			fn(event)
+		}
+	}
+	return nil
+}
+
+// stamp sets the metadata of a resource being created, or updated when existing is the
+// metadata of the stored resource. Values set by the caller are overwritten.
+// Must be called with the lock held, before notify.
+func (s *FileStore) stamp(meta *models.ResourceMeta, existing *models.ResourceMeta) {
+	now := time.Now().UTC()
+	if existing != nil {
+		meta.CreatedAt = existing.CreatedAt
+		meta.CreatedBy = existing.CreatedBy
+	} else {
+		meta.CreatedAt = &now
+		meta.CreatedBy = s.actor
+	}
+	meta.UpdatedAt = &now
+	meta.UpdatedBy = s.actor
+	// notify assigns the next version to the change
+	meta.ResourceVersion = s.data.Version + 1
+}
+
+// attribute sets the actor of the change in progress and returns a function resetting it.
+// Must be called with the lock held.
+func (s *FileStore) attribute(actor string) func() {
+	s.actor = actor
+	return func() {
+		s.actor = ""
+	}
+}
+
+// WithActor returns a view of the store that attributes changes to the actor
+func (s *FileStore) WithActor(actor string) Store {
+	return &actorStore{FileStore: s, actor: actor}
+}
+
+// actorStore is a view of a FileStore attributing changes to an actor
+type actorStore struct {
+	*FileStore
+	actor string
+}
+
+// Namespace returns a namespaced view attributing changes to the same actor
+func (a *actorStore) Namespace(name string) Store {
+	if name == "" {
+		return a
+	}
+	return &namespacedStore{fs: a.FileStore, namespace: name, actor: a.actor}
+}
+
+// WithActor returns a view attributing changes to another actor
+func (a *actorStore) WithActor(actor string) Store {
+	return a.FileStore.WithActor(actor)
+}
+
+// Close is a no-op, the underlying store is owned by its creator
+func (a *actorStore) Close() {}
+
+// CreateMiddleware creates a new middleware
+func (a *actorStore) CreateMiddleware(middleware *models.Middleware) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.createMiddleware(middleware)
+}
+
+// UpdateMiddleware updates an existing middleware
+func (a *actorStore) UpdateMiddleware(id string, middleware *models.Middleware) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.updateMiddleware(id, middleware)
+}
+
+// DeleteMiddleware deletes a middleware
+func (a *actorStore) DeleteMiddleware(id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.deleteMiddleware(id)
+}
+
+// DeleteMiddlewareWithOptions deletes a middleware, with cascade along with its dependents
+func (a *actorStore) DeleteMiddlewareWithOptions(id string, options DeleteOptions) ([]models.ResourceRef, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.deleteWithOptions(typeMiddleware, id, options)
+}
+
+// RenameMiddleware renames a middleware and rewrites the references to it
+func (a *actorStore) RenameMiddleware(id, newID string, authorize Authorizer) ([]models.ResourceRef, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.rename(typeMiddleware, id, newID, authorize)
+}
+
+// CreateRouter creates a new router
+func (a *actorStore) CreateRouter(router *models.Router) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.createRouter(router)
+}
+
+// UpdateRouter updates a router
+func (a *actorStore) UpdateRouter(id string, router *models.Router) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.updateRouter(id, router)
+}
+
+// DeleteRouter deletes a router
+func (a *actorStore) DeleteRouter(id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.deleteRouter(id)
+}
+
+// CreateService creates a new service
+func (a *actorStore) CreateService(service *models.Service) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.createService(service)
+}
+
+// UpdateService updates an existing service
+func (a *actorStore) UpdateService(id string, service *models.Service) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.updateService(id, service)
+}
+
+// DeleteService deletes a service
+func (a *actorStore) DeleteService(id string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.deleteService(id)
+}
+
+// DeleteServiceWithOptions deletes a service, with cascade along with its dependents
+func (a *actorStore) DeleteServiceWithOptions(id string, options DeleteOptions) ([]models.ResourceRef, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.deleteWithOptions(typeService, id, options)
+}
+
+// RenameService renames a service and rewrites the references to it
+func (a *actorStore) RenameService(id, newID string, authorize Authorizer) ([]models.ResourceRef, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.rename(typeService, id, newID, authorize)
+}
+
+// CreateApp creates an app and all its components
+func (a *actorStore) CreateApp(app *models.App) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.createApp(app)
+}
+
+// ApplyBatch creates and updates the resources of a batch in one transaction
+func (a *actorStore) ApplyBatch(batch *Batch) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.applyBatch(batch)
+}
+
+// DeleteApp deletes an app and its unshared components
+func (a *actorStore) DeleteApp(id string) (*models.AppDeleteResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.attribute(a.actor)()
+	return a.deleteApp(id)
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+func TestChangeNotification(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	fs, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+
+	var events []models.ChangeEvent
+	fs.OnChange(func(event models.ChangeEvent) {
+		events = append(events, event)
+	})
+
+	alice := fs.WithActor("alice")
+	if err := alice.CreateService(&models.Service{ID: "api", URL: "http://api:8080"}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	if err := alice.Namespace("team-a").CreateService(&models.Service{ID: "web", URL: "http://web:8080"}); err != nil {
+		t.Fatalf("Failed to create namespaced service: %v", err)
+	}
+	if err := fs.Namespace("team-a").WithActor("bob").UpdateService("web", &models.Service{URL: "http://web:9090"}); err != nil {
+		t.Fatalf("Failed to update namespaced service: %v", err)
+	}
+	if err := fs.DeleteService("api"); err != nil {
+		t.Fatalf("Failed to delete service: %v", err)
+	}
+	// Failed changes are not reported
+	if err := alice.DeleteService("missing"); !IsNotFound(err) {
+		t.Fatalf("Expected not found, got %v", err)
+	}
+
+	expected := []models.ChangeEvent{
+		{Version: 1, Action: models.ChangeCreated, Type: ResourceServices, ID: "api", Actor: "alice"},
+		{Version: 2, Action: models.ChangeCreated, Type: ResourceServices, ID: "team-a/web", Actor: "alice"},
+		{Version: 3, Action: models.ChangeUpdated, Type: ResourceServices, ID: "team-a/web", Actor: "bob"},
+		{Version: 4, Action: models.ChangeDeleted, Type: ResourceServices, ID: "api"},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(expected), len(events), events)
+	}
+	for i, want := range expected {
+		got := events[i]
+		if got.Version != want.Version || got.Action != want.Action || got.Type != want.Type || got.ID != want.ID || got.Actor != want.Actor {
+			t.Errorf("Event %d: expected %+v, got %+v", i, want, got)
+		}
+		if got.Time.IsZero() {
+			t.Errorf("Event %d has no time", i)
+		}
+	}
+
+	// The version survives a restart
+	if err := fs.Save(); err != nil {
+		t.Fatalf("Failed to save store: %v", err)
+	}
+	fs.Close()
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen file store: %v", err)
+	}
+	defer reopened.Close()
+
+	if version := reopened.Version(); version != 4 {
+		t.Fatalf("Expected version 4 after reload, got %d", version)
+	}
+}
+
+func TestResourceMetadata(t *testing.T) {
+	fs, err := NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	defer fs.Close()
+
+	// Metadata set by the caller is ignored
+	forged := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	service := &models.Service{ID: "web", URL: "http://web:8080", ResourceMeta: models.ResourceMeta{
+		CreatedAt: &forged, CreatedBy: "mallory", ResourceVersion: 42,
+	}}
+	if err := fs.Namespace("team-a").WithActor("alice").CreateService(service); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	if err := fs.CreateService(&models.Service{ID: "api", URL: "http://api:8080"}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	created, err := fs.GetService("team-a/web")
+	if err != nil {
+		t.Fatalf("Failed to get service: %v", err)
+	}
+	if created.CreatedBy != "alice" || created.UpdatedBy != "alice" || created.ResourceVersion != 1 {
+		t.Fatalf("Expected service created by alice at version 1, got %+v", created.ResourceMeta)
+	}
+	if created.CreatedAt == nil || created.CreatedAt.Equal(forged) || !created.UpdatedAt.Equal(*created.CreatedAt) {
+		t.Fatalf("Expected creation time to be set by the store, got %+v", created.ResourceMeta)
+	}
+
+	update := &models.Service{URL: "http://web:9090", ResourceMeta: models.ResourceMeta{CreatedBy: "mallory"}}
+	if err := fs.WithActor("bob").Namespace("team-a").UpdateService("web", update); err != nil {
+		t.Fatalf("Failed to update service: %v", err)
+	}
+
+	updated, _ := fs.GetService("team-a/web")
+	if updated.CreatedBy != "alice" || !updated.CreatedAt.Equal(*created.CreatedAt) {
+		t.Fatalf("Expected creation metadata to be kept, got %+v", updated.ResourceMeta)
+	}
+	if updated.UpdatedBy != "bob" || updated.UpdatedAt.Before(*created.UpdatedAt) {
+		t.Fatalf("Expected service updated by bob, got %+v", updated.ResourceMeta)
+	}
+	if updated.ResourceVersion != 3 || updated.ResourceVersion != fs.Version() {
+		t.Fatalf("Expected resource version 3, got %d", updated.ResourceVersion)
+	}
+}
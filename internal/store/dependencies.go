@@ -0,0 +1,323 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// Resource types as named in dependencies
+const (
+	typeRouter     = "router"
+	typeService    = "service"
+	typeMiddleware = "middleware"
+	typeApp        = "app"
+)
+
+// DeleteOptions control how a resource is deleted
+type DeleteOptions struct {
+	// Cascade deletes the resources depending on the resource as well, transitively
+	Cascade bool
+	// DryRun reports what would be deleted without deleting anything
+	DryRun bool
+	// Authorize optionally refuses the delete with ErrForbidden if it returns false for a resource to delete
+	Authorize Authorizer
+}
+
+// Authorizer returns true if the caller may change the given resource
+type Authorizer func(ref models.ResourceRef) bool
+
+// authorizeAll returns ErrForbidden if the authorizer refuses one of the given resources
+func authorizeAll(authorizer Authorizer, refs []models.ResourceRef) error {
+	if authorizer == nil {
+		return nil
+	}
+	for _, ref := range refs {
+		if !authorizer(ref) {
+			return fmt.Errorf("%w: %s %s", ErrForbidden, ref.ResourceType, ref.ID)
+		}
+	}
+	return nil
+}
+
+// errDryRun rolls back the transaction of a dry run
+var errDryRun = errors.New("dry run")
+
+// dependencyIndex maps every referenced service and middleware to the resources referencing it:
+// routers, weighted, mirroring and failover services, chain and errors middlewares.
+// Dependencies are sorted by type, ID and field. Must be called with the lock held.
+func (s *FileStore) dependencyIndex() map[models.ResourceRef][]Dependency {
+	index := make(map[models.ResourceRef][]Dependency)
+	add := func(resourceType, id string, refs []models.Reference) {
+		for _, ref := range refs {
+			target := models.ResourceRef{ResourceType: ref.ResourceType, ID: ref.ID}
+			dependency := Dependency{ResourceType: resourceType, ID: id, Field: ref.Field}
+			if !slices.Contains(index[target], dependency) {
+				index[target] = append(index[target], dependency)
+			}
+		}
+	}
+
+	for id, router := range s.data.Routers {
+		add(typeRouter, id, router.References())
+	}
+	for id, service := range s.data.Services {
+		add(typeService, id, service.References())
+	}
+	for id, middleware := range s.data.Middlewares {
+		add(typeMiddleware, id, middleware.References())
+	}
+
+	for _, dependencies := range index {
+		sort.Slice(dependencies, func(i, j int) bool {
+			a, b := dependencies[i], dependencies[j]
+			if a.ResourceType != b.ResourceType {
+				return a.ResourceType < b.ResourceType
+			}
+			if a.ID != b.ID {
+				return a.ID < b.ID
+			}
+			return a.Field < b.Field
+		})
+	}
+	return index
+}
+
+// dependents returns the resources referencing the given resource, sorted by type and ID.
+// Must be called with the lock held.
+func (s *FileStore) dependents(resourceType, id string) []Dependency {
+	dependencies := s.dependencyIndex()[models.ResourceRef{ResourceType: resourceType, ID: id}]
+	if dependencies == nil {
+		return []Dependency{}
+	}
+	return dependencies
+}
+
+// checkReferences verifies that the services and middlewares referenced by a resource exist.
+// Must be called with the lock held.
+func (s *FileStore) checkReferences(resourceType, id string, refs []models.Reference) error {
+	for _, ref := range refs {
+		if !s.exists(ref.ResourceType, ref.ID) {
+			return NewValidationError(resourceType, id, ref.Field, fmt.Sprintf("%s %s not found", ref.ResourceType, ref.ID))
+		}
+	}
+	return nil
+}
+
+// checkCycles verifies that the references of a resource to resources of the same type, i.e.
+// between services and between chained middlewares, don't lead back to it. The given references
+// replace those currently stored for the resource. Must be called with the lock held.
+func (s *FileStore) checkCycles(resourceType, id string, refs []models.Reference) error {
+	references := func(id string) []models.Reference {
+		switch resourceType {
+		case typeService:
+			return s.data.Services[id].References()
+		case typeMiddleware:
+			return s.data.Middlewares[id].References()
+		}
+		return nil
+	}
+
+	// Depth-first search for a path back to the resource, following its new references first
+	visited := make(map[string]bool)
+	path := []string{id}
+	var visit func(refs []models.Reference) bool
+	visit = func(refs []models.Reference) bool {
+		for _, ref := range refs {
+			if ref.ResourceType != resourceType {
+				continue
+			}
+			path = append(path, ref.ID)
+			if ref.ID == id {
+				return true
+			}
+			if !visited[ref.ID] {
+				visited[ref.ID] = true
+				if visit(references(ref.ID)) {
+					return true
+				}
+			}
+			path = path[:len(path)-1]
+		}
+		return false
+	}
+
+	for _, ref := range refs {
+		if visit([]models.Reference{ref}) {
+			return NewValidationError(resourceType, id, ref.Field,
+				fmt.Sprintf("reference cycle %s", strings.Join(path, " -> ")))
+		}
+	}
+	return nil
+}
+
+// usedBy returns the dependents of a resource in the "type:id" form of the InUse methods.
+// Must be called with the lock held.
+func (s *FileStore) usedBy(resourceType, id string) []string {
+	dependencies := s.dependents(resourceType, id)
+	usedBy := make([]string, len(dependencies))
+	for i, dependency := range dependencies {
+		usedBy[i] = fmt.Sprintf("%s:%s", dependency.ResourceType, dependency.ID)
+	}
+	return usedBy
+}
+
+// checkUnused returns a DependencyError if other resources reference the given resource.
+// Must be called with the lock held.
+func (s *FileStore) checkUnused(resourceType, id string) error {
+	if dependencies := s.dependents(resourceType, id); len(dependencies) > 0 {
+		return NewDependencyError(resourceType, id, dependencies)
+	}
+	return nil
+}
+
+// deletePlan returns the resources to delete to delete the given resource, dependents first.
+// Without cascade, the plan only holds the resource, which must not be in use.
+// Must be called with the lock held.
+func (s *FileStore) deletePlan(resourceType, id string, cascade bool) ([]models.ResourceRef, error) {
+	if !s.exists(resourceType, id) {
+		return nil, ErrNotFound
+	}
+	if !cascade {
+		if err := s.checkUnused(resourceType, id); err != nil {
+			return nil, err
+		}
+		return []models.ResourceRef{{ResourceType: resourceType, ID: id}}, nil
+	}
+
+	index := s.dependencyIndex()
+	plan := []models.ResourceRef{}
+	visited := make(map[models.ResourceRef]bool)
+	var visit func(ref models.ResourceRef)
+	visit = func(ref models.ResourceRef) {
+		if visited[ref] {
+			return
+		}
+		visited[ref] = true
+		for _, dependency := range index[ref] {
+			visit(models.ResourceRef{ResourceType: dependency.ResourceType, ID: dependency.ID})
+		}
+		plan = append(plan, ref)
+	}
+	visit(models.ResourceRef{ResourceType: resourceType, ID: id})
+	return plan, nil
+}
+
+// deleteAll deletes the resources of a plan in one transaction, rolled back for a dry run.
+// Must be called with the lock held.
+func (s *FileStore) deleteAll(plan []models.ResourceRef, dryRun bool) error {
+	err := s.transaction(func() error {
+		for _, ref := range plan {
+			var err error
+			switch ref.ResourceType {
+			case typeRouter:
+				err = s.deleteRouter(ref.ID)
+			case typeService:
+				err = s.deleteService(ref.ID)
+			case typeMiddleware:
+				err = s.deleteMiddleware(ref.ID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		return nil
+	}
+	return err
+}
+
+// exists returns true if the given resource exists. Must be called with the lock held.
+func (s *FileStore) exists(resourceType, id string) bool {
+	var ok bool
+	switch resourceType {
+	case typeRouter:
+		_, ok = s.data.Routers[id]
+	case typeService:
+		_, ok = s.data.Services[id]
+	case typeMiddleware:
+		_, ok = s.data.Middlewares[id]
+	}
+	return ok
+}
+
+// DeleteServiceWithOptions deletes a service, with cascade along with the resources depending on it.
+// It returns the deleted resources, or with dry run those that would be deleted.
+func (s *FileStore) DeleteServiceWithOptions(id string, options DeleteOptions) ([]models.ResourceRef, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deleteWithOptions(typeService, id, options)
+}
+
+// DeleteMiddlewareWithOptions deletes a middleware, with cascade along with the resources depending on it.
+// It returns the deleted resources, or with dry run those that would be deleted.
+func (s *FileStore) DeleteMiddlewareWithOptions(id string, options DeleteOptions) ([]models.ResourceRef, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deleteWithOptions(typeMiddleware, id, options)
+}
+
+// deleteWithOptions is an internal non-locking version of the DeleteWithOptions methods
+func (s *FileStore) deleteWithOptions(resourceType, id string, options DeleteOptions) ([]models.ResourceRef, error) {
+	plan, err := s.deletePlan(resourceType, id, options.Cascade)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeAll(options.Authorize, plan); err != nil {
+		return nil, err
+	}
+	if err := s.deleteAll(plan, options.DryRun); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Graph returns the dependency graph of all routers, services and middlewares
+func (s *FileStore) Graph() (*models.Graph, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.graph(), nil
+}
+
+// graph is an internal non-locking version of Graph
+func (s *FileStore) graph() *models.Graph {
+	graph := &models.Graph{
+		Nodes: []models.ResourceRef{},
+		Edges: []models.GraphEdge{},
+	}
+
+	for id := range s.data.Routers {
+		graph.Nodes = append(graph.Nodes, models.ResourceRef{ResourceType: typeRouter, ID: id})
+	}
+	for id := range s.data.Services {
+		graph.Nodes = append(graph.Nodes, models.ResourceRef{ResourceType: typeService, ID: id})
+	}
+	for id := range s.data.Middlewares {
+		graph.Nodes = append(graph.Nodes, models.ResourceRef{ResourceType: typeMiddleware, ID: id})
+	}
+
+	for target, dependencies := range s.dependencyIndex() {
+		for _, dependency := range dependencies {
+			graph.Edges = append(graph.Edges, models.GraphEdge{
+				From:  models.ResourceRef{ResourceType: dependency.ResourceType, ID: dependency.ID},
+				To:    target,
+				Field: dependency.Field,
+			})
+		}
+	}
+
+	graph.Sort()
+	return graph
+}
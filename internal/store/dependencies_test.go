@@ -0,0 +1,323 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+func TestDeleteWithOptions(t *testing.T) {
+	fs, err := NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	defer fs.Close()
+
+	var events []models.ChangeEvent
+	fs.OnChange(func(event models.ChangeEvent) {
+		events = append(events, event)
+	})
+
+	fs.CreateService(&models.Service{ID: "api", URL: "http://api:8080"})
+	fs.CreateMiddleware(&models.Middleware{ID: "auth", Type: "basicAuth"})
+	fs.CreateRouter(&models.Router{ID: "api", Rule: "Host(`api.example.com`)", Service: models.Service{ID: "api"}, Middlewares: []models.Middleware{{ID: "auth"}}})
+	fs.CreateRouter(&models.Router{ID: "api-v2", Rule: "Host(`v2.example.com`)", Service: models.Service{ID: "api"}})
+
+	t.Run("Dependency Error", func(t *testing.T) {
+		err := fs.DeleteService("api")
+		if !IsResourceInUse(err) {
+			t.Fatalf("Expected resource in use, got %v", err)
+		}
+		dependencies := GetDependencies(err)
+		if fmt.Sprint(dependencies) != "[{router api service} {router api-v2 service}]" {
+			t.Fatalf("Expected the routers as dependencies, got %+v", dependencies)
+		}
+
+		if _, err := fs.DeleteMiddlewareWithOptions("auth", DeleteOptions{DryRun: true}); fmt.Sprint(GetDependencies(err)) != "[{router api middlewares}]" {
+			t.Fatalf("Expected a dry run without cascade to report the dependencies, got %v", err)
+		}
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		version := fs.Version()
+		count := len(events)
+
+		deleted, err := fs.DeleteServiceWithOptions("api", DeleteOptions{Cascade: true, DryRun: true})
+		if err != nil {
+			t.Fatalf("Failed to preview delete: %v", err)
+		}
+		if fmt.Sprint(deleted) != "[{router api} {router api-v2} {service api}]" {
+			t.Fatalf("Expected the dependents before the service, got %+v", deleted)
+		}
+		if _, err := fs.GetRouter("api"); err != nil {
+			t.Fatalf("Expected the dry run to keep the router, got %v", err)
+		}
+		if fs.Version() != version || len(events) != count {
+			t.Fatalf("Expected no change to be reported, got version %d and events %+v", fs.Version(), events[count:])
+		}
+	})
+
+	t.Run("Cascade", func(t *testing.T) {
+		// A cascade reaching a resource the caller may not change deletes nothing
+		servicesOnly := func(ref models.ResourceRef) bool { return ref.ResourceType == typeService }
+		if _, err := fs.DeleteServiceWithOptions("api", DeleteOptions{Cascade: true, Authorize: servicesOnly}); !IsForbidden(err) {
+			t.Fatalf("Expected the cascade to be forbidden, got %v", err)
+		}
+		if _, err := fs.GetRouter("api"); err != nil {
+			t.Fatalf("Expected the router to be kept, got %v", err)
+		}
+
+		deleted, err := fs.WithActor("alice").DeleteServiceWithOptions("api", DeleteOptions{Cascade: true})
+		if err != nil {
+			t.Fatalf("Failed to delete: %v", err)
+		}
+		if len(deleted) != 3 {
+			t.Fatalf("Expected 3 deleted resources, got %+v", deleted)
+		}
+		if routers, _ := fs.ListRouters(); len(routers) != 0 {
+			t.Fatalf("Expected the routers to be deleted, got %+v", routers)
+		}
+		if last := events[len(events)-1]; last.Type != ResourceServices || last.Actor != "alice" {
+			t.Fatalf("Expected the service deletion by alice last, got %+v", last)
+		}
+
+		// The middleware is no longer used and goes alone
+		deleted, err = fs.DeleteMiddlewareWithOptions("auth", DeleteOptions{Cascade: true})
+		if err != nil || fmt.Sprint(deleted) != "[{middleware auth}]" {
+			t.Fatalf("Expected only the middleware to be deleted, got %+v, %v", deleted, err)
+		}
+		if _, err := fs.DeleteMiddlewareWithOptions("auth", DeleteOptions{Cascade: true}); !IsNotFound(err) {
+			t.Fatalf("Expected not found, got %v", err)
+		}
+	})
+
+	t.Run("Namespaces", func(t *testing.T) {
+		teamA, teamB := fs.Namespace("team-a"), fs.Namespace("team-b")
+		teamA.CreateService(&models.Service{ID: "shared", URL: "http://shared:8080", Shared: true})
+		teamA.CreateRouter(&models.Router{ID: "web", Rule: "Host(`a.example.com`)", Service: models.Service{ID: "shared"}})
+		teamB.CreateRouter(&models.Router{ID: "web", Rule: "Host(`b.example.com`)", Service: models.Service{ID: "team-a/shared"}})
+
+		// Dependencies are reported with local IDs, those of other namespaces stay qualified
+		err := teamA.DeleteService("shared")
+		if fmt.Sprint(GetDependencies(err)) != "[{router web service} {router team-b/web service}]" {
+			t.Fatalf("Expected dependencies relative to the namespace, got %v", err)
+		}
+
+		// A cascade must not delete resources of other namespaces
+		if _, err := teamA.DeleteServiceWithOptions("shared", DeleteOptions{Cascade: true}); !IsDependencyError(err) {
+			t.Fatalf("Expected the cascade to be refused, got %v", err)
+		}
+		if _, err := fs.GetRouter("team-a/web"); err != nil {
+			t.Fatalf("Expected the router to be kept, got %v", err)
+		}
+
+		teamB.DeleteRouter("web")
+		deleted, err := teamA.DeleteServiceWithOptions("shared", DeleteOptions{Cascade: true})
+		if err != nil || fmt.Sprint(deleted) != "[{router web} {service shared}]" {
+			t.Fatalf("Expected local IDs of the deleted resources, got %+v, %v", deleted, err)
+		}
+	})
+}
+
+func TestDependencyIndex(t *testing.T) {
+	fs, err := NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	defer fs.Close()
+
+	for _, id := range []string{"blue", "green", "backup", "errors-page"} {
+		if err := fs.CreateService(&models.Service{ID: id, URL: "http://" + id + ":8080"}); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+	}
+	resources := []func() error{
+		func() error {
+			return fs.CreateService(&models.Service{ID: "split", Weighted: &models.WeightedService{
+				Services: []models.WeightedServiceItem{{Name: models.Service{ID: "blue"}, Weight: 3}, {Name: models.Service{ID: "green"}, Weight: 1}},
+			}})
+		},
+		func() error {
+			return fs.CreateService(&models.Service{ID: "shadow", Mirroring: &models.MirroringService{
+				Service: models.Service{ID: "split"}, Mirrors: []models.MirrorServiceItem{{Name: models.Service{ID: "green"}, Percent: 10}},
+			}})
+		},
+		func() error {
+			return fs.CreateService(&models.Service{ID: "ha", Failover: &models.FailoverService{
+				Service: models.Service{ID: "shadow"}, Fallback: models.Service{ID: "backup"},
+			}})
+		},
+		func() error {
+			return fs.CreateMiddleware(&models.Middleware{ID: "errors", Type: "errors", Config: map[string]interface{}{"service": "errors-page"}})
+		},
+		func() error {
+			return fs.CreateMiddleware(&models.Middleware{ID: "auth", Type: "basicAuth", Config: map[string]interface{}{}})
+		},
+		func() error {
+			return fs.CreateMiddleware(&models.Middleware{ID: "secure", Type: "chain", Config: map[string]interface{}{"middlewares": []interface{}{"auth", "errors"}}})
+		},
+		func() error {
+			return fs.CreateRouter(&models.Router{ID: "web", Rule: "Host(`web.example.com`)", Service: models.Service{ID: "ha"}, Middlewares: []models.Middleware{{ID: "secure"}}})
+		},
+	}
+	for _, create := range resources {
+		if err := create(); err != nil {
+			t.Fatalf("Failed to create resource: %v", err)
+		}
+	}
+
+	t.Run("Nested References In Use", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			remove   func() error
+			expected string
+		}{
+			{"Weighted", func() error { return fs.DeleteService("blue") }, "[{service split weighted.services}]"},
+			{"Mirroring", func() error { return fs.DeleteService("green") }, "[{service shadow mirroring.mirrors} {service split weighted.services}]"},
+			{"Failover", func() error { return fs.DeleteService("backup") }, "[{service ha failover.fallback}]"},
+			{"Errors Middleware", func() error { return fs.DeleteService("errors-page") }, "[{middleware errors config.service}]"},
+			{"Chain", func() error { return fs.DeleteMiddleware("auth") }, "[{middleware secure config.middlewares}]"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := tt.remove()
+				if !IsDependencyError(err) || fmt.Sprint(GetDependencies(err)) != tt.expected {
+					t.Fatalf("Expected dependencies %s, got %v %v", tt.expected, err, GetDependencies(err))
+				}
+			})
+		}
+	})
+
+	t.Run("Missing References", func(t *testing.T) {
+		err := fs.CreateService(&models.Service{ID: "broken", Failover: &models.FailoverService{
+			Service: models.Service{ID: "blue"}, Fallback: models.Service{ID: "missing"},
+		}})
+		if !IsValidationError(err) {
+			t.Fatalf("Expected a validation error, got %v", err)
+		}
+
+		err = fs.UpdateMiddleware("secure", &models.Middleware{Type: "chain", Config: map[string]interface{}{"middlewares": []interface{}{"missing"}}})
+		if !IsValidationError(err) {
+			t.Fatalf("Expected a validation error, got %v", err)
+		}
+		if mw, _ := fs.GetMiddleware("secure"); len(mw.MiddlewareRefs()) != 2 {
+			t.Fatalf("Expected the chain to be unchanged, got %+v", mw)
+		}
+	})
+
+	t.Run("Graph", func(t *testing.T) {
+		graph, err := fs.Graph()
+		if err != nil {
+			t.Fatalf("Failed to get graph: %v", err)
+		}
+		if len(graph.Nodes) != 11 || len(graph.Edges) != 11 {
+			t.Fatalf("Expected 11 nodes and 11 edges, got %+v", graph)
+		}
+		if first := graph.Edges[0]; fmt.Sprint(first) != "{{middleware errors} {service errors-page} config.service}" {
+			t.Fatalf("Expected edges sorted by their nodes, got %+v", first)
+		}
+	})
+
+	t.Run("Namespaced Graph", func(t *testing.T) {
+		teamA, teamB := fs.Namespace("team-a"), fs.Namespace("team-b")
+		teamA.CreateService(&models.Service{ID: "api", URL: "http://api:8080", Shared: true})
+		teamB.CreateService(&models.Service{ID: "internal", URL: "http://internal:8080"})
+		teamB.CreateRouter(&models.Router{ID: "web", Rule: "Host(`b.example.com`)", Service: models.Service{ID: "team-a/api"}})
+
+		graph, err := teamB.Graph()
+		if err != nil {
+			t.Fatalf("Failed to get graph: %v", err)
+		}
+		expected := "&{[{router web} {service internal} {service team-a/api}] [{{router web} {service team-a/api} service}]}"
+		if fmt.Sprint(graph) != expected {
+			t.Fatalf("Expected the namespace and its references, got %v", graph)
+		}
+	})
+
+	t.Run("Cascade Follows Nested References", func(t *testing.T) {
+		deleted, err := fs.DeleteServiceWithOptions("green", DeleteOptions{Cascade: true, DryRun: true})
+		if err != nil {
+			t.Fatalf("Failed to preview delete: %v", err)
+		}
+		if fmt.Sprint(deleted) != "[{router web} {service ha} {service shadow} {service split} {service green}]" {
+			t.Fatalf("Expected dependents before their references, got %+v", deleted)
+		}
+
+		deleted, err = fs.DeleteServiceWithOptions("errors-page", DeleteOptions{Cascade: true})
+		if err != nil || fmt.Sprint(deleted) != "[{router web} {middleware secure} {middleware errors} {service errors-page}]" {
+			t.Fatalf("Expected the errors middleware chain to be deleted, got %+v, %v", deleted, err)
+		}
+	})
+}
+
+func TestReferenceCycles(t *testing.T) {
+	fs, err := NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	defer fs.Close()
+
+	fs.CreateService(&models.Service{ID: "api", URL: "http://api:8080"})
+	fs.CreateService(&models.Service{ID: "split", Weighted: &models.WeightedService{
+		Services: []models.WeightedServiceItem{{Name: models.Service{ID: "api"}, Weight: 1}},
+	}})
+	fs.CreateService(&models.Service{ID: "shadow", Mirroring: &models.MirroringService{Service: models.Service{ID: "split"}}})
+	fs.CreateMiddleware(&models.Middleware{ID: "auth", Type: "basicAuth", Config: map[string]interface{}{}})
+	fs.CreateMiddleware(&models.Middleware{ID: "inner", Type: "chain", Config: map[string]interface{}{"middlewares": []interface{}{"auth"}}})
+	fs.CreateMiddleware(&models.Middleware{ID: "outer", Type: "chain", Config: map[string]interface{}{"middlewares": []interface{}{"inner"}}})
+
+	tests := []struct {
+		name     string
+		apply    func() error
+		expected string
+	}{
+		{
+			name: "Weighted Through Mirroring",
+			apply: func() error {
+				return fs.UpdateService("split", &models.Service{Weighted: &models.WeightedService{
+					Services: []models.WeightedServiceItem{{Name: models.Service{ID: "api"}, Weight: 1}, {Name: models.Service{ID: "shadow"}, Weight: 1}},
+				}})
+			},
+			expected: "weighted.services: reference cycle split -> shadow -> split",
+		},
+		{
+			name: "Failover On Itself",
+			apply: func() error {
+				return fs.CreateService(&models.Service{ID: "ha", Failover: &models.FailoverService{
+					Service: models.Service{ID: "api"}, Fallback: models.Service{ID: "ha"},
+				}})
+			},
+			expected: "failover.fallback: reference cycle ha -> ha",
+		},
+		{
+			name: "Indirect Chain",
+			apply: func() error {
+				return fs.UpdateMiddleware("inner", &models.Middleware{Type: "chain", Config: map[string]interface{}{"middlewares": []interface{}{"auth", "outer"}}})
+			},
+			expected: "config.middlewares: reference cycle inner -> outer -> inner",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.apply()
+			if !IsValidationError(err) || !strings.HasSuffix(err.Error(), tt.expected) {
+				t.Fatalf("Expected a validation error ending in %q, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	// The rejected updates left the references unchanged
+	if service, _ := fs.GetService("split"); len(service.Weighted.Services) != 1 {
+		t.Fatalf("Expected the weighted service to be unchanged, got %+v", service.Weighted)
+	}
+	if err := fs.UpdateService("shadow", &models.Service{Mirroring: &models.MirroringService{
+		Service: models.Service{ID: "split"}, Mirrors: []models.MirrorServiceItem{{Name: models.Service{ID: "api"}, Percent: 10}},
+	}}); err != nil {
+		t.Fatalf("Expected a reference to a shared service not to be a cycle, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultEnvironment is the name of the environment served by the top-level API
+const DefaultEnvironment = "default"
+
+// Environments is a registry of named environments, each backed by its own store
+type Environments struct {
+	mu     sync.RWMutex
+	stores map[string]Store
+}
+
+// NewEnvironments creates a registry containing the default environment
+func NewEnvironments(defaultStore Store) *Environments {
+	return &Environments{
+		stores: map[string]Store{
+			DefaultEnvironment: defaultStore,
+		},
+	}
+}
+
+// Add registers a store for the named environment
+func (e *Environments) Add(name string, s Store) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.stores[name]; ok {
+		return fmt.Errorf("environment %s: %w", name, ErrAlreadyExists)
+	}
+
+	e.stores[name] = s
+	return nil
+}
+
+// Get returns the store of the named environment
+func (e *Environments) Get(name string) (Store, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	s, ok := e.stores[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s, nil
+}
+
+// Names returns the sorted names of all environments
+func (e *Environments) Names() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	names := make([]string, 0, len(e.stores))
+	for name := range e.stores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save persists the data of all environments, returning the first error encountered
+func (e *Environments) Save() error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	var firstErr error
+	for name, s := range e.stores {
+		if err := s.Save(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("environment %s: %w", name, err)
+		}
+	}
+	return firstErr
+}
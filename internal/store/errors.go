@@ -24,6 +24,9 @@ var (
 
 	// ErrInvalidID is returned when a resource ID is invalid
 	ErrInvalidID = errors.New("invalid resource ID")
+
+	// ErrForbidden is returned when an operation reaches resources the caller may not change
+	ErrForbidden = errors.New("resource not allowed")
 )
 
 // DependencyError provides detailed information about dependencies when a resource can't be deleted
@@ -91,21 +94,32 @@ func IsResourceInUse(err error) bool {
 	return errors.Is(err, ErrResourceInUse) || IsDependencyError(err)
 }
 
-// IsDependencyError returns true if the error is a DependencyError
+// IsDependencyError returns true if the error is or wraps a DependencyError
 func IsDependencyError(err error) bool {
-	_, ok := err.(*DependencyError)
-	return ok
+	var depErr *DependencyError
+	return errors.As(err, &depErr)
+}
+
+// IsInvalidID returns true if the error is an ErrInvalidID error
+func IsInvalidID(err error) bool {
+	return errors.Is(err, ErrInvalidID)
+}
+
+// IsForbidden returns true if the error is an ErrForbidden error
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
 }
 
-// IsValidationError returns true if the error is a ValidationError
+// IsValidationError returns true if the error is or wraps a ValidationError
 func IsValidationError(err error) bool {
-	_, ok := err.(*ValidationError)
-	return ok
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
 }
 
-// GetDependencies returns the dependencies if the error is a DependencyError, otherwise returns nil
+// GetDependencies returns the dependencies if the error is or wraps a DependencyError, otherwise returns nil
 func GetDependencies(err error) []Dependency {
-	if depErr, ok := err.(*DependencyError); ok {
+	var depErr *DependencyError
+	if errors.As(err, &depErr) {
 		return depErr.Dependencies
 	}
 	return nil
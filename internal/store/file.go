@@ -6,7 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
+	"github.com/sistemica/traefik-manager/internal/metrics"
 	"github.com/sistemica/traefik-manager/internal/models"
 )
 
@@ -15,6 +17,9 @@ type storeData struct {
 	Middlewares map[string]models.Middleware `json:"middlewares"`
 	Routers     map[string]models.Router     `json:"routers"`
 	Services    map[string]models.Service    `json:"services"`
+	Apps        map[string]appData           `json:"apps,omitempty"`
+	// Version is incremented with every change
+	Version uint64 `json:"version,omitempty"`
 }
 
 // FileStore implements the Store interface with file-based persistence
@@ -24,6 +29,12 @@ type FileStore struct {
 	filePath     string
 	saveDebounce chan struct{}
 	done         chan struct{} //  channel to signal shutdown
+
+	// Change notification, guarded by mu
+	onChange []func(models.ChangeEvent)
+	actor    string
+	// pending holds the change events of the transaction in progress
+	pending []models.ChangeEvent
 }
 
 // NewFileStore creates a new FileStore
@@ -39,6 +50,7 @@ func NewFileStore(filePath string) (*FileStore, error) {
 			Middlewares: make(map[string]models.Middleware),
 			Routers:     make(map[string]models.Router),
 			Services:    make(map[string]models.Service),
+			Apps:        make(map[string]appData),
 		},
 		filePath:     filePath,
 		saveDebounce: make(chan struct{}, 1),
@@ -101,8 +113,16 @@ func (s *FileStore) triggerSave() {
 	}
 }
 
-// save writes the store data to disk
+// save writes the store data to disk and records its latency and outcome
 func (s *FileStore) save() error {
+	start := time.Now()
+	err := s.write()
+	metrics.ObserveStoreSave(filepath.Base(s.filePath), time.Since(start), err)
+	return err
+}
+
+// write marshals the store data and writes it to the store file
+func (s *FileStore) write() error {
 	data, err := json.MarshalIndent(s.data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal store data: %w", err)
@@ -146,6 +166,9 @@ func (s *FileStore) Load() error {
 	if s.data.Services == nil {
 		s.data.Services = make(map[string]models.Service)
 	}
+	if s.data.Apps == nil {
+		s.data.Apps = make(map[string]appData)
+	}
 
 	return nil
 }
@@ -179,11 +202,31 @@ func (s *FileStore) CreateMiddleware(middleware *models.Middleware) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.createMiddleware(middleware)
+}
+
+// createMiddleware is an internal non-locking version of CreateMiddleware
+func (s *FileStore) createMiddleware(middleware *models.Middleware) error {
 	if _, ok := s.data.Middlewares[middleware.ID]; ok {
 		return ErrAlreadyExists
 	}
+	if err := s.checkProviderName(typeMiddleware, middleware.ID); err != nil {
+		return err
+	}
 
+	// Reject references leading back to the middleware through other chained middlewares
+	if err := s.checkCycles(typeMiddleware, middleware.ID, middleware.References()); err != nil {
+		return err
+	}
+
+	// Validate that the chained middlewares and the errors service exist
+	if err := s.checkReferences(typeMiddleware, middleware.ID, middleware.References()); err != nil {
+		return err
+	}
+
+	s.stamp(&middleware.ResourceMeta, nil)
 	s.data.Middlewares[middleware.ID] = *middleware
+	s.notify(models.ChangeCreated, ResourceMiddlewares, middleware.ID)
 	s.triggerSave()
 	return nil
 }
@@ -193,13 +236,37 @@ func (s *FileStore) UpdateMiddleware(id string, middleware *models.Middleware) e
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, ok := s.data.Middlewares[id]; !ok {
+	return s.updateMiddleware(id, middleware)
+}
+
+// updateMiddleware is an internal non-locking version of UpdateMiddleware
+func (s *FileStore) updateMiddleware(id string, middleware *models.Middleware) error {
+	existing, ok := s.data.Middlewares[id]
+	if !ok {
 		return ErrNotFound
 	}
 
 	// Ensure ID doesn't change
 	middleware.ID = id
+
+	// Keep the middleware shared while other namespaces reference it
+	if err := s.checkSharing(typeMiddleware, id, middleware.Shared); err != nil {
+		return err
+	}
+
+	// Reject references leading back to the middleware through other chained middlewares
+	if err := s.checkCycles(typeMiddleware, id, middleware.References()); err != nil {
+		return err
+	}
+
+	// Validate that the chained middlewares and the errors service exist
+	if err := s.checkReferences(typeMiddleware, id, middleware.References()); err != nil {
+		return err
+	}
+
+	s.stamp(&middleware.ResourceMeta, &existing.ResourceMeta)
 	s.data.Middlewares[id] = *middleware
+	s.notify(models.ChangeUpdated, ResourceMiddlewares, id)
 	s.triggerSave()
 	return nil
 }
@@ -209,20 +276,22 @@ func (s *FileStore) DeleteMiddleware(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.deleteMiddleware(id)
+}
+
+// deleteMiddleware is an internal non-locking version of DeleteMiddleware
+func (s *FileStore) deleteMiddleware(id string) error {
 	if _, ok := s.data.Middlewares[id]; !ok {
 		return ErrNotFound
 	}
 
 	// Check if middleware is in use
-	inUse, _, err := s.middlewareInUse(id)
-	if err != nil {
+	if err := s.checkUnused(typeMiddleware, id); err != nil {
 		return err
 	}
-	if inUse {
-		return ErrResourceInUse
-	}
 
 	delete(s.data.Middlewares, id)
+	s.notify(models.ChangeDeleted, ResourceMiddlewares, id)
 	s.triggerSave()
 	return nil
 }
@@ -246,22 +315,7 @@ func (s *FileStore) MiddlewareInUse(id string) (bool, []string, error) {
 
 // middlewareInUse is an internal non-locking version of MiddlewareInUse
 func (s *FileStore) middlewareInUse(id string) (bool, []string, error) {
-	usedBy := []string{}
-
-	// Check if middleware is used by any routers
-	for routerID, router := range s.data.Routers {
-		if router.Middlewares != nil {
-			for _, mw := range router.Middlewares {
-				// FIXED: Instead of calling MiddlewareExists which acquires a lock,
-				// just check the map directly since we already have a lock
-				if mw.ID == id {
-					usedBy = append(usedBy, fmt.Sprintf("router:%s", routerID))
-					break // Found at least one reference in this router
-				}
-			}
-		}
-	}
-
+	usedBy := s.usedBy(typeMiddleware, id)
 	return len(usedBy) > 0, usedBy, nil
 }
 
@@ -294,13 +348,21 @@ func (s *FileStore) CreateRouter(router *models.Router) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.createRouter(router)
+}
+
+// createRouter is an internal non-locking version of CreateRouter
+func (s *FileStore) createRouter(router *models.Router) error {
 	if _, ok := s.data.Routers[router.ID]; ok {
 		return ErrAlreadyExists
 	}
+	if err := s.checkProviderName(typeRouter, router.ID); err != nil {
+		return err
+	}
 
 	// Validate that all referenced services exist
 	if _, ok := s.data.Services[router.Service.ID]; !ok {
-		return fmt.Errorf("service %s not found", router.Service.ID)
+		return NewValidationError(typeRouter, router.ID, "service", fmt.Sprintf("service %s not found", router.Service.ID))
 	}
 
 	// Validate that all referenced middlewares exist - THE IMPORTANT FIX IS HERE
@@ -308,12 +370,14 @@ func (s *FileStore) CreateRouter(router *models.Router) error {
 		for _, mw := range router.Middlewares {
 			// Check map directly instead of calling MiddlewareExists
 			if _, ok := s.data.Middlewares[mw.ID]; !ok {
-				return fmt.Errorf("middleware %s not found", mw.ID)
+				return NewValidationError(typeRouter, router.ID, "middlewares", fmt.Sprintf("middleware %s not found", mw.ID))
 			}
 		}
 	}
 
+	s.stamp(&router.ResourceMeta, nil)
 	s.data.Routers[router.ID] = *router
+	s.notify(models.ChangeCreated, ResourceRouters, router.ID)
 	s.triggerSave()
 	return nil
 }
@@ -323,11 +387,17 @@ func (s *FileStore) DeleteRouter(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.deleteRouter(id)
+}
+
+// deleteRouter is an internal non-locking version of DeleteRouter
+func (s *FileStore) deleteRouter(id string) error {
 	if _, ok := s.data.Routers[id]; !ok {
 		return ErrNotFound
 	}
 
 	delete(s.data.Routers, id)
+	s.notify(models.ChangeDeleted, ResourceRouters, id)
 	s.triggerSave()
 	return nil
 }
@@ -379,11 +449,31 @@ func (s *FileStore) CreateService(service *models.Service) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.createService(service)
+}
+
+// createService is an internal non-locking version of CreateService
+func (s *FileStore) createService(service *models.Service) error {
 	if _, ok := s.data.Services[service.ID]; ok {
 		return ErrAlreadyExists
 	}
+	if err := s.checkProviderName(typeService, service.ID); err != nil {
+		return err
+	}
 
+	// Reject references leading back to the service through other services
+	if err := s.checkCycles(typeService, service.ID, service.References()); err != nil {
+		return err
+	}
+
+	// Validate that the weighted, mirrored and failover services exist
+	if err := s.checkReferences(typeService, service.ID, service.References()); err != nil {
+		return err
+	}
+
+	s.stamp(&service.ResourceMeta, nil)
 	s.data.Services[service.ID] = *service
+	s.notify(models.ChangeCreated, ResourceServices, service.ID)
 	s.triggerSave()
 	return nil
 }
@@ -393,13 +483,37 @@ func (s *FileStore) UpdateService(id string, service *models.Service) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, ok := s.data.Services[id]; !ok {
+	return s.updateService(id, service)
+}
+
+// updateService is an internal non-locking version of UpdateService
+func (s *FileStore) updateService(id string, service *models.Service) error {
+	existing, ok := s.data.Services[id]
+	if !ok {
 		return ErrNotFound
 	}
 
 	// Ensure ID doesn't change
 	service.ID = id
+
+	// Keep the service shared while other namespaces reference it
+	if err := s.checkSharing(typeService, id, service.Shared); err != nil {
+		return err
+	}
+
+	// Reject references leading back to the service through other services
+	if err := s.checkCycles(typeService, id, service.References()); err != nil {
+		return err
+	}
+
+	// Validate that the weighted, mirrored and failover services exist
+	if err := s.checkReferences(typeService, id, service.References()); err != nil {
+		return err
+	}
+
+	s.stamp(&service.ResourceMeta, &existing.ResourceMeta)
 	s.data.Services[id] = *service
+	s.notify(models.ChangeUpdated, ResourceServices, id)
 	s.triggerSave()
 	return nil
 }
@@ -409,20 +523,22 @@ func (s *FileStore) DeleteService(id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.deleteService(id)
+}
+
+// deleteService is an internal non-locking version of DeleteService
+func (s *FileStore) deleteService(id string) error {
 	if _, ok := s.data.Services[id]; !ok {
 		return ErrNotFound
 	}
 
 	// Check if service is in use
-	inUse, usedBy, err := s.serviceInUse(id)
-	if err != nil {
+	if err := s.checkUnused(typeService, id); err != nil {
 		return err
 	}
-	if inUse {
-		return fmt.Errorf("%w: %s", ErrResourceInUse, usedBy)
-	}
 
 	delete(s.data.Services, id)
+	s.notify(models.ChangeDeleted, ResourceServices, id)
 	s.triggerSave()
 	return nil
 }
@@ -446,15 +562,7 @@ func (s *FileStore) ServiceInUse(id string) (bool, []string, error) {
 
 // serviceInUse is an internal non-locking version of ServiceInUse
 func (s *FileStore) serviceInUse(id string) (bool, []string, error) {
-	usedBy := []string{}
-
-	// Check if service is used by any routers
-	for routerID, router := range s.data.Routers {
-		if router.Service.ID == id {
-			usedBy = append(usedBy, fmt.Sprintf("router:%s", routerID))
-		}
-	}
-
+	usedBy := s.usedBy(typeService, id)
 	return len(usedBy) > 0, usedBy, nil
 }
 
@@ -463,6 +571,11 @@ func (s *FileStore) UpdateRouter(id string, router *models.Router) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.updateRouter(id, router)
+}
+
+// updateRouter is an internal non-locking version of UpdateRouter
+func (s *FileStore) updateRouter(id string, router *models.Router) error {
 	// Check if router exists
 	existingRouter, ok := s.data.Routers[id]
 	if !ok {
@@ -472,7 +585,7 @@ func (s *FileStore) UpdateRouter(id string, router *models.Router) error {
 	// Validate that referenced service exists
 	if router.Service.ID != "" {
 		if _, ok := s.data.Services[router.Service.ID]; !ok {
-			return fmt.Errorf("service %s not found", router.Service.ID)
+			return NewValidationError(typeRouter, id, "service", fmt.Sprintf("service %s not found", router.Service.ID))
 		}
 	} else {
 		// If service ID is empty in update, keep the existing one
@@ -483,7 +596,7 @@ func (s *FileStore) UpdateRouter(id string, router *models.Router) error {
 	if router.Middlewares != nil {
 		for _, mw := range router.Middlewares {
 			if _, ok := s.data.Middlewares[mw.ID]; !ok {
-				return fmt.Errorf("middleware %s not found", mw.ID)
+				return NewValidationError(typeRouter, id, "middlewares", fmt.Sprintf("middleware %s not found", mw.ID))
 			}
 		}
 	}
@@ -492,7 +605,9 @@ func (s *FileStore) UpdateRouter(id string, router *models.Router) error {
 	router.ID = id
 
 	// Update router
+	s.stamp(&router.ResourceMeta, &existingRouter.ResourceMeta)
 	s.data.Routers[id] = *router
+	s.notify(models.ChangeUpdated, ResourceRouters, id)
 
 	// Trigger save
 	s.triggerSave()
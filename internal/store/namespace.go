@@ -0,0 +1,905 @@
+package store
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// NamespaceSeparator separates the namespace from the resource ID in qualified IDs
+const NamespaceSeparator = "/"
+
+// QualifiedID returns the store-wide ID of a resource in a namespace
+func QualifiedID(namespace, id string) string {
+	if namespace == "" {
+		return id
+	}
+	return namespace + NamespaceSeparator + id
+}
+
+// SplitQualifiedID splits a store-wide ID into its namespace and local ID
+func SplitQualifiedID(qualifiedID string) (namespace, id string) {
+	if i := strings.Index(qualifiedID, NamespaceSeparator); i >= 0 {
+		return qualifiedID[:i], qualifiedID[i+len(NamespaceSeparator):]
+	}
+	return "", qualifiedID
+}
+
+// ProviderName converts a store-wide resource ID into the name used in Traefik's configuration.
+// Namespaced IDs ("team-a/api") are prefixed with their namespace ("team-a-api").
+func ProviderName(id string) string {
+	return strings.ReplaceAll(id, NamespaceSeparator, "-")
+}
+
+// checkProviderName verifies that no other resource of the type has the same provider name,
+// as they would replace each other in Traefik's configuration. Must be called with the lock held.
+func (s *FileStore) checkProviderName(resourceType, id string) error {
+	var ids []string
+	switch resourceType {
+	case typeRouter:
+		ids = slices.Collect(maps.Keys(s.data.Routers))
+	case typeService:
+		ids = slices.Collect(maps.Keys(s.data.Services))
+	case typeMiddleware:
+		ids = slices.Collect(maps.Keys(s.data.Middlewares))
+	}
+
+	name := ProviderName(id)
+	for _, other := range ids {
+		if other != id && ProviderName(other) == name {
+			return NewValidationError(resourceType, id, "id",
+				fmt.Sprintf("provider name %s is already used by %s %s", name, resourceType, other))
+		}
+	}
+	return nil
+}
+
+// checkSharing verifies that a service or middleware no longer shared isn't referenced by resources
+// of other namespaces, which may only reference shared resources. Must be called with the lock held.
+func (s *FileStore) checkSharing(resourceType, id string, shared bool) error {
+	if shared {
+		return nil
+	}
+	namespace, _ := SplitQualifiedID(id)
+	for _, dependency := range s.dependents(resourceType, id) {
+		if other, _ := SplitQualifiedID(dependency.ID); other != namespace {
+			return NewValidationError(resourceType, id, "shared",
+				fmt.Sprintf("%s %s of another namespace references the %s", dependency.ResourceType, dependency.ID, resourceType))
+		}
+	}
+	return nil
+}
+
+// Namespace returns a view of the store scoped to the given namespace.
+// An empty namespace returns the unscoped store.
+func (s *FileStore) Namespace(name string) Store {
+	if name == "" {
+		return s
+	}
+	return &namespacedStore{fs: s, namespace: name}
+}
+
+// namespacedStore is a view of a FileStore limited to the resources of one namespace.
+//
+// Resources are stored under qualified IDs ("team-a/api"). The view accepts and returns
+// local IDs ("api"). References without a namespace point into the view's own namespace,
+// while qualified references ("team-b/auth") point into another namespace and are only
+// allowed for services and middlewares marked as shared.
+type namespacedStore struct {
+	fs        *FileStore
+	namespace string
+	// actor changes are attributed to
+	actor string
+}
+
+// qualify converts a local ID or reference into a store-wide ID
+func (n *namespacedStore) qualify(id string) string {
+	if strings.Contains(id, NamespaceSeparator) {
+		return id
+	}
+	return QualifiedID(n.namespace, id)
+}
+
+// localize converts a store-wide ID into an ID relative to the view's namespace
+func (n *namespacedStore) localize(id string) string {
+	return strings.TrimPrefix(id, n.namespace+NamespaceSeparator)
+}
+
+// owns returns true if the store-wide ID belongs to the view's namespace
+func (n *namespacedStore) owns(id string) bool {
+	return strings.HasPrefix(id, n.namespace+NamespaceSeparator)
+}
+
+// checkID rejects resource IDs that would escape the namespace
+func checkID(id string) error {
+	if id == "" || strings.Contains(id, NamespaceSeparator) {
+		return fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	return nil
+}
+
+// checkServiceRef verifies that a qualified service reference is allowed.
+// Must be called with the store lock held.
+func (n *namespacedStore) checkServiceRef(resourceType, resourceID, field, ref string) error {
+	if ref == "" || n.owns(ref) {
+		return nil
+	}
+	// A missing service is not shared: it could be created later without being shared
+	if svc, ok := n.fs.data.Services[ref]; !ok || !svc.Shared {
+		return NewValidationError(resourceType, resourceID, field, fmt.Sprintf("service %s is not shared", ref))
+	}
+	return nil
+}
+
+// checkMiddlewareRef verifies that a qualified middleware reference is allowed.
+// Must be called with the store lock held.
+func (n *namespacedStore) checkMiddlewareRef(resourceType, resourceID, field, ref string) error {
+	if ref == "" || n.owns(ref) {
+		return nil
+	}
+	// A missing middleware is not shared: it could be created later without being shared
+	if mw, ok := n.fs.data.Middlewares[ref]; !ok || !mw.Shared {
+		return NewValidationError(resourceType, resourceID, field, fmt.Sprintf("middleware %s is not shared", ref))
+	}
+	return nil
+}
+
+// toStoreRouter converts a router from the view into its stored form and validates cross-namespace references
+func (n *namespacedStore) toStoreRouter(router models.Router) (models.Router, error) {
+	router.ID = n.qualify(router.ID)
+	router.RewriteRefs(n.qualify, n.qualify)
+
+	if err := n.checkServiceRef("router", router.ID, "service", router.Service.ID); err != nil {
+		return router, err
+	}
+	for _, mw := range router.Middlewares {
+		if err := n.checkMiddlewareRef("router", router.ID, "middlewares", mw.ID); err != nil {
+			return router, err
+		}
+	}
+	return router, nil
+}
+
+// toStoreService converts a service from the view into its stored form and validates cross-namespace references
+func (n *namespacedStore) toStoreService(service models.Service) (models.Service, error) {
+	service.ID = n.qualify(service.ID)
+	service.RewriteServiceRefs(n.qualify)
+
+	for _, ref := range service.ServiceRefs() {
+		if err := n.checkServiceRef("service", service.ID, "services", ref); err != nil {
+			return service, err
+		}
+	}
+	return service, nil
+}
+
+// toStoreMiddleware converts a middleware from the view into its stored form and validates cross-namespace references
+func (n *namespacedStore) toStoreMiddleware(middleware models.Middleware) (models.Middleware, error) {
+	middleware.ID = n.qualify(middleware.ID)
+	middleware.RewriteRefs(n.qualify, n.qualify)
+
+	for _, ref := range middleware.MiddlewareRefs() {
+		if err := n.checkMiddlewareRef("middleware", middleware.ID, "middlewares", ref); err != nil {
+			return middleware, err
+		}
+	}
+	for _, ref := range middleware.ServiceRefs() {
+		if err := n.checkServiceRef("middleware", middleware.ID, "service", ref); err != nil {
+			return middleware, err
+		}
+	}
+	return middleware, nil
+}
+
+// fromStoreRouter converts a stored router into its form in the view
+func (n *namespacedStore) fromStoreRouter(router models.Router) models.Router {
+	router.ID = n.localize(router.ID)
+	router.RewriteRefs(n.localize, n.localize)
+	return router
+}
+
+// fromStoreService converts a stored service into its form in the view
+func (n *namespacedStore) fromStoreService(service models.Service) models.Service {
+	service.ID = n.localize(service.ID)
+	service.RewriteServiceRefs(n.localize)
+	return service
+}
+
+// fromStoreMiddleware converts a stored middleware into its form in the view
+func (n *namespacedStore) fromStoreMiddleware(middleware models.Middleware) models.Middleware {
+	middleware.ID = n.localize(middleware.ID)
+	middleware.RewriteRefs(n.localize, n.localize)
+	return middleware
+}
+
+// localizeUsers converts "type:id" dependency descriptions into the view's namespace
+func (n *namespacedStore) localizeUsers(usedBy []string) []string {
+	result := make([]string, len(usedBy))
+	for i, user := range usedBy {
+		if parts := strings.SplitN(user, ":", 2); len(parts) == 2 {
+			result[i] = parts[0] + ":" + n.localize(parts[1])
+		} else {
+			result[i] = user
+		}
+	}
+	return result
+}
+
+// localizeError converts the IDs of a DependencyError into IDs relative to the view's namespace
+func (n *namespacedStore) localizeError(err error) error {
+	depErr, ok := err.(*DependencyError)
+	if !ok {
+		return err
+	}
+	dependencies := make([]Dependency, len(depErr.Dependencies))
+	for i, dependency := range depErr.Dependencies {
+		dependency.ID = n.localize(dependency.ID)
+		dependencies[i] = dependency
+	}
+	return NewDependencyError(depErr.ResourceType, n.localize(depErr.ResourceID), dependencies)
+}
+
+// localAuthorizer returns an authorizer passing the local IDs of the resources of the namespace to the given one
+func (n *namespacedStore) localAuthorizer(authorize Authorizer) Authorizer {
+	if authorize == nil {
+		return nil
+	}
+	return func(ref models.ResourceRef) bool {
+		ref.ID = n.localize(ref.ID)
+		return authorize(ref)
+	}
+}
+
+// deleteWithOptions deletes a resource of the namespace. A cascade reaching resources
+// of other namespaces is refused. Must be called with the lock held.
+func (n *namespacedStore) deleteWithOptions(resourceType, id string, options DeleteOptions) ([]models.ResourceRef, error) {
+	plan, err := n.fs.deletePlan(resourceType, n.qualify(id), options.Cascade)
+	if err != nil {
+		return nil, n.localizeError(err)
+	}
+
+	foreign := []Dependency{}
+	for _, ref := range plan {
+		if !n.owns(ref.ID) {
+			foreign = append(foreign, Dependency{ResourceType: ref.ResourceType, ID: ref.ID})
+		}
+	}
+	if len(foreign) > 0 {
+		return nil, NewDependencyError(resourceType, id, foreign)
+	}
+
+	local := make([]models.ResourceRef, len(plan))
+	for i, ref := range plan {
+		local[i] = models.ResourceRef{ResourceType: ref.ResourceType, ID: n.localize(ref.ID)}
+	}
+	if err := authorizeAll(options.Authorize, local); err != nil {
+		return nil, err
+	}
+
+	if err := n.fs.deleteAll(plan, options.DryRun); err != nil {
+		return nil, n.localizeError(err)
+	}
+	return local, nil
+}
+
+// rename renames a resource of the namespace. A rename rewriting references held by
+// resources of other namespaces is refused. Must be called with the lock held.
+func (n *namespacedStore) rename(resourceType, id, newID string, authorize Authorizer) ([]models.ResourceRef, error) {
+	if !n.fs.exists(resourceType, n.qualify(id)) {
+		return nil, ErrNotFound
+	}
+
+	foreign := []Dependency{}
+	for _, ref := range n.fs.renamePlan(resourceType, n.qualify(id)) {
+		if !n.owns(ref.ID) {
+			foreign = append(foreign, Dependency{ResourceType: ref.ResourceType, ID: ref.ID})
+		}
+	}
+	if len(foreign) > 0 {
+		return nil, NewDependencyError(resourceType, id, foreign)
+	}
+
+	plan, err := n.fs.rename(resourceType, n.qualify(id), n.qualify(newID), n.localAuthorizer(authorize))
+	if err != nil {
+		return nil, n.localizeError(err)
+	}
+	for i := range plan {
+		plan[i].ID = n.localize(plan[i].ID)
+	}
+	return plan, nil
+}
+
+// ListMiddlewares returns all middlewares of the namespace
+func (n *namespacedStore) ListMiddlewares() ([]models.Middleware, error) {
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	middlewares := make([]models.Middleware, 0)
+	for id, middleware := range n.fs.data.Middlewares {
+		if n.owns(id) {
+			middlewares = append(middlewares, n.fromStoreMiddleware(middleware))
+		}
+	}
+	return middlewares, nil
+}
+
+// GetMiddleware returns a middleware of the namespace by ID
+func (n *namespacedStore) GetMiddleware(id string) (*models.Middleware, error) {
+	if err := checkID(id); err != nil {
+		return nil, ErrNotFound
+	}
+
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	middleware, ok := n.fs.data.Middlewares[n.qualify(id)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	middleware = n.fromStoreMiddleware(middleware)
+	return &middleware, nil
+}
+
+// CreateMiddleware creates a new middleware in the namespace
+func (n *namespacedStore) CreateMiddleware(middleware *models.Middleware) error {
+	if err := checkID(middleware.ID); err != nil {
+		return err
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	stored, err := n.toStoreMiddleware(*middleware)
+	if err != nil {
+		return err
+	}
+	return n.fs.createMiddleware(&stored)
+}
+
+// UpdateMiddleware updates an existing middleware in the namespace
+func (n *namespacedStore) UpdateMiddleware(id string, middleware *models.Middleware) error {
+	if err := checkID(id); err != nil {
+		return ErrNotFound
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	middleware.ID = id
+	stored, err := n.toStoreMiddleware(*middleware)
+	if err != nil {
+		return err
+	}
+	return n.fs.updateMiddleware(stored.ID, &stored)
+}
+
+// DeleteMiddleware deletes a middleware from the namespace
+func (n *namespacedStore) DeleteMiddleware(id string) error {
+	if err := checkID(id); err != nil {
+		return ErrNotFound
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	return n.localizeError(n.fs.deleteMiddleware(n.qualify(id)))
+}
+
+// DeleteMiddlewareWithOptions deletes a middleware from the namespace, with cascade along with its dependents
+func (n *namespacedStore) DeleteMiddlewareWithOptions(id string, options DeleteOptions) ([]models.ResourceRef, error) {
+	if err := checkID(id); err != nil {
+		return nil, ErrNotFound
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	return n.deleteWithOptions(typeMiddleware, id, options)
+}
+
+// RenameMiddleware renames a middleware of the namespace and rewrites the references to it
+func (n *namespacedStore) RenameMiddleware(id, newID string, authorize Authorizer) ([]models.ResourceRef, error) {
+	if err := checkID(id); err != nil {
+		return nil, ErrNotFound
+	}
+	if err := checkID(newID); err != nil {
+		return nil, err
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	return n.rename(typeMiddleware, id, newID, authorize)
+}
+
+// MiddlewareExists checks if a middleware exists in the namespace.
+// Qualified IDs of shared middlewares in other namespaces are resolved as well.
+func (n *namespacedStore) MiddlewareExists(id string) (bool, error) {
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	_, ok := n.fs.data.Middlewares[n.qualify(id)]
+	return ok, nil
+}
+
+// MiddlewareInUse checks if a middleware of the namespace is in use
+func (n *namespacedStore) MiddlewareInUse(id string) (bool, []string, error) {
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	inUse, usedBy, err := n.fs.middlewareInUse(n.qualify(id))
+	return inUse, n.localizeUsers(usedBy), err
+}
+
+// ListRouters returns all routers of the namespace
+func (n *namespacedStore) ListRouters() ([]models.Router, error) {
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	routers := make([]models.Router, 0)
+	for id, router := range n.fs.data.Routers {
+		if n.owns(id) {
+			routers = append(routers, n.fromStoreRouter(router))
+		}
+	}
+	return routers, nil
+}
+
+// GetRouter returns a router of the namespace by ID
+func (n *namespacedStore) GetRouter(id string) (*models.Router, error) {
+	if err := checkID(id); err != nil {
+		return nil, ErrNotFound
+	}
+
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	router, ok := n.fs.data.Routers[n.qualify(id)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	router = n.fromStoreRouter(router)
+	return &router, nil
+}
+
+// CreateRouter creates a new router in the namespace
+func (n *namespacedStore) CreateRouter(router *models.Router) error {
+	if err := checkID(router.ID); err != nil {
+		return err
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	stored, err := n.toStoreRouter(*router)
+	if err != nil {
+		return err
+	}
+	return n.fs.createRouter(&stored)
+}
+
+// UpdateRouter updates an existing router in the namespace
+func (n *namespacedStore) UpdateRouter(id string, router *models.Router) error {
+	if err := checkID(id); err != nil {
+		return ErrNotFound
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	router.ID = id
+	stored, err := n.toStoreRouter(*router)
+	if err != nil {
+		return err
+	}
+	return n.fs.updateRouter(stored.ID, &stored)
+}
+
+// DeleteRouter deletes a router from the namespace
+func (n *namespacedStore) DeleteRouter(id string) error {
+	if err := checkID(id); err != nil {
+		return ErrNotFound
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	return n.fs.deleteRouter(n.qualify(id))
+}
+
+// RouterExists checks if a router exists in the namespace
+func (n *namespacedStore) RouterExists(id string) (bool, error) {
+	if err := checkID(id); err != nil {
+		return false, nil
+	}
+
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	_, ok := n.fs.data.Routers[n.qualify(id)]
+	return ok, nil
+}
+
+// RouterInUse checks if a router is in use
+func (n *namespacedStore) RouterInUse(id string) (bool, []string, error) {
+	// Routers are standalone entities and not referenced by other resources
+	return false, nil, nil
+}
+
+// ListServices returns all services of the namespace
+func (n *namespacedStore) ListServices() ([]models.Service, error) {
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	services := make([]models.Service, 0)
+	for id, service := range n.fs.data.Services {
+		if n.owns(id) {
+			services = append(services, n.fromStoreService(service))
+		}
+	}
+	return services, nil
+}
+
+// GetService returns a service of the namespace by ID
+func (n *namespacedStore) GetService(id string) (*models.Service, error) {
+	if err := checkID(id); err != nil {
+		return nil, ErrNotFound
+	}
+
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	service, ok := n.fs.data.Services[n.qualify(id)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	service = n.fromStoreService(service)
+	return &service, nil
+}
+
+// CreateService creates a new service in the namespace
+func (n *namespacedStore) CreateService(service *models.Service) error {
+	if err := checkID(service.ID); err != nil {
+		return err
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	stored, err := n.toStoreService(*service)
+	if err != nil {
+		return err
+	}
+	return n.fs.createService(&stored)
+}
+
+// UpdateService updates an existing service in the namespace
+func (n *namespacedStore) UpdateService(id string, service *models.Service) error {
+	if err := checkID(id); err != nil {
+		return ErrNotFound
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	service.ID = id
+	stored, err := n.toStoreService(*service)
+	if err != nil {
+		return err
+	}
+	return n.fs.updateService(stored.ID, &stored)
+}
+
+// DeleteService deletes a service from the namespace
+func (n *namespacedStore) DeleteService(id string) error {
+	if err := checkID(id); err != nil {
+		return ErrNotFound
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	return n.localizeError(n.fs.deleteService(n.qualify(id)))
+}
+
+// DeleteServiceWithOptions deletes a service from the namespace, with cascade along with its dependents
+func (n *namespacedStore) DeleteServiceWithOptions(id string, options DeleteOptions) ([]models.ResourceRef, error) {
+	if err := checkID(id); err != nil {
+		return nil, ErrNotFound
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	return n.deleteWithOptions(typeService, id, options)
+}
+
+// RenameService renames a service of the namespace and rewrites the references to it
+func (n *namespacedStore) RenameService(id, newID string, authorize Authorizer) ([]models.ResourceRef, error) {
+	if err := checkID(id); err != nil {
+		return nil, ErrNotFound
+	}
+	if err := checkID(newID); err != nil {
+		return nil, err
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	return n.rename(typeService, id, newID, authorize)
+}
+
+// ServiceExists checks if a service exists in the namespace.
+// Qualified IDs of shared services in other namespaces are resolved as well.
+func (n *namespacedStore) ServiceExists(id string) (bool, error) {
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	_, ok := n.fs.data.Services[n.qualify(id)]
+	return ok, nil
+}
+
+// ServiceInUse checks if a service of the namespace is in use
+func (n *namespacedStore) ServiceInUse(id string) (bool, []string, error) {
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	inUse, usedBy, err := n.fs.serviceInUse(n.qualify(id))
+	return inUse, n.localizeUsers(usedBy), err
+}
+
+// fromStoreApp converts a stored app into its form in the view
+func (n *namespacedStore) fromStoreApp(app models.App) models.App {
+	app.ID = n.localize(app.ID)
+	for i := range app.Routers {
+		app.Routers[i] = n.fromStoreRouter(app.Routers[i])
+	}
+	for i := range app.Services {
+		app.Services[i] = n.fromStoreService(app.Services[i])
+	}
+	for i := range app.Middlewares {
+		app.Middlewares[i] = n.fromStoreMiddleware(app.Middlewares[i])
+	}
+	return app
+}
+
+// localizeIDs converts store-wide IDs into IDs relative to the view's namespace
+func (n *namespacedStore) localizeIDs(ids []string) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = n.localize(id)
+	}
+	return result
+}
+
+// ListApps returns all apps of the namespace
+func (n *namespacedStore) ListApps() ([]models.App, error) {
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	apps := make([]models.App, 0)
+	for id, record := range n.fs.data.Apps {
+		if n.owns(id) {
+			apps = append(apps, n.fromStoreApp(n.fs.resolveApp(record)))
+		}
+	}
+	return apps, nil
+}
+
+// GetApp returns an app of the namespace by ID
+func (n *namespacedStore) GetApp(id string) (*models.App, error) {
+	if err := checkID(id); err != nil {
+		return nil, ErrNotFound
+	}
+
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	record, ok := n.fs.data.Apps[n.qualify(id)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	app := n.fromStoreApp(n.fs.resolveApp(record))
+	return &app, nil
+}
+
+// ApplyBatch creates and updates resources of the namespace in one transaction
+func (n *namespacedStore) ApplyBatch(batch *Batch) error {
+	for _, router := range batch.Routers {
+		if err := checkID(router.ID); err != nil {
+			return err
+		}
+	}
+	for _, service := range batch.Services {
+		if err := checkID(service.ID); err != nil {
+			return err
+		}
+	}
+	for _, middleware := range batch.Middlewares {
+		if err := checkID(middleware.ID); err != nil {
+			return err
+		}
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	stored := &Batch{
+		Routers:     make([]models.Router, len(batch.Routers)),
+		Services:    make([]models.Service, len(batch.Services)),
+		Middlewares: make([]models.Middleware, len(batch.Middlewares)),
+		Updates:     make(map[models.ResourceRef]bool, len(batch.Updates)),
+	}
+	var err error
+	for i, router := range batch.Routers {
+		if stored.Routers[i], err = n.toStoreRouter(router); err != nil {
+			return err
+		}
+	}
+	for i, service := range batch.Services {
+		if stored.Services[i], err = n.toStoreService(service); err != nil {
+			return err
+		}
+	}
+	for i, middleware := range batch.Middlewares {
+		if stored.Middlewares[i], err = n.toStoreMiddleware(middleware); err != nil {
+			return err
+		}
+	}
+	for ref, update := range batch.Updates {
+		stored.Updates[models.ResourceRef{ResourceType: ref.ResourceType, ID: n.qualify(ref.ID)}] = update
+	}
+
+	return n.fs.applyBatch(stored)
+}
+
+// CreateApp creates an app and all its components in the namespace
+func (n *namespacedStore) CreateApp(app *models.App) error {
+	if err := checkID(app.ID); err != nil {
+		return err
+	}
+	for _, router := range app.Routers {
+		if err := checkID(router.ID); err != nil {
+			return err
+		}
+	}
+	for _, service := range app.Services {
+		if err := checkID(service.ID); err != nil {
+			return err
+		}
+	}
+	for _, middleware := range app.Middlewares {
+		if err := checkID(middleware.ID); err != nil {
+			return err
+		}
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	stored := models.App{
+		ID:          n.qualify(app.ID),
+		Description: app.Description,
+		Routers:     make([]models.Router, len(app.Routers)),
+		Services:    make([]models.Service, len(app.Services)),
+		Middlewares: make([]models.Middleware, len(app.Middlewares)),
+	}
+	var err error
+	for i, router := range app.Routers {
+		if stored.Routers[i], err = n.toStoreRouter(router); err != nil {
+			return err
+		}
+	}
+	for i, service := range app.Services {
+		if stored.Services[i], err = n.toStoreService(service); err != nil {
+			return err
+		}
+	}
+	for i, middleware := range app.Middlewares {
+		if stored.Middlewares[i], err = n.toStoreMiddleware(middleware); err != nil {
+			return err
+		}
+	}
+
+	if err := n.fs.createApp(&stored); err != nil {
+		return err
+	}
+	app.ResourceMeta = stored.ResourceMeta
+	return nil
+}
+
+// DeleteApp deletes an app of the namespace and its unshared components
+func (n *namespacedStore) DeleteApp(id string) (*models.AppDeleteResponse, error) {
+	if err := checkID(id); err != nil {
+		return nil, ErrNotFound
+	}
+
+	n.fs.mu.Lock()
+	defer n.fs.mu.Unlock()
+	defer n.fs.attribute(n.actor)()
+
+	response, err := n.fs.deleteApp(n.qualify(id))
+	if err != nil {
+		return nil, err
+	}
+	response.ID = id
+	for _, components := range []*models.AppComponents{&response.Deleted, &response.Kept} {
+		components.Routers = n.localizeIDs(components.Routers)
+		components.Services = n.localizeIDs(components.Services)
+		components.Middlewares = n.localizeIDs(components.Middlewares)
+	}
+	return response, nil
+}
+
+// Graph returns the dependency graph of the namespace. Resources of other namespaces
+// referenced by the namespace are included with their qualified IDs.
+func (n *namespacedStore) Graph() (*models.Graph, error) {
+	n.fs.mu.RLock()
+	defer n.fs.mu.RUnlock()
+
+	localize := func(ref models.ResourceRef) models.ResourceRef {
+		return models.ResourceRef{ResourceType: ref.ResourceType, ID: n.localize(ref.ID)}
+	}
+
+	full := n.fs.graph()
+	graph := &models.Graph{
+		Nodes: []models.ResourceRef{},
+		Edges: []models.GraphEdge{},
+	}
+	referenced := make(map[models.ResourceRef]bool)
+	for _, edge := range full.Edges {
+		if n.owns(edge.From.ID) {
+			referenced[edge.To] = true
+			graph.Edges = append(graph.Edges, models.GraphEdge{From: localize(edge.From), To: localize(edge.To), Field: edge.Field})
+		}
+	}
+	for _, node := range full.Nodes {
+		if n.owns(node.ID) || referenced[node] {
+			graph.Nodes = append(graph.Nodes, localize(node))
+		}
+	}
+
+	graph.Sort()
+	return graph, nil
+}
+
+// Namespace returns a view of the underlying store scoped to another namespace
+func (n *namespacedStore) Namespace(name string) Store {
+	return n.fs.WithActor(n.actor).Namespace(name)
+}
+
+// WithActor returns a view of the namespace that attributes changes to the actor
+func (n *namespacedStore) WithActor(actor string) Store {
+	return &namespacedStore{fs: n.fs, namespace: n.namespace, actor: actor}
+}
+
+// Save persists the underlying store data to disk
+func (n *namespacedStore) Save() error {
+	return n.fs.Save()
+}
+
+// Load loads the underlying store data from disk
+func (n *namespacedStore) Load() error {
+	return n.fs.Load()
+}
+
+// Close is a no-op, the underlying store is owned by its creator
+func (n *namespacedStore) Close() {}
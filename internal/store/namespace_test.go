@@ -0,0 +1,156 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+func TestNamespacedStore(t *testing.T) {
+	fs, err := NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	defer fs.Close()
+
+	teamA := fs.Namespace("team-a")
+	teamB := fs.Namespace("team-b")
+
+	// Same local IDs in two namespaces
+	for _, ns := range []Store{teamA, teamB} {
+		if err := ns.CreateService(&models.Service{ID: "api", URL: "http://api:8080"}); err != nil {
+			t.Fatalf("Failed to create service: %v", err)
+		}
+		if err := ns.CreateRouter(&models.Router{ID: "api", Rule: "Host(`api`)", Service: models.Service{ID: "api"}}); err != nil {
+			t.Fatalf("Failed to create router: %v", err)
+		}
+	}
+
+	t.Run("Isolation", func(t *testing.T) {
+		routers, err := teamA.ListRouters()
+		if err != nil {
+			t.Fatalf("Failed to list routers: %v", err)
+		}
+		if len(routers) != 1 || routers[0].ID != "api" || routers[0].Service.ID != "api" {
+			t.Fatalf("Expected one local router 'api', got %+v", routers)
+		}
+
+		// The unscoped store sees qualified IDs
+		router, err := fs.GetRouter("team-b/api")
+		if err != nil {
+			t.Fatalf("Failed to get qualified router: %v", err)
+		}
+		if router.Service.ID != "team-b/api" {
+			t.Fatalf("Expected qualified service reference, got '%s'", router.Service.ID)
+		}
+
+		if _, err := teamA.GetRouter("team-b/api"); !IsNotFound(err) {
+			t.Fatalf("Expected not found for another namespace's router, got %v", err)
+		}
+
+		if err := teamA.CreateRouter(&models.Router{ID: "team-b/evil", Rule: "Host(`x`)", Service: models.Service{ID: "api"}}); !IsInvalidID(err) {
+			t.Fatalf("Expected invalid ID error, got %v", err)
+		}
+	})
+
+	t.Run("Shared References", func(t *testing.T) {
+		router := &models.Router{ID: "cross", Rule: "Host(`cross`)", Service: models.Service{ID: "team-b/api"}}
+		if err := teamA.CreateRouter(router); !IsValidationError(err) {
+			t.Fatalf("Expected validation error for unshared service, got %v", err)
+		}
+
+		if err := teamB.UpdateService("api", &models.Service{URL: "http://api:8080", Shared: true}); err != nil {
+			t.Fatalf("Failed to share service: %v", err)
+		}
+
+		if err := teamA.CreateRouter(router); err != nil {
+			t.Fatalf("Failed to create router referencing shared service: %v", err)
+		}
+
+		stored, err := teamA.GetRouter("cross")
+		if err != nil {
+			t.Fatalf("Failed to get router: %v", err)
+		}
+		if stored.Service.ID != "team-b/api" {
+			t.Fatalf("Expected cross-namespace reference to stay qualified, got '%s'", stored.Service.ID)
+		}
+
+		// The shared service is now in use by another namespace
+		if err := teamB.DeleteRouter("api"); err != nil {
+			t.Fatalf("Failed to delete router: %v", err)
+		}
+		if err := teamB.DeleteService("api"); !IsResourceInUse(err) {
+			t.Fatalf("Expected resource in use error, got %v", err)
+		}
+
+		// It can't stop being shared either
+		if err := teamB.UpdateService("api", &models.Service{URL: "http://api:8080"}); !IsValidationError(err) {
+			t.Fatalf("Expected validation error for unsharing a referenced service, got %v", err)
+		}
+		if service, _ := fs.GetService("team-b/api"); !service.Shared {
+			t.Fatal("Expected the service to stay shared")
+		}
+	})
+
+	t.Run("Missing Resources Of Other Namespaces", func(t *testing.T) {
+		// A reference to a missing resource could resolve to a resource created later without sharing
+		router := &models.Router{ID: "later", Rule: "Host(`later`)", Service: models.Service{ID: "team-b/later"}}
+		if err := teamA.CreateRouter(router); !IsValidationError(err) {
+			t.Fatalf("Expected validation error for a missing service, got %v", err)
+		}
+		chain := &models.Middleware{ID: "chain", Type: "chain", Config: map[string]interface{}{"middlewares": []interface{}{"team-b/later"}}}
+		if err := teamA.CreateMiddleware(chain); !IsValidationError(err) {
+			t.Fatalf("Expected validation error for a missing middleware, got %v", err)
+		}
+	})
+}
+
+func TestProviderNameCollisions(t *testing.T) {
+	fs, err := NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	defer fs.Close()
+
+	if err := fs.Namespace("team-a").CreateService(&models.Service{ID: "api", URL: "http://a:8080"}); err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	if err := fs.Namespace("a-b").CreateMiddleware(&models.Middleware{ID: "c", Type: "basicAuth"}); err != nil {
+		t.Fatalf("Failed to create middleware: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		create func() error
+	}{
+		{"Root ID Taking A Namespaced Name", func() error {
+			return fs.CreateService(&models.Service{ID: "team-a-api", URL: "http://root:8080"})
+		}},
+		{"Namespaced IDs Flattened Alike", func() error {
+			return fs.Namespace("a").CreateMiddleware(&models.Middleware{ID: "b-c", Type: "basicAuth"})
+		}},
+		{"Rename Into A Taken Name", func() error {
+			fs.CreateService(&models.Service{ID: "other", URL: "http://other:8080"})
+			_, err := fs.RenameService("other", "team-a-api", nil)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.create(); !IsValidationError(err) {
+				t.Fatalf("Expected a validation error, got %v", err)
+			}
+		})
+	}
+
+	if service, err := fs.GetService("team-a/api"); err != nil || service.URL != "http://a:8080" {
+		t.Fatalf("Expected the namespaced service to be kept, got %+v, %v", service, err)
+	}
+
+	// Names only need to be unique per resource type
+	if err := fs.CreateRouter(&models.Router{ID: "team-a-api", Rule: "Host(`api`)", Service: models.Service{ID: "team-a/api"}}); err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+}
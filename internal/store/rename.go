@@ -0,0 +1,170 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// RenameService changes the ID of a service and rewrites the references of the routers, services,
+// middlewares and apps using it. It returns the resources whose references were rewritten.
+// The optional authorizer must allow the new ID and every resource to rewrite.
+func (s *FileStore) RenameService(id, newID string, authorize Authorizer) ([]models.ResourceRef, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rename(typeService, id, newID, authorize)
+}
+
+// RenameMiddleware changes the ID of a middleware and rewrites the references of the routers,
+// chain middlewares and apps using it. It returns the resources whose references were rewritten.
+// The optional authorizer must allow the new ID and every resource to rewrite.
+func (s *FileStore) RenameMiddleware(id, newID string, authorize Authorizer) ([]models.ResourceRef, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rename(typeMiddleware, id, newID, authorize)
+}
+
+// renamePlan returns the resources referencing the given resource, i.e. whose references
+// a rename rewrites, sorted by type and ID. Must be called with the lock held.
+func (s *FileStore) renamePlan(resourceType, id string) []models.ResourceRef {
+	plan := []models.ResourceRef{}
+	for _, dependency := range s.dependents(resourceType, id) {
+		ref := models.ResourceRef{ResourceType: dependency.ResourceType, ID: dependency.ID}
+		if !slices.Contains(plan, ref) {
+			plan = append(plan, ref)
+		}
+	}
+
+	apps := []string{}
+	for appID, record := range s.data.Apps {
+		if slices.Contains(record.Services, id) && resourceType == typeService ||
+			slices.Contains(record.Middlewares, id) && resourceType == typeMiddleware {
+			apps = append(apps, appID)
+		}
+	}
+	slices.Sort(apps)
+	for _, appID := range apps {
+		plan = append(plan, models.ResourceRef{ResourceType: typeApp, ID: appID})
+	}
+	return plan
+}
+
+// rename is an internal non-locking version of the Rename methods. The resource is stored
+// under its new ID first so that the rewritten references resolve, and the old one is
+// deleted last, once nothing references it anymore.
+func (s *FileStore) rename(resourceType, id, newID string, authorize Authorizer) ([]models.ResourceRef, error) {
+	if !s.exists(resourceType, id) {
+		return nil, ErrNotFound
+	}
+	// The new ID follows the rules of namespaced IDs and keeps the resource in its namespace
+	namespace, _ := SplitQualifiedID(id)
+	local, ok := strings.CutPrefix(newID, QualifiedID(namespace, ""))
+	if !ok || checkID(local) != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidID, newID)
+	}
+	if s.exists(resourceType, newID) {
+		return nil, ErrAlreadyExists
+	}
+	if err := s.checkProviderName(resourceType, newID); err != nil {
+		return nil, err
+	}
+
+	plan := s.renamePlan(resourceType, id)
+	renamed := models.ResourceRef{ResourceType: resourceType, ID: newID}
+	if err := authorizeAll(authorize, append([]models.ResourceRef{renamed}, plan...)); err != nil {
+		return nil, err
+	}
+
+	renameRef := func(refType string) func(ref string) string {
+		return func(ref string) string {
+			if refType == resourceType && ref == id {
+				return newID
+			}
+			return ref
+		}
+	}
+	serviceID, middlewareID := renameRef(typeService), renameRef(typeMiddleware)
+
+	err := s.transaction(func() error {
+		switch resourceType {
+		case typeService:
+			service := s.data.Services[id]
+			existing := service.ResourceMeta
+			service.ID = newID
+			s.stamp(&service.ResourceMeta, &existing)
+			s.data.Services[newID] = service
+			s.notify(models.ChangeCreated, ResourceServices, newID)
+		case typeMiddleware:
+			middleware := s.data.Middlewares[id]
+			existing := middleware.ResourceMeta
+			middleware.ID = newID
+			s.stamp(&middleware.ResourceMeta, &existing)
+			s.data.Middlewares[newID] = middleware
+			s.notify(models.ChangeCreated, ResourceMiddlewares, newID)
+		}
+
+		for _, ref := range plan {
+			var err error
+			switch ref.ResourceType {
+			case typeRouter:
+				router := s.data.Routers[ref.ID]
+				router.RewriteRefs(serviceID, middlewareID)
+				err = s.updateRouter(ref.ID, &router)
+			case typeService:
+				service := s.data.Services[ref.ID]
+				service.RewriteServiceRefs(serviceID)
+				err = s.updateService(ref.ID, &service)
+			case typeMiddleware:
+				middleware := s.data.Middlewares[ref.ID]
+				middleware.RewriteRefs(middlewareID, serviceID)
+				err = s.updateMiddleware(ref.ID, &middleware)
+			case typeApp:
+				s.renameAppComponent(ref.ID, resourceType, id, newID)
+			}
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", ref.ResourceType, ref.ID, err)
+			}
+		}
+
+		if resourceType == typeService {
+			return s.deleteService(id)
+		}
+		return s.deleteMiddleware(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// renameAppComponent replaces the ID of a renamed service or middleware in the components of an app.
+// Must be called with the lock held.
+func (s *FileStore) renameAppComponent(appID, resourceType, id, newID string) {
+	record := s.data.Apps[appID]
+	components := record.Services
+	if resourceType == typeMiddleware {
+		components = record.Middlewares
+	}
+
+	renamed := make([]string, len(components))
+	for i, component := range components {
+		if component == id {
+			component = newID
+		}
+		renamed[i] = component
+	}
+	if resourceType == typeMiddleware {
+		record.Middlewares = renamed
+	} else {
+		record.Services = renamed
+	}
+
+	existing := record.ResourceMeta
+	s.stamp(&record.ResourceMeta, &existing)
+	s.data.Apps[appID] = record
+	s.notify(models.ChangeUpdated, ResourceApps, appID)
+}
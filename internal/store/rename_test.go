@@ -0,0 +1,135 @@
+package store
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+func TestRename(t *testing.T) {
+	fs, err := NewFileStore(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+	defer fs.Close()
+
+	err = fs.CreateApp(&models.App{
+		ID:       "shop",
+		Services: []models.Service{{ID: "api", URL: "http://api:8080"}},
+		Middlewares: []models.Middleware{
+			{ID: "auth", Type: "basicAuth", Config: map[string]interface{}{}},
+			{ID: "errors", Type: "errors", Config: map[string]interface{}{"service": "api"}},
+		},
+		Routers: []models.Router{{ID: "shop", Rule: "Host(`shop.example.com`)", Service: models.Service{ID: "api"}, Middlewares: []models.Middleware{{ID: "auth"}}}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create app: %v", err)
+	}
+	fs.CreateService(&models.Service{ID: "split", Weighted: &models.WeightedService{
+		Services: []models.WeightedServiceItem{{Name: models.Service{ID: "api"}, Weight: 1}},
+	}})
+	fs.CreateMiddleware(&models.Middleware{ID: "secure", Type: "chain", Config: map[string]interface{}{"middlewares": []interface{}{"auth"}}})
+	original, _ := fs.GetService("api")
+
+	var events []models.ChangeEvent
+	fs.OnChange(func(event models.ChangeEvent) {
+		events = append(events, event)
+	})
+
+	t.Run("Service", func(t *testing.T) {
+		updated, err := fs.WithActor("alice").RenameService("api", "backend", nil)
+		if err != nil {
+			t.Fatalf("Failed to rename service: %v", err)
+		}
+		if fmt.Sprint(updated) != "[{middleware errors} {router shop} {service split} {app shop}]" {
+			t.Fatalf("Expected the referencing resources, got %+v", updated)
+		}
+
+		if exists, _ := fs.ServiceExists("api"); exists {
+			t.Fatal("Expected the old service to be gone")
+		}
+		service, err := fs.GetService("backend")
+		if err != nil || service.URL != "http://api:8080" || !service.CreatedAt.Equal(*original.CreatedAt) || service.UpdatedBy != "alice" {
+			t.Fatalf("Expected the service under its new ID with its creation kept, got %+v, %v", service, err)
+		}
+		if router, _ := fs.GetRouter("shop"); router.Service.ID != "backend" {
+			t.Fatalf("Expected the router to be rewritten, got %+v", router.Service)
+		}
+		if split, _ := fs.GetService("split"); split.Weighted.Services[0].Name.ID != "backend" {
+			t.Fatalf("Expected the weighted service to be rewritten, got %+v", split.Weighted)
+		}
+		if errorsMiddleware, _ := fs.GetMiddleware("errors"); fmt.Sprint(errorsMiddleware.ServiceRefs()) != "[backend]" {
+			t.Fatalf("Expected the errors middleware to be rewritten, got %+v", errorsMiddleware.Config)
+		}
+		if app, _ := fs.GetApp("shop"); len(app.Services) != 1 || app.Services[0].ID != "backend" {
+			t.Fatalf("Expected the app to keep its service, got %+v", app.Services)
+		}
+
+		if len(events) != 6 || events[0].Type != ResourceServices || events[0].ID != "backend" || events[5].Action != models.ChangeDeleted {
+			t.Fatalf("Expected the creation, the rewrites and the deletion, got %+v", events)
+		}
+	})
+
+	t.Run("Middleware", func(t *testing.T) {
+		updated, err := fs.RenameMiddleware("auth", "basic-auth", nil)
+		if err != nil {
+			t.Fatalf("Failed to rename middleware: %v", err)
+		}
+		if fmt.Sprint(updated) != "[{middleware secure} {router shop} {app shop}]" {
+			t.Fatalf("Expected the referencing resources, got %+v", updated)
+		}
+		if secure, _ := fs.GetMiddleware("secure"); fmt.Sprint(secure.MiddlewareRefs()) != "[basic-auth]" {
+			t.Fatalf("Expected the chain to be rewritten, got %+v", secure.Config)
+		}
+		if router, _ := fs.GetRouter("shop"); router.Middlewares[0].ID != "basic-auth" {
+			t.Fatalf("Expected the router to be rewritten, got %+v", router.Middlewares)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := fs.RenameService("missing", "other", nil); !IsNotFound(err) {
+			t.Fatalf("Expected not found, got %v", err)
+		}
+		if _, err := fs.RenameService("backend", "split", nil); !IsAlreadyExists(err) {
+			t.Fatalf("Expected already exists, got %v", err)
+		}
+		for _, newID := range []string{"", "team-a/secure", "/secure"} {
+			if _, err := fs.RenameMiddleware("secure", newID, nil); !IsInvalidID(err) {
+				t.Fatalf("Expected %q to be an invalid ID, got %v", newID, err)
+			}
+		}
+
+		// The router referencing the service must be allowed as well
+		servicesOnly := func(ref models.ResourceRef) bool { return ref.ResourceType == typeService }
+		if _, err := fs.RenameService("backend", "renamed", servicesOnly); !IsForbidden(err) {
+			t.Fatalf("Expected the rename to be forbidden, got %v", err)
+		}
+		if exists, _ := fs.ServiceExists("backend"); !exists {
+			t.Fatal("Expected the service to keep its ID")
+		}
+	})
+
+	t.Run("Namespaces", func(t *testing.T) {
+		teamA, teamB := fs.Namespace("team-a"), fs.Namespace("team-b")
+		teamA.CreateService(&models.Service{ID: "shared", URL: "http://shared:8080", Shared: true})
+		teamA.CreateRouter(&models.Router{ID: "web", Rule: "Host(`a.example.com`)", Service: models.Service{ID: "shared"}})
+		teamB.CreateRouter(&models.Router{ID: "web", Rule: "Host(`b.example.com`)", Service: models.Service{ID: "team-a/shared"}})
+
+		// References held by other namespaces are not rewritten
+		_, err := teamA.RenameService("shared", "common", nil)
+		if fmt.Sprint(GetDependencies(err)) != "[{router team-b/web }]" {
+			t.Fatalf("Expected the rename to be refused, got %v", err)
+		}
+
+		teamB.DeleteRouter("web")
+		updated, err := teamA.RenameService("shared", "common", nil)
+		if err != nil || fmt.Sprint(updated) != "[{router web}]" {
+			t.Fatalf("Expected local IDs of the updated resources, got %+v, %v", updated, err)
+		}
+		if router, _ := fs.GetRouter("team-a/web"); router.Service.ID != "team-a/common" {
+			t.Fatalf("Expected the qualified reference to be rewritten, got %+v", router.Service)
+		}
+	})
+}
@@ -12,6 +12,8 @@ type Store interface {
 	CreateMiddleware(middleware *models.Middleware) error
 	UpdateMiddleware(id string, middleware *models.Middleware) error
 	DeleteMiddleware(id string) error
+	DeleteMiddlewareWithOptions(id string, options DeleteOptions) ([]models.ResourceRef, error)
+	RenameMiddleware(id, newID string, authorize Authorizer) ([]models.ResourceRef, error)
 	MiddlewareExists(id string) (bool, error)
 	MiddlewareInUse(id string) (bool, []string, error)
 
@@ -30,9 +32,31 @@ type Store interface {
 	CreateService(service *models.Service) error
 	UpdateService(id string, service *models.Service) error
 	DeleteService(id string) error
+	DeleteServiceWithOptions(id string, options DeleteOptions) ([]models.ResourceRef, error)
+	RenameService(id, newID string, authorize Authorizer) ([]models.ResourceRef, error)
 	ServiceExists(id string) (bool, error)
 	ServiceInUse(id string) (bool, []string, error)
 
+	// Apps
+	ListApps() ([]models.App, error)
+	GetApp(id string) (*models.App, error)
+	CreateApp(app *models.App) error
+	DeleteApp(id string) (*models.AppDeleteResponse, error)
+
+	// Batches
+	// ApplyBatch creates and updates routers, services and middlewares in one transaction
+	ApplyBatch(batch *Batch) error
+
+	// Dependencies
+	// Graph returns the references between routers, services and middlewares
+	Graph() (*models.Graph, error)
+
+	// Namespaces
+	// Namespace returns a view of the store limited to one namespace, using local IDs
+	Namespace(name string) Store
+	// WithActor returns a view of the store that attributes changes to the actor
+	WithActor(actor string) Store
+
 	// Persistence
 	Save() error
 	Load() error
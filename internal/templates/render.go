@@ -0,0 +1,298 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+var (
+	// placeholderPattern matches the ${name} placeholders of string values
+	placeholderPattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+	// parameterNamePattern matches valid parameter names
+	parameterNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// hostnamePattern matches DNS names, optionally starting with a wildcard label
+	hostnamePattern = regexp.MustCompile(`^(\*\.)?([A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.)*[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+)
+
+// Validate checks the parameters of a template and the IDs and placeholders of its components
+func Validate(template models.Template) error {
+	parameters := make(map[string]bool, len(template.Parameters))
+	for _, param := range template.Parameters {
+		if !parameterNamePattern.MatchString(param.Name) {
+			return fmt.Errorf("invalid parameter name %q", param.Name)
+		}
+		if parameters[param.Name] {
+			return fmt.Errorf("duplicate parameter %q", param.Name)
+		}
+		parameters[param.Name] = true
+
+		if err := validateParameter(param); err != nil {
+			return fmt.Errorf("parameter %s: %w", param.Name, err)
+		}
+	}
+
+	if len(template.Routers) == 0 {
+		return errors.New("a template requires at least one router")
+	}
+
+	for _, group := range []struct {
+		resourceType string
+		components   []map[string]interface{}
+	}{
+		{"router", template.Routers},
+		{"service", template.Services},
+		{"middleware", template.Middlewares},
+	} {
+		ids := make(map[string]bool, len(group.components))
+		for _, component := range group.components {
+			id, _ := component["id"].(string)
+			if id == "" || placeholderPattern.MatchString(id) {
+				return fmt.Errorf("every %s requires a literal id", group.resourceType)
+			}
+			if ids[id] {
+				return fmt.Errorf("duplicate %s %q", group.resourceType, id)
+			}
+			ids[id] = true
+
+			err := walkStrings(component, func(s string) error {
+				for _, match := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+					if !parameters[match[1]] {
+						return fmt.Errorf("%s %s uses undeclared parameter %q", group.resourceType, id, match[1])
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateParameter checks the type, constraints and default of a parameter
+func validateParameter(param models.TemplateParameter) error {
+	switch param.Type {
+	case models.ParameterString, models.ParameterURL, models.ParameterHostname:
+		if param.Minimum != nil || param.Maximum != nil {
+			return errors.New("minimum and maximum only apply to integer and number parameters")
+		}
+		if param.Pattern != "" {
+			if _, err := regexp.Compile(param.Pattern); err != nil {
+				return fmt.Errorf("invalid pattern: %w", err)
+			}
+		}
+	case models.ParameterInteger, models.ParameterNumber:
+		if param.Pattern != "" {
+			return errors.New("pattern only applies to string, url and hostname parameters")
+		}
+		if param.Minimum != nil && param.Maximum != nil && *param.Minimum > *param.Maximum {
+			return errors.New("minimum is greater than maximum")
+		}
+	case models.ParameterBoolean:
+		if param.Pattern != "" || param.Minimum != nil || param.Maximum != nil {
+			return errors.New("boolean parameters take no constraints")
+		}
+	default:
+		return fmt.Errorf("invalid type %q, expected one of: string, integer, number, boolean, url, hostname", param.Type)
+	}
+
+	if param.Default != nil {
+		if _, err := convert(param, param.Default); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+	return nil
+}
+
+// Render returns the components of a template with its placeholders replaced by the given values.
+// A placeholder making up a whole string is replaced by the typed value, e.g. a number,
+// while placeholders within a string are replaced by the value's text.
+func Render(template models.Template, values map[string]interface{}) (*models.Template, error) {
+	resolved := make(map[string]interface{}, len(template.Parameters))
+	for _, param := range template.Parameters {
+		value, ok := values[param.Name]
+		if !ok || value == nil {
+			value = param.Default
+		}
+		if value == nil {
+			return nil, fmt.Errorf("parameter %s is required", param.Name)
+		}
+
+		converted, err := convert(param, value)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", param.Name, err)
+		}
+		resolved[param.Name] = converted
+	}
+	for name := range values {
+		if _, ok := resolved[name]; !ok {
+			return nil, fmt.Errorf("unknown parameter %s", name)
+		}
+	}
+
+	rendered := template
+	rendered.Routers = substituteAll(template.Routers, resolved)
+	rendered.Services = substituteAll(template.Services, resolved)
+	rendered.Middlewares = substituteAll(template.Middlewares, resolved)
+	return &rendered, nil
+}
+
+// convert checks a value against the type and constraints of a parameter and
+// returns it as string, int64, float64 or bool
+func convert(param models.TemplateParameter, value interface{}) (interface{}, error) {
+	switch param.Type {
+	case models.ParameterString, models.ParameterURL, models.ParameterHostname:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be a string")
+		}
+		if param.Type == models.ParameterURL {
+			u, err := url.Parse(s)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, errors.New("must be an http or https URL")
+			}
+		}
+		if param.Type == models.ParameterHostname && !hostnamePattern.MatchString(s) {
+			return nil, errors.New("must be a hostname")
+		}
+		if param.Pattern != "" {
+			if matched, _ := regexp.MatchString("^(?:"+param.Pattern+")$", s); !matched {
+				return nil, fmt.Errorf("must match %s", param.Pattern)
+			}
+		}
+		return s, nil
+
+	case models.ParameterInteger, models.ParameterNumber:
+		n, ok := number(value)
+		if !ok {
+			return nil, errors.New("must be a number")
+		}
+		if param.Type == models.ParameterInteger && n != math.Trunc(n) {
+			return nil, errors.New("must be an integer")
+		}
+		if param.Minimum != nil && n < *param.Minimum {
+			return nil, fmt.Errorf("must be at least %s", formatNumber(*param.Minimum))
+		}
+		if param.Maximum != nil && n > *param.Maximum {
+			return nil, fmt.Errorf("must be at most %s", formatNumber(*param.Maximum))
+		}
+		if param.Type == models.ParameterInteger {
+			return int64(n), nil
+		}
+		return n, nil
+
+	case models.ParameterBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, errors.New("must be a boolean")
+		}
+		return b, nil
+	}
+
+	return nil, fmt.Errorf("invalid type %q", param.Type)
+}
+
+// number returns the numeric value of a decoded JSON number
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// formatNumber formats a number without a trailing fraction
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// format returns the text of a converted parameter value
+func format(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return formatNumber(v)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(value)
+}
+
+// substituteAll returns copies of the components with their placeholders replaced
+func substituteAll(components []map[string]interface{}, values map[string]interface{}) []map[string]interface{} {
+	if components == nil {
+		return nil
+	}
+	result := make([]map[string]interface{}, len(components))
+	for i, component := range components {
+		result[i] = substitute(component, values).(map[string]interface{})
+	}
+	return result
+}
+
+// substitute returns a copy of a decoded JSON value with its placeholders replaced
+func substitute(value interface{}, values map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if match := placeholderPattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			return values[match[1]]
+		}
+		return placeholderPattern.ReplaceAllStringFunc(v, func(placeholder string) string {
+			return format(values[strings.TrimSuffix(strings.TrimPrefix(placeholder, "${"), "}")])
+		})
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = substitute(item, values)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = substitute(item, values)
+		}
+		return result
+	}
+	return value
+}
+
+// walkStrings calls fn for every string of a decoded JSON value
+func walkStrings(value interface{}, fn func(s string) error) error {
+	switch v := value.(type) {
+	case string:
+		return fn(v)
+	case map[string]interface{}:
+		for _, item := range v {
+			if err := walkStrings(item, fn); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := walkStrings(item, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package templates
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+// publicAPI is a template of a public HTTPS API with rate limit and auth
+const publicAPI = `{
+	"id": "public-api",
+	"parameters": [
+		{"name": "host", "type": "hostname"},
+		{"name": "backendURL", "type": "url"},
+		{"name": "rps", "type": "integer", "default": 100, "minimum": 1, "maximum": 10000}
+	],
+	"routers": [{"id": "api", "rule": "Host(` + "`${host}`" + `)", "service": "api", "middlewares": ["ratelimit", "auth"], "tls": {}}],
+	"services": [{"id": "api", "url": "${backendURL}"}],
+	"middlewares": [
+		{"id": "ratelimit", "type": "rateLimit", "config": {"average": "${rps}", "burst": "${rps}"}},
+		{"id": "auth", "type": "basicAuth", "config": {"users": ["admin:x"]}}
+	]
+}`
+
+func parseTemplate(t *testing.T, data string) models.Template {
+	t.Helper()
+	var template models.Template
+	if err := json.Unmarshal([]byte(data), &template); err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+	return template
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(parseTemplate(t, publicAPI)); err != nil {
+		t.Fatalf("Expected template to be valid, got %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		old, new string
+		expected string
+	}{
+		{"Invalid Parameter Name", `"name": "host"`, `"name": "the host"`, "invalid parameter name"},
+		{"Invalid Type", `"type": "hostname"`, `"type": "ip"`, "invalid type"},
+		{"Invalid Default", `"default": 100`, `"default": 0`, "invalid default"},
+		{"Pattern On Integer", `"minimum": 1`, `"pattern": "[0-9]+"`, "pattern only applies"},
+		{"Undeclared Parameter", "${host}", "${hostname}", `undeclared parameter "hostname"`},
+		{"Placeholder ID", `{"id": "api", "url"`, `{"id": "${host}", "url"`, "literal id"},
+		{"Duplicate Middleware", `{"id": "auth"`, `{"id": "ratelimit"`, `duplicate middleware "ratelimit"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(parseTemplate(t, strings.Replace(publicAPI, tt.old, tt.new, 1)))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	template := parseTemplate(t, publicAPI)
+
+	t.Run("Typed Substitution", func(t *testing.T) {
+		rendered, err := Render(template, map[string]interface{}{
+			"host":       "api.example.com",
+			"backendURL": "http://api:8080",
+		})
+		if err != nil {
+			t.Fatalf("Failed to render template: %v", err)
+		}
+
+		if rule := rendered.Routers[0]["rule"]; rule != "Host(`api.example.com`)" {
+			t.Errorf("Expected inline placeholder to be replaced, got %v", rule)
+		}
+		if url := rendered.Services[0]["url"]; url != "http://api:8080" {
+			t.Errorf("Expected URL to be replaced, got %v", url)
+		}
+		// A whole-string placeholder keeps the parameter type, here the default
+		config := rendered.Middlewares[0]["config"].(map[string]interface{})
+		if config["average"] != int64(100) || config["burst"] != int64(100) {
+			t.Errorf("Expected integer default, got %#v", config)
+		}
+		// The template itself is left unchanged
+		if template.Services[0]["url"] != "${backendURL}" {
+			t.Errorf("Expected template to be unchanged, got %v", template.Services[0]["url"])
+		}
+	})
+
+	t.Run("Invalid Values", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			values   map[string]interface{}
+			expected string
+		}{
+			{"Missing Required", map[string]interface{}{"host": "api.example.com"}, "backendURL is required"},
+			{"Unknown Parameter", map[string]interface{}{"host": "api.example.com", "backendURL": "http://api", "port": 80.0}, "unknown parameter port"},
+			{"Invalid Hostname", map[string]interface{}{"host": "api example", "backendURL": "http://api"}, "must be a hostname"},
+			{"Invalid URL", map[string]interface{}{"host": "api.example.com", "backendURL": "api:8080"}, "must be an http or https URL"},
+			{"Fractional Integer", map[string]interface{}{"host": "api.example.com", "backendURL": "http://api", "rps": 1.5}, "must be an integer"},
+			{"Above Maximum", map[string]interface{}{"host": "api.example.com", "backendURL": "http://api", "rps": 20000.0}, "must be at most 10000"},
+			{"Wrong Type", map[string]interface{}{"host": "api.example.com", "backendURL": "http://api", "rps": "100"}, "must be a number"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := Render(template, tt.values)
+				if err == nil || !strings.Contains(err.Error(), tt.expected) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expected, err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,168 @@
+// Package templates manages parameterized bundles of routers, services and middlewares
+// and renders them with typed parameter values.
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// Store manages templates persisted in a JSON file
+type Store struct {
+	mu        sync.RWMutex
+	filePath  string
+	templates map[string]models.Template // by ID
+}
+
+// NewStore creates a new Store, loading existing templates from filePath
+func NewStore(filePath string) (*Store, error) {
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	s := &Store{
+		filePath:  filePath,
+		templates: make(map[string]models.Template),
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read templates file: %w", err)
+	}
+
+	var templates []models.Template
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("failed to parse templates file: %w", err)
+	}
+	for _, template := range templates {
+		s.templates[template.ID] = template
+	}
+
+	return s, nil
+}
+
+// List returns all templates sorted by ID
+func (s *Store) List() []models.Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	templates := make([]models.Template, 0, len(s.templates))
+	for _, template := range s.templates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+	return templates
+}
+
+// Get returns the template with the given ID
+func (s *Store) Get(id string) (*models.Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	template, ok := s.templates[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return &template, nil
+}
+
+// Create stores a new template
+func (s *Store) Create(template *models.Template) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.templates[template.ID]; ok {
+		return store.ErrAlreadyExists
+	}
+
+	template.CreatedAt = time.Now().UTC()
+	template.UpdatedAt = template.CreatedAt
+	s.templates[template.ID] = *template
+
+	if err := s.save(); err != nil {
+		delete(s.templates, template.ID)
+		return err
+	}
+	return nil
+}
+
+// Update replaces the parameters and components of a template.
+// Apps instantiated from it before are left unchanged.
+func (s *Store) Update(id string, template *models.Template) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.templates[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+
+	template.ID = id
+	template.CreatedAt = existing.CreatedAt
+	template.UpdatedAt = time.Now().UTC()
+	s.templates[id] = *template
+
+	if err := s.save(); err != nil {
+		s.templates[id] = existing
+		return err
+	}
+	return nil
+}
+
+// Delete removes the template with the given ID
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	template, ok := s.templates[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+
+	delete(s.templates, id)
+
+	if err := s.save(); err != nil {
+		s.templates[id] = template
+		return err
+	}
+	return nil
+}
+
+// save writes all templates to the file, must be called with the lock held
+func (s *Store) save() error {
+	templates := make([]models.Template, 0, len(s.templates))
+	for _, template := range s.templates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+
+	data, err := json.MarshalIndent(templates, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal templates: %w", err)
+	}
+
+	tempFile := s.filePath + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write templates file: %w", err)
+	}
+	if err := os.Rename(tempFile, s.filePath); err != nil {
+		return fmt.Errorf("failed to rename templates file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Package tlsconfig builds server TLS configurations from certificate files
+// that are reloaded when they change.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/logger"
+)
+
+// nextProtos enables HTTP/2 next to HTTP/1.1
+var nextProtos = []string{"h2", "http/1.1"}
+
+// ParseVersion converts a TLS version like "1.2" to its tls constant
+func ParseVersion(version string) (uint16, error) {
+	switch version {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS version %q, expected 1.0, 1.1, 1.2 or 1.3", version)
+}
+
+// Reloader holds a certificate and an optional client CA pool loaded from files.
+// New connections always use the most recently loaded files.
+type Reloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+}
+
+// NewReloader loads the certificate, key and optional client CA files
+func NewReloader(certFile, keyFile, clientCAFile string) (*Reloader, error) {
+	r := &Reloader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the files again. On error the previously loaded files stay in use.
+func (r *Reloader) Reload() error {
+	modTimes := r.currentModTimes()
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		data, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(data) {
+			return fmt.Errorf("no certificates found in client CA file %s", r.clientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	r.mu.Unlock()
+
+	logger.Info().Str("cert", r.certFile).Msg("TLS certificates loaded")
+	return nil
+}
+
+// ReloadIfChanged reloads the files if any of them was modified since the last load
+func (r *Reloader) ReloadIfChanged() (bool, error) {
+	modTimes := r.currentModTimes()
+
+	r.mu.RLock()
+	changed := false
+	for file, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[file]) {
+			changed = true
+		}
+	}
+	r.mu.RUnlock()
+
+	if !changed {
+		return false, nil
+	}
+	return true, r.Reload()
+}
+
+// Watch checks the files for changes every interval until done is closed
+func (r *Reloader) Watch(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := r.ReloadIfChanged(); err != nil {
+				logger.Error().Err(err).Msg("Failed to reload TLS certificates")
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// TLSConfig returns a server configuration using the current files for every new connection
+func (r *Reloader) TLSConfig(minVersion uint16, clientAuth tls.ClientAuthType) *tls.Config {
+	return &tls.Config{
+		MinVersion: minVersion,
+		ClientAuth: clientAuth,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return &tls.Config{
+				MinVersion:   minVersion,
+				ClientAuth:   clientAuth,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientCAs:    r.clientCAs,
+			}, nil
+		},
+	}
+}
+
+// currentModTimes returns the modification times of the files
+func (r *Reloader) currentModTimes() map[string]time.Time {
+	modTimes := make(map[string]time.Time, 3)
+	for _, file := range []string{r.certFile, r.keyFile, r.clientCAFile} {
+		if file == "" {
+			continue
+		}
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime()
+		}
+	}
+	return modTimes
+}
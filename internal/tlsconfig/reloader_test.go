@@ -0,0 +1,122 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCertificate writes a self-signed certificate with the given serial number and its key
+func writeCertificate(t *testing.T, certFile, keyFile string, serial int64) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "traefik-manager"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+}
+
+// servedSerial returns the serial number of the certificate served for a new connection
+func servedSerial(t *testing.T, config *tls.Config) int64 {
+	connConfig, err := config.GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("Failed to get connection config: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(connConfig.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatalf("Failed to parse served certificate: %v", err)
+	}
+	return leaf.SerialNumber.Int64()
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	writeCertificate(t, certFile, keyFile, 1)
+
+	reloader, err := NewReloader(certFile, keyFile, certFile)
+	if err != nil {
+		t.Fatalf("Failed to create reloader: %v", err)
+	}
+
+	config := reloader.TLSConfig(tls.VersionTLS12, tls.VerifyClientCertIfGiven)
+
+	t.Run("Serves Loaded Certificate", func(t *testing.T) {
+		if serial := servedSerial(t, config); serial != 1 {
+			t.Fatalf("Expected serial 1, got %d", serial)
+		}
+		if changed, err := reloader.ReloadIfChanged(); changed || err != nil {
+			t.Fatalf("Expected no reload without changes, got %v, %v", changed, err)
+		}
+	})
+
+	t.Run("Reloads Changed Files", func(t *testing.T) {
+		writeCertificate(t, certFile, keyFile, 2)
+		// Make sure the modification time differs on coarse-grained file systems
+		future := time.Now().Add(time.Minute)
+		os.Chtimes(certFile, future, future)
+
+		changed, err := reloader.ReloadIfChanged()
+		if err != nil || !changed {
+			t.Fatalf("Expected reload, got %v, %v", changed, err)
+		}
+		if serial := servedSerial(t, config); serial != 2 {
+			t.Fatalf("Expected serial 2 after reload, got %d", serial)
+		}
+	})
+
+	t.Run("Keeps Certificate On Invalid Files", func(t *testing.T) {
+		if err := os.WriteFile(keyFile, []byte("invalid"), 0600); err != nil {
+			t.Fatalf("Failed to write key: %v", err)
+		}
+		if err := reloader.Reload(); err == nil {
+			t.Fatalf("Expected error for invalid key")
+		}
+		if serial := servedSerial(t, config); serial != 2 {
+			t.Fatalf("Expected previous certificate to stay in use, got serial %d", serial)
+		}
+	})
+
+	t.Run("Parse Version", func(t *testing.T) {
+		if version, err := ParseVersion("1.3"); err != nil || version != tls.VersionTLS13 {
+			t.Fatalf("Expected TLS 1.3, got %v, %v", version, err)
+		}
+		if _, err := ParseVersion("2.0"); err == nil {
+			t.Fatalf("Expected error for unsupported version")
+		}
+	})
+}
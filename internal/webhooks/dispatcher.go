@@ -0,0 +1,440 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/logger"
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderWebhookID = "X-Webhook-ID"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderEvent     = "X-Webhook-Event"
+	// HeaderSignature holds "sha256=" followed by the hex HMAC-SHA256 of the body keyed with the webhook secret
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxConcurrentAttempts limits the number of deliveries sent at the same time
+const maxConcurrentAttempts = 8
+
+// Options configures the delivery of webhooks
+type Options struct {
+	// MaxAttempts is the number of attempts before a delivery is moved to the dead-letter list
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, doubled for every further retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration
+	// Timeout of a single attempt
+	Timeout time.Duration
+	// HistorySize is the number of recent deliveries kept per webhook
+	HistorySize int
+	// DeadLetterSize is the number of dead deliveries kept per webhook
+	DeadLetterSize int
+}
+
+// Dispatcher delivers change events to the matching webhooks, retrying failed deliveries
+type Dispatcher struct {
+	webhooks *Store
+	client   *http.Client
+	opts     Options
+
+	mu          sync.Mutex
+	queue       []*models.WebhookDelivery            // deliveries waiting for an attempt
+	deliveries  map[string][]*models.WebhookDelivery // recent deliveries by webhook, oldest first
+	deadLetters map[string][]*models.WebhookDelivery // dead deliveries by webhook, oldest first
+
+	wake chan struct{}
+	sem  chan struct{}
+}
+
+// NewDispatcher creates a Dispatcher delivering to the webhooks of the store
+func NewDispatcher(webhooks *Store, opts Options) *Dispatcher {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 1
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = time.Second
+	}
+	if opts.MaxBackoff < opts.InitialBackoff {
+		opts.MaxBackoff = opts.InitialBackoff
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.HistorySize <= 0 {
+		opts.HistorySize = 1
+	}
+	if opts.DeadLetterSize <= 0 {
+		opts.DeadLetterSize = 1
+	}
+
+	return &Dispatcher{
+		webhooks: webhooks,
+		client: &http.Client{
+			Timeout: opts.Timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		opts:        opts,
+		deliveries:  make(map[string][]*models.WebhookDelivery),
+		deadLetters: make(map[string][]*models.WebhookDelivery),
+		wake:        make(chan struct{}, 1),
+		sem:         make(chan struct{}, maxConcurrentAttempts),
+	}
+}
+
+// Webhooks returns the store of registered webhooks
+func (d *Dispatcher) Webhooks() *Store {
+	return d.webhooks
+}
+
+// Notify returns a change hook queueing deliveries for the changes of an environment.
+// The hook doesn't block, so it can be registered with store.ChangeNotifier.OnChange.
+func (d *Dispatcher) Notify(environment string) func(models.ChangeEvent) {
+	return func(change models.ChangeEvent) {
+		event := EventName(change)
+
+		var queued []*models.WebhookDelivery
+		for _, webhook := range d.webhooks.List() {
+			if !Matches(webhook, environment, change) {
+				continue
+			}
+			id, err := generateToken("", 8)
+			if err != nil {
+				logger.Error().Err(err).Str("webhook", webhook.ID).Msg("Failed to queue webhook delivery")
+				continue
+			}
+			now := time.Now().UTC()
+			queued = append(queued, &models.WebhookDelivery{
+				ID:          id,
+				WebhookID:   webhook.ID,
+				Event:       event,
+				Environment: environment,
+				Change:      change,
+				Status:      models.DeliveryPending,
+				CreatedAt:   now,
+				NextAttempt: &now,
+			})
+		}
+		if len(queued) == 0 {
+			return
+		}
+
+		d.mu.Lock()
+		for _, delivery := range queued {
+			d.deliveries[delivery.WebhookID] = appendCapped(d.deliveries[delivery.WebhookID], delivery, d.opts.HistorySize)
+			d.queue = append(d.queue, delivery)
+		}
+		d.mu.Unlock()
+		d.signal()
+	}
+}
+
+// Run sends queued deliveries until done is closed
+func (d *Dispatcher) Run(done <-chan struct{}) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		due, next := d.takeDue(time.Now())
+		for _, delivery := range due {
+			go d.attempt(delivery)
+		}
+
+		wait := time.Hour
+		if !next.IsZero() {
+			wait = time.Until(next)
+		}
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+
+		select {
+		case <-done:
+			return
+		case <-d.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// Deliveries returns the recent deliveries of a webhook, newest first.
+// With status "dead" the dead-letter list is returned, other statuses filter the recent deliveries.
+func (d *Dispatcher) Deliveries(webhookID, status string) []models.WebhookDelivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	list := d.deliveries[webhookID]
+	if status == models.DeliveryDead {
+		list = d.deadLetters[webhookID]
+	}
+
+	result := make([]models.WebhookDelivery, 0, len(list))
+	for i := len(list) - 1; i >= 0; i-- {
+		if status != "" && list[i].Status != status {
+			continue
+		}
+		result = append(result, *list[i])
+	}
+	return result
+}
+
+// Redeliver moves a delivery from the dead-letter list back to the queue with fresh attempts
+func (d *Dispatcher) Redeliver(webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dead := d.deadLetters[webhookID]
+	for i, delivery := range dead {
+		if delivery.ID != deliveryID {
+			continue
+		}
+
+		d.deadLetters[webhookID] = append(dead[:i:i], dead[i+1:]...)
+
+		now := time.Now().UTC()
+		delivery.Status = models.DeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttempt = &now
+		if !contains(d.deliveries[webhookID], delivery) {
+			d.deliveries[webhookID] = appendCapped(d.deliveries[webhookID], delivery, d.opts.HistorySize)
+		}
+		d.queue = append(d.queue, delivery)
+		d.signal()
+
+		result := *delivery
+		return &result, nil
+	}
+	return nil, store.ErrNotFound
+}
+
+// Forget drops the deliveries of a deleted webhook
+func (d *Dispatcher) Forget(webhookID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.deliveries, webhookID)
+	delete(d.deadLetters, webhookID)
+}
+
+// takeDue removes the deliveries due at now from the queue and returns them
+// with the time the next remaining delivery is due, zero if none is left
+func (d *Dispatcher) takeDue(now time.Time) ([]*models.WebhookDelivery, time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var due []*models.WebhookDelivery
+	var next time.Time
+	remaining := d.queue[:0]
+	for _, delivery := range d.queue {
+		if !delivery.NextAttempt.After(now) {
+			due = append(due, delivery)
+			continue
+		}
+		remaining = append(remaining, delivery)
+		if next.IsZero() || delivery.NextAttempt.Before(next) {
+			next = *delivery.NextAttempt
+		}
+	}
+	d.queue = remaining
+	return due, next
+}
+
+// attempt sends a delivery once and records the outcome
+func (d *Dispatcher) attempt(delivery *models.WebhookDelivery) {
+	d.sem <- struct{}{}
+	defer func() { <-d.sem }()
+
+	d.mu.Lock()
+	payload := models.WebhookPayload{
+		DeliveryID:  delivery.ID,
+		WebhookID:   delivery.WebhookID,
+		Event:       delivery.Event,
+		Environment: delivery.Environment,
+		Change:      delivery.Change,
+		Text:        summary(delivery.Change, delivery.Environment),
+	}
+	d.mu.Unlock()
+
+	webhook, err := d.webhooks.Get(payload.WebhookID)
+	secret, ok := d.webhooks.secret(payload.WebhookID)
+	if err != nil || !ok {
+		// The webhook was deleted while the delivery was queued
+		return
+	}
+
+	statusCode, err := d.send(webhook.URL, secret, payload)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.LastAttempt = &now
+	delivery.StatusCode = statusCode
+	delivery.Error = ""
+	delivery.NextAttempt = nil
+
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliverySucceeded
+	case delivery.Attempts >= d.opts.MaxAttempts:
+		delivery.Status = models.DeliveryDead
+		delivery.Error = err.Error()
+		d.deadLetters[delivery.WebhookID] = appendCapped(d.deadLetters[delivery.WebhookID], delivery, d.opts.DeadLetterSize)
+		logger.Warn().Err(err).Str("webhook", delivery.WebhookID).Str("delivery", delivery.ID).
+			Int("attempts", delivery.Attempts).Msg("Webhook delivery moved to dead-letter list")
+	default:
+		next := now.Add(d.backoff(delivery.Attempts))
+		delivery.Error = err.Error()
+		delivery.NextAttempt = &next
+		d.queue = append(d.queue, delivery)
+		d.signal()
+		logger.Debug().Err(err).Str("webhook", delivery.WebhookID).Str("delivery", delivery.ID).
+			Time("next", next).Msg("Webhook delivery failed, retrying")
+	}
+}
+
+// send posts a signed payload and returns the response status
+func (d *Dispatcher) send(url, secret string, payload models.WebhookPayload) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "traefik-manager-webhooks")
+	req.Header.Set(HeaderWebhookID, payload.WebhookID)
+	req.Header.Set(HeaderDelivery, payload.DeliveryID)
+	req.Header.Set(HeaderEvent, payload.Event)
+	req.Header.Set(HeaderSignature, Sign(secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay after the given number of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.opts.InitialBackoff
+	for i := 1; i < attempts && delay < d.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.opts.MaxBackoff {
+		delay = d.opts.MaxBackoff
+	}
+	return delay
+}
+
+// signal wakes up Run without blocking
+func (d *Dispatcher) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Sign returns the signature header value of a body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// EventName returns the name webhook filters are matched against, e.g. "router.created"
+func EventName(change models.ChangeEvent) string {
+	return strings.TrimSuffix(change.Type, "s") + "." + change.Action
+}
+
+// ValidPattern returns true if the event pattern is well-formed
+func ValidPattern(pattern string) bool {
+	_, err := path.Match(pattern, "")
+	return pattern != "" && err == nil
+}
+
+// Matches returns true if a change in the environment is delivered to the webhook
+func Matches(webhook models.Webhook, environment string, change models.ChangeEvent) bool {
+	if webhook.Environment != "" && webhook.Environment != environment {
+		return false
+	}
+	if webhook.Namespace != "" {
+		if namespace, _ := store.SplitQualifiedID(change.ID); namespace != webhook.Namespace {
+			return false
+		}
+	}
+	if len(webhook.Events) == 0 {
+		return true
+	}
+
+	event := EventName(change)
+	for _, pattern := range webhook.Events {
+		if ok, _ := path.Match(pattern, event); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// summary describes a change in a sentence
+func summary(change models.ChangeEvent, environment string) string {
+	actor := change.Actor
+	if actor == "" {
+		actor = "Someone"
+	}
+	return fmt.Sprintf("%s %s %s %q in %s", actor, change.Action, strings.TrimSuffix(change.Type, "s"), change.ID, environment)
+}
+
+// appendCapped appends a delivery, dropping the oldest ones beyond size
+func appendCapped(list []*models.WebhookDelivery, delivery *models.WebhookDelivery, size int) []*models.WebhookDelivery {
+	list = append(list, delivery)
+	if len(list) > size {
+		list = append(list[:0:0], list[len(list)-size:]...)
+	}
+	return list
+}
+
+// contains returns true if the list holds the delivery
+func contains(list []*models.WebhookDelivery, delivery *models.WebhookDelivery) bool {
+	for _, item := range list {
+		if item == delivery {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+)
+
+func TestMatches(t *testing.T) {
+	change := models.ChangeEvent{Action: models.ChangeUpdated, Type: "routers", ID: "team-a/api"}
+
+	tests := []struct {
+		name     string
+		webhook  models.Webhook
+		expected bool
+	}{
+		{"No Filters", models.Webhook{}, true},
+		{"Type Pattern", models.Webhook{Events: []string{"router.*"}}, true},
+		{"Action Pattern", models.Webhook{Events: []string{"*.deleted"}}, false},
+		{"Any Pattern Matches", models.Webhook{Events: []string{"service.*", "router.updated"}}, true},
+		{"Namespace", models.Webhook{Namespace: "team-a"}, true},
+		{"Other Namespace", models.Webhook{Namespace: "team-b"}, false},
+		{"Environment", models.Webhook{Environment: "production"}, true},
+		{"Other Environment", models.Webhook{Environment: "staging"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Matches(tt.webhook, "production", change); got != tt.expected {
+				t.Fatalf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestStorePersistence(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "webhooks.json")
+
+	s, err := NewStore(filePath)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	webhook := &models.Webhook{URL: "https://hooks.example.com/a", Events: []string{"router.*"}}
+	secret, err := s.Create(webhook, "")
+	if err != nil {
+		t.Fatalf("Failed to create webhook: %v", err)
+	}
+	if webhook.ID == "" || secret == "" {
+		t.Fatalf("Expected generated ID and secret, got %q and %q", webhook.ID, secret)
+	}
+
+	reopened, err := NewStore(filePath)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	if got, ok := reopened.secret(webhook.ID); !ok || got != secret {
+		t.Fatalf("Expected secret to be persisted, got %q", got)
+	}
+	if list := reopened.List(); len(list) != 1 || list[0].URL != webhook.URL {
+		t.Fatalf("Expected persisted webhook, got %+v", list)
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	var mu sync.Mutex
+	failing := true
+	var received []*http.Request
+	var bodies [][]byte
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, r)
+		bodies = append(bodies, body)
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer target.Close()
+
+	s, err := NewStore(filepath.Join(t.TempDir(), "webhooks.json"))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	webhook := &models.Webhook{URL: target.URL, Events: []string{"router.*"}}
+	secret, err := s.Create(webhook, "test-secret")
+	if err != nil {
+		t.Fatalf("Failed to create webhook: %v", err)
+	}
+
+	d := NewDispatcher(s, Options{
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     20 * time.Millisecond,
+		Timeout:        time.Second,
+		HistorySize:    10,
+		DeadLetterSize: 10,
+	})
+	done := make(chan struct{})
+	defer close(done)
+	go d.Run(done)
+
+	notify := d.Notify("default")
+	notify(models.ChangeEvent{Version: 1, Action: models.ChangeCreated, Type: "services", ID: "api"})
+	notify(models.ChangeEvent{Version: 2, Action: models.ChangeUpdated, Type: "routers", ID: "api", Actor: "alice"})
+
+	waitFor := func(t *testing.T, status string) models.WebhookDelivery {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if deliveries := d.Deliveries(webhook.ID, status); len(deliveries) == 1 {
+				return deliveries[0]
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatalf("Timed out waiting for a %s delivery", status)
+		return models.WebhookDelivery{}
+	}
+
+	var dead models.WebhookDelivery
+	t.Run("Dead Letter After Retries", func(t *testing.T) {
+		dead = waitFor(t, models.DeliveryDead)
+		if dead.Attempts != 3 || dead.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("Expected 3 attempts ending in 503, got %d and %d", dead.Attempts, dead.StatusCode)
+		}
+		if dead.Event != "router.updated" {
+			t.Fatalf("Expected only the router change to be delivered, got %s", dead.Event)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(received) != 3 {
+			t.Fatalf("Expected 3 requests, got %d", len(received))
+		}
+	})
+
+	t.Run("Signed Payload", func(t *testing.T) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		req, body := received[0], bodies[0]
+		if got, expected := req.Header.Get(HeaderSignature), Sign(secret, body); got != expected {
+			t.Fatalf("Expected signature %s, got %s", expected, got)
+		}
+		if req.Header.Get(HeaderEvent) != "router.updated" || req.Header.Get(HeaderWebhookID) != webhook.ID {
+			t.Fatalf("Unexpected headers: %v", req.Header)
+		}
+
+		var payload models.WebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("Invalid payload: %v", err)
+		}
+		if payload.Change.Actor != "alice" || payload.Text != `alice updated router "api" in default` {
+			t.Fatalf("Unexpected payload: %+v", payload)
+		}
+	})
+
+	t.Run("Redeliver", func(t *testing.T) {
+		mu.Lock()
+		failing = false
+		mu.Unlock()
+
+		if _, err := d.Redeliver(webhook.ID, dead.ID); err != nil {
+			t.Fatalf("Failed to redeliver: %v", err)
+		}
+		delivered := waitFor(t, models.DeliverySucceeded)
+		if delivered.ID != dead.ID || delivered.Attempts != 1 {
+			t.Fatalf("Expected redelivered delivery with one attempt, got %+v", delivered)
+		}
+		if len(d.Deliveries(webhook.ID, models.DeliveryDead)) != 0 {
+			t.Fatal("Expected dead-letter list to be empty")
+		}
+
+		if _, err := d.Redeliver(webhook.ID, dead.ID); err == nil {
+			t.Fatal("Expected error redelivering a delivery that isn't dead")
+		}
+	})
+}
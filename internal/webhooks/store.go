@@ -0,0 +1,207 @@
+// Package webhooks manages webhook registrations and delivers signed change
+// notifications to them.
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sistemica/traefik-manager/internal/models"
+	"github.com/sistemica/traefik-manager/internal/store"
+)
+
+// Prefixes of generated identifiers and secrets so they are easy to recognize
+const (
+	idPrefix     = "wh_"
+	secretPrefix = "whsec_"
+)
+
+// storedWebhook is the persisted form of a webhook, including its signing secret
+type storedWebhook struct {
+	models.Webhook
+	Secret string `json:"secret"`
+}
+
+// Store manages webhooks persisted in a JSON file
+type Store struct {
+	mu       sync.RWMutex
+	filePath string
+	webhooks map[string]storedWebhook // by ID
+}
+
+// NewStore creates a new Store, loading existing webhooks from filePath
+func NewStore(filePath string) (*Store, error) {
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	s := &Store{
+		filePath: filePath,
+		webhooks: make(map[string]storedWebhook),
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read webhooks file: %w", err)
+	}
+
+	var webhooks []storedWebhook
+	if err := json.Unmarshal(data, &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to parse webhooks file: %w", err)
+	}
+	for _, webhook := range webhooks {
+		s.webhooks[webhook.ID] = webhook
+	}
+
+	return s, nil
+}
+
+// List returns all webhooks sorted by ID
+func (s *Store) List() []models.Webhook {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	webhooks := make([]models.Webhook, 0, len(s.webhooks))
+	for _, webhook := range s.webhooks {
+		webhooks = append(webhooks, webhook.Webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].ID < webhooks[j].ID
+	})
+	return webhooks
+}
+
+// Get returns the webhook with the given ID
+func (s *Store) Get(id string) (*models.Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	webhook, ok := s.webhooks[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	result := webhook.Webhook
+	return &result, nil
+}
+
+// Create registers a new webhook. A secret is generated if none is given.
+// The secret is returned and cannot be retrieved again.
+func (s *Store) Create(webhook *models.Webhook, secret string) (string, error) {
+	id, err := generateToken(idPrefix, 8)
+	if err != nil {
+		return "", err
+	}
+	if secret == "" {
+		if secret, err = generateToken(secretPrefix, 24); err != nil {
+			return "", err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhook.ID = id
+	webhook.CreatedAt = time.Now().UTC()
+	s.webhooks[id] = storedWebhook{Webhook: *webhook, Secret: secret}
+
+	if err := s.save(); err != nil {
+		delete(s.webhooks, id)
+		return "", err
+	}
+
+	return secret, nil
+}
+
+// Update replaces the URL and filters of a webhook, keeping its secret
+func (s *Store) Update(id string, webhook *models.Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.webhooks[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+
+	webhook.ID = id
+	webhook.CreatedAt = existing.CreatedAt
+	s.webhooks[id] = storedWebhook{Webhook: *webhook, Secret: existing.Secret}
+
+	if err := s.save(); err != nil {
+		s.webhooks[id] = existing
+		return err
+	}
+	return nil
+}
+
+// Delete removes the webhook with the given ID
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhook, ok := s.webhooks[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+
+	delete(s.webhooks, id)
+
+	if err := s.save(); err != nil {
+		s.webhooks[id] = webhook
+		return err
+	}
+	return nil
+}
+
+// secret returns the signing secret of a webhook
+func (s *Store) secret(id string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	webhook, ok := s.webhooks[id]
+	return webhook.Secret, ok
+}
+
+// save writes all webhooks to the file, must be called with the lock held
+func (s *Store) save() error {
+	webhooks := make([]storedWebhook, 0, len(s.webhooks))
+	for _, webhook := range s.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].ID < webhooks[j].ID
+	})
+
+	data, err := json.MarshalIndent(webhooks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhooks: %w", err)
+	}
+
+	tempFile := s.filePath + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write webhooks file: %w", err)
+	}
+	if err := os.Rename(tempFile, s.filePath); err != nil {
+		return fmt.Errorf("failed to rename webhooks file: %w", err)
+	}
+	return nil
+}
+
+// generateToken creates a random hex token with the given prefix
+func generateToken(prefix string, size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return prefix + hex.EncodeToString(buf), nil
+}